@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKeyInterceptor는 로그인 없이 device api key로 rpc를 호출한다
+type APIKeyInterceptor struct {
+	apiKey string
+}
+
+func NewAPIKeyInterceptor(apiKey string) *APIKeyInterceptor {
+	return &APIKeyInterceptor{apiKey: apiKey}
+}
+
+func (i *APIKeyInterceptor) Unary() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+		return invoker(i.attachKey(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func (i *APIKeyInterceptor) Stream() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		return streamer(i.attachKey(ctx), desc, cc, method, opts...)
+	}
+}
+
+func (i *APIKeyInterceptor) attachKey(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "ApiKey "+i.apiKey)
+}
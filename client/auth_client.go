@@ -2,16 +2,19 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/JeongWoo-Seo/pcBook/pb"
 	"google.golang.org/grpc"
 )
 
+// AuthClient는 처음 로그인한 뒤 비밀번호를 지우고 refresh token으로만 access token을 갱신한다
 type AuthClient struct {
-	service  pb.AuthServiceClient
-	username string
-	password string
+	service      pb.AuthServiceClient
+	username     string
+	password     string
+	refreshToken string
 }
 
 func NewAuthClinet(cc *grpc.ClientConn, username, password string) *AuthClient {
@@ -32,6 +35,33 @@ func (client *AuthClient) Login() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	// 이 client는 TOTP code를 입력받을 수 없다
+	if res.GetTwoFactorRequired() {
+		return "", fmt.Errorf("user %s requires two-factor authentication", client.username)
+	}
+
+	client.password = ""
+	client.refreshToken = res.GetRefreshToken()
+	return res.GetAccessToken(), nil
+}
+
+// Refresh는 아직 로그인하지 않았으면 로그인하고, 이후에는 refresh token을 교체하며 access token을 받는다
+func (client *AuthClient) Refresh() (string, error) {
+	if client.refreshToken == "" {
+		if client.password == "" {
+			return "", fmt.Errorf("no refresh token or password to authenticate with")
+		}
+		return client.Login()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := client.service.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: client.refreshToken})
+	if err != nil {
+		return "", err
+	}
 
+	client.refreshToken = res.GetRefreshToken()
 	return res.GetAccessToken(), nil
 }
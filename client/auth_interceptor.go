@@ -91,11 +91,12 @@ func (i *AuthInterceptor) scheduleRefreshToken(refreshDuration time.Duration) er
 }
 
 func (i *AuthInterceptor) refreshToken() error {
-	accessToken, err := i.authClient.Login()
+	accessToken, err := i.authClient.Refresh()
 	if err != nil {
+		log.Printf("can not refresh token: %v", err)
 		return err
 	}
 	i.accessToken = accessToken
-	log.Printf("token refreshed: %v", accessToken)
+	log.Printf("token refreshed")
 	return nil
 }
@@ -1,8 +1,9 @@
 package client
 
 import (
-	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
@@ -25,6 +26,14 @@ func NewLaptopClient(cc *grpc.ClientConn) *LaptopClient {
 	return &LaptopClient{service: service}
 }
 
+const (
+	defaultChunkSize = 32 << 10
+	maxUploadRetries = 5
+	uploadRetryDelay = time.Second
+)
+
+// UploadImage는 업로드 세션을 열고 chunk 단위로 전송한다
+// 전송이 실패하면 서버에 기록된 offset을 조회해 그 위치부터 다시 보낸다
 func (laptopClient *LaptopClient) UploadImage(laptopID string, imagePath string) {
 	file, err := os.Open(imagePath)
 	if err != nil {
@@ -32,58 +41,126 @@ func (laptopClient *LaptopClient) UploadImage(laptopID string, imagePath string)
 	}
 	defer file.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	stat, err := file.Stat()
+	if err != nil {
+		log.Fatal("can not stat image file: ", err)
+	}
 
-	stream, err := laptopClient.service.UploadImage(ctx)
+	checksum, err := fileChecksum(file)
 	if err != nil {
-		log.Fatal("failed to upload file: ", err)
+		log.Fatal("can not compute image checksum: ", err)
 	}
 
-	req := &pb.UploadImageRequest{
-		Data: &pb.UploadImageRequest_Info{
-			Info: &pb.ImageInfo{
-				LaptopId:  laptopID,
-				ImageType: filepath.Ext(imagePath),
-			},
+	session, err := laptopClient.startImageUpload(&pb.StartImageUploadRequest{
+		Info: &pb.ImageInfo{
+			LaptopId:  laptopID,
+			ImageType: filepath.Ext(imagePath),
+			Checksum:  checksum,
 		},
+		TotalSize: uint32(stat.Size()),
+	})
+	if err != nil {
+		log.Fatal("can not start image upload: ", err)
 	}
 
-	err = stream.Send(req)
-	if err != nil {
-		log.Fatal("can not send image info: ", err)
+	chunkSize := session.GetChunkSize()
+	if chunkSize == 0 {
+		chunkSize = defaultChunkSize
 	}
 
-	reader := bufio.NewReader(file)
-	buffer := make([]byte, 1024)
+	uploadID := session.GetUploadId()
+	totalSize := uint32(stat.Size())
+	buffer := make([]byte, chunkSize)
 
-	for {
-		n, err := reader.Read(buffer)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
+	offset := uint32(0)
+	retries := 0
+
+	for offset < totalSize {
+		n, err := file.ReadAt(buffer, int64(offset))
+		if err != nil && err != io.EOF {
 			log.Fatal("can not read chunk to buff: ", err)
 		}
 
-		req := &pb.UploadImageRequest{
-			Data: &pb.UploadImageRequest_ChunkData{
-				ChunkData: buffer[:n],
-			},
+		res, err := laptopClient.uploadImageChunk(uploadID, offset, buffer[:n])
+		if err == nil {
+			offset = res.GetCommittedOffset()
+			retries = 0
+			continue
 		}
 
-		err = stream.Send(req)
-		if err != nil {
+		retries++
+		if retries > maxUploadRetries {
 			log.Fatal("can not send chunk to server: ", err)
 		}
+		log.Printf("chunk upload failed at offset %d, resuming: %v", offset, err)
+		time.Sleep(uploadRetryDelay)
+
+		committed, err := laptopClient.committedOffset(uploadID)
+		if err != nil {
+			log.Print("can not get upload status: ", err)
+			continue
+		}
+		offset = committed
 	}
 
-	res, err := stream.CloseAndRecv()
+	res, err := laptopClient.commitImageUpload(uploadID)
 	if err != nil {
-		log.Fatal("can not recieve res: ", err)
+		log.Fatal("can not commit image upload: ", err)
 	}
 
-	log.Printf("image upload with id: %s", res.GetId())
+	log.Printf("image upload with id: %s, digest: %s", res.GetId(), res.GetDigest())
+}
+
+func (laptopClient *LaptopClient) startImageUpload(req *pb.StartImageUploadRequest) (*pb.StartImageUploadResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return laptopClient.service.StartImageUpload(ctx, req)
+}
+
+func (laptopClient *LaptopClient) uploadImageChunk(uploadID string, offset uint32, chunk []byte) (*pb.UploadImageChunkResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req := &pb.UploadImageChunkRequest{
+		UploadId:  uploadID,
+		Offset:    offset,
+		ChunkData: chunk,
+	}
+	return laptopClient.service.UploadImageChunk(ctx, req)
+}
+
+func (laptopClient *LaptopClient) committedOffset(uploadID string) (uint32, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := laptopClient.service.GetImageUpload(ctx, &pb.GetImageUploadRequest{UploadId: uploadID})
+	if err != nil {
+		return 0, err
+	}
+	return res.GetCommittedOffset(), nil
+}
+
+func (laptopClient *LaptopClient) commitImageUpload(uploadID string) (*pb.UploadImageResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return laptopClient.service.CommitImageUpload(ctx, &pb.CommitImageUploadRequest{UploadId: uploadID})
+}
+
+func fileChecksum(file *os.File) (string, error) {
+	hasher := sha256.New()
+	_, err := io.Copy(hasher, file)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
 func (laptopClient *LaptopClient) CreateLaptop(laptop *pb.Laptop) {
@@ -163,6 +240,10 @@ func (laptopClient *LaptopClient) RatingLaptop(laptopIDs []string, scores []floa
 				waitResponse <- fmt.Errorf("can not recieve stream res: %v", err)
 				return
 			}
+			if res.GetError() != nil {
+				log.Printf("rating rejected for laptop %s: %s", res.GetLaptopId(), res.GetError().GetMessage())
+				continue
+			}
 			log.Print("recieve res: ", res)
 		}
 	}()
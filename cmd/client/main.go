@@ -20,14 +20,16 @@ import (
 )
 
 const (
-	username        = "admin"
-	password        = "secret"
-	refreshDuration = 30 * time.Second
+	// apiKeyEnv는 관리자가 CreateApiKey로 발급한 device api key를 담는다
+	apiKeyEnv = "PCBOOK_API_KEY"
 )
 
 func main() {
 	serverAddress := flag.String("address", "", "the server port")
 	enableTls := flag.Bool("tls", false, "enable tls")
+	apiKey := flag.String("api-key", os.Getenv(apiKeyEnv), "device api key, "+apiKeyEnv+" is used when empty")
+	certAuth := flag.Bool("cert-auth", false, "authenticate only with the tls client certificate")
+	laptopID := flag.String("laptop-id", "", "catalog id of the laptop to report, it must belong to the caller's organization")
 	flag.Parse()
 	log.Printf("server port : %s", *serverAddress)
 
@@ -41,45 +43,39 @@ func main() {
 		transferOption = grpc.WithTransportCredentials(tlscredentials)
 	}
 
-	cc1, err := grpc.NewClient(*serverAddress, transferOption)
-	if err != nil {
-		log.Fatal("can not create client: ", err)
+	var interceptorOpts []grpc.DialOption
+	if *certAuth {
+		if !*enableTls {
+			log.Fatal("-cert-auth requires -tls")
+		}
+		// server의 cert identity mapping이 client 인증서를 호출자로 인정한다
+	} else if *apiKey != "" {
+		interceptor := client.NewAPIKeyInterceptor(*apiKey)
+		interceptorOpts = []grpc.DialOption{
+			grpc.WithUnaryInterceptor(interceptor.Unary()),
+			grpc.WithStreamInterceptor(interceptor.Stream()),
+		}
+	} else {
+		log.Fatalf("an api key is required, set -api-key or %s (or use -cert-auth)", apiKeyEnv)
 	}
-
-	authClient := client.NewAuthClinet(cc1, username, password)
-	interceptor, err := client.NewAuthInterceptor(authClient, authmethods(), refreshDuration)
-	if err != nil {
-		log.Fatal("can not create auth interceptor: ", err)
+	if *laptopID == "" {
+		log.Fatal("-laptop-id is required")
 	}
-	cc2, err := grpc.NewClient(
-		*serverAddress,
-		transferOption,
-		grpc.WithUnaryInterceptor(interceptor.Unary()),
-		grpc.WithStreamInterceptor(interceptor.Stream()),
-	)
+	cc, err := grpc.NewClient(*serverAddress, append(interceptorOpts, transferOption)...)
 	if err != nil {
 		log.Fatal("can not create client: ", err)
 	}
-	laptopClient := client.NewLaptopClient(cc2)
+	laptopClient := client.NewLaptopClient(cc)
 
 	//testRatingLaptop(laptopClient)
-	err = GetPcBookInfo(laptopClient)
+	err = GetPcBookInfo(laptopClient, *laptopID)
 	if err != nil {
 		log.Fatal("failed to get PC info:", err)
 	}
 }
 
-func authmethods() map[string]bool {
-	const laptopServicePath = "/pcbook.LaptopService/"
-
-	return map[string]bool{
-		laptopServicePath + "CreateLaptop": true,
-		laptopServicePath + "UploadImage":  true,
-		laptopServicePath + "RateLaptop":   true,
-	}
-}
-
-func GetPcBookInfo(laptopClient *client.LaptopClient) error {
+// GetPcBookInfo는 laptopID laptop의 상태를 1초마다 보낸다
+func GetPcBookInfo(laptopClient *client.LaptopClient, laptopID string) error {
 	sendQueue := make(chan *pb.LaptopInfo, 100)
 
 	go client.StartSenderWorker(laptopClient, sendQueue)
@@ -87,11 +83,6 @@ func GetPcBookInfo(laptopClient *client.LaptopClient) error {
 	defer close(sendQueue)
 	defer time.Sleep(1 * time.Second)
 
-	laptopId, err := laptopClient.GetMacSerialID()
-	if err != nil {
-		return fmt.Errorf("can not get laptop id")
-	}
-
 	for {
 		start := time.Now()
 
@@ -154,7 +145,7 @@ func GetPcBookInfo(laptopClient *client.LaptopClient) error {
 			continue
 		}
 
-		result.Id = laptopId
+		result.Id = laptopID
 		result.CreateAt = timestamppb.Now()
 
 		elapsed := time.Since(start)
@@ -10,10 +10,13 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/JeongWoo-Seo/pcBook/pb"
 	"github.com/JeongWoo-Seo/pcBook/redisutil"
+	"github.com/JeongWoo-Seo/pcBook/s3util"
 	"github.com/JeongWoo-Seo/pcBook/service"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
@@ -25,6 +28,11 @@ import (
 const (
 	serverCertFile = "cert/server-cert.pem"
 	serverKeyFile  = "cert/server-key.pem"
+
+	// tokenKeysEnv는 key set 파일과 같은 형식의 JSON을 담는다
+	tokenKeysEnv = "PCBOOK_TOKEN_KEYS"
+	// rootPasswordEnv는 처음 만드는 root 사용자의 비밀번호를 담는다
+	rootPasswordEnv = "PCBOOK_ROOT_PASSWORD"
 )
 
 func main() {
@@ -32,12 +40,39 @@ func main() {
 	enableTls := flag.Bool("tls", false, "enable tls")
 	serverType := flag.String("type", "grpc", "type of srver(grpc/rest)")
 	endPoint := flag.String("endpoint", "", "grpc endpoint")
+	uploadTTL := flag.Duration("upload-ttl", 24*time.Hour, "expiry of incomplete image uploads")
+	imageStoreType := flag.String("image-store", "disk", "type of image store(disk/s3)")
+	s3Endpoint := flag.String("s3-endpoint", "http://localhost:9000", "s3 compatible storage endpoint")
+	s3Region := flag.String("s3-region", "us-east-1", "s3 region")
+	s3Bucket := flag.String("s3-bucket", "pcbook-images", "s3 bucket for images")
+	s3MultipartThreshold := flag.Int("s3-multipart-threshold", service.DefaultS3MultipartThreshold, "image size above which multipart upload is used")
+	s3PartSize := flag.Int("s3-part-size", service.DefaultS3PartSize, "size of each multipart upload part (at least 5 MiB)")
+	maxImages := flag.Int("max-images", service.DefaultImageLimits.MaxImages, "maximum number of images per laptop")
+	maxImageBytes := flag.Int("max-image-bytes", service.DefaultImageLimits.MaxTotalBytes, "maximum total image bytes per laptop")
+	imageGCInterval := flag.Duration("image-gc-interval", time.Hour, "interval of image garbage collection (0 to disable)")
+	imageGCDryRun := flag.Bool("image-gc-dry-run", false, "only report image garbage without deleting")
+	redisAddr := flag.String("redis-addr", "localhost:6379", "redis server address")
+	ratingStoreType := flag.String("rating-store", "memory", "type of rating store(memory/redis)")
+	ratingMin := flag.Float64("rating-min", service.DefaultRatingScale.Min, "minimum rating score")
+	ratingMax := flag.Float64("rating-max", service.DefaultRatingScale.Max, "maximum rating score")
+	ratingStep := flag.Float64("rating-step", service.DefaultRatingScale.Step, "rating score step (0 to allow any value)")
+	ratingPriorMean := flag.Float64("rating-prior-mean", service.DefaultRatingPrior.Mean, "prior mean score used by the bayesian average")
+	ratingPriorWeight := flag.Float64("rating-prior-weight", service.DefaultRatingPrior.Weight, "number of virtual ratings the prior mean counts as")
+	breachedPasswords := flag.String("breached-passwords", "", "file of breached passwords, one per line, rejected by the password policy")
+	tokenKeys := flag.String("token-keys", "", "token key set file, "+tokenKeysEnv+" is used when empty")
+	devTokenKey := flag.Bool("dev-token-key", false, "use the built-in development token key when no token keys are configured (never in production)")
+	policyPath := flag.String("rbac-policy", "rbac_policy.json", "rbac policy file, reloaded on SIGHUP")
+	certIdentitiesPath := flag.String("cert-identities", "", "file mapping tls client certificates to identities, reloaded on SIGHUP")
+	revocationStoreType := flag.String("revocation-store", "memory", "type of token revocation store(memory/redis)")
+	loginAttemptStoreType := flag.String("login-attempt-store", "memory", "type of login failure store(memory/redis)")
+	loginFreeAttempts := flag.Int("login-free-attempts", service.DefaultUserBackoff.FreeAttempts, "failed logins per username before the account is temporarily locked")
+	passwordMinLength := flag.Int("password-min-length", service.DefaultPasswordPolicy.MinLength, "minimum password length")
 	flag.Parse()
 
 	// =========================
 	// Redis 설정
 	// =========================
-	rm := redisutil.NewRedisManager()
+	rm := redisutil.NewRedisManager(*redisAddr)
 	defer rm.Client.Close()
 	rm.StartRedisMonitor(context.Background(), 10*time.Second)
 	redisutil.StartCleanup(context.Background(), rm, 5*time.Second, 15)
@@ -46,20 +81,123 @@ func main() {
 	// Auth
 	// =========================
 	userStore := service.NewInMemoryUserStore()
-	tokenManager := service.NewPasetoManager(service.TokenKey, service.TokenDuration)
-	authServer := service.NewAuthServer(userStore, tokenManager)
-	err := seedUser(userStore)
+	keys, err := loadTokenKeys(*tokenKeys, *devTokenKey)
+	if err != nil {
+		log.Fatal("can not load token keys: ", err)
+	}
+	tokenManager := service.NewPasetoManagerWithKeys(keys, service.TokenDuration)
+	refreshTokenStore := service.NewInMemoryRefreshTokenStore()
+	refreshTokenStore.StartCleanup(context.Background(), time.Hour)
+	revocationStore, err := newRevocationStore(*revocationStoreType, rm)
+	if err != nil {
+		log.Fatal("can not create revocation store: ", err)
+	}
+	apiKeyStore := service.NewInMemoryAPIKeyStore()
+	authServer := service.NewAuthServer(userStore, tokenManager, refreshTokenStore, revocationStore)
+	loginChallengeStore := service.NewInMemoryLoginChallengeStore()
+	loginChallengeStore.StartCleanup(context.Background(), time.Minute)
+	authServer.LoginChallenges = loginChallengeStore
+	userAdminServer := service.NewUserAdminServer(userStore, refreshTokenStore, revocationStore, apiKeyStore)
+	userAdminServer.TokenDuration = tokenManager.TokenDuration()
+	loginAttemptStore, err := newLoginAttemptStore(*loginAttemptStoreType, rm)
+	if err != nil {
+		log.Fatal("can not create login attempt store: ", err)
+	}
+	authServer.LoginThrottle = service.NewLoginThrottle(loginAttemptStore)
+	authServer.LoginThrottle.User.FreeAttempts = *loginFreeAttempts
+	userAdminServer.LoginThrottle = authServer.LoginThrottle
+	policy, err := service.LoadPolicy(*policyPath)
+	if err != nil {
+		log.Fatal("can not load rbac policy: ", err)
+	}
+	orgStore := service.NewInMemoryOrgStore()
+	interceptor := service.NewAuthInterceptor(tokenManager, userStore, revocationStore, apiKeyStore, policy)
+	interceptor.SetOrgStore(orgStore)
+	authServer.Policy = interceptor.Policy
+	userAdminServer.Policy = interceptor.Policy
+	if *certIdentitiesPath != "" {
+		if !*enableTls {
+			log.Fatal("cert identities require -tls")
+		}
+		identities, err := service.LoadCertIdentities(*certIdentitiesPath)
+		if err != nil {
+			log.Fatal("can not load cert identities: ", err)
+		}
+		interceptor.SetCertIdentities(identities)
+	}
+	authServer.PasswordPolicy.MinLength = *passwordMinLength
+	if *breachedPasswords != "" {
+		breached, err := service.LoadBreachedPasswords(*breachedPasswords)
+		if err != nil {
+			log.Fatal("can not load breached passwords: ", err)
+		}
+		authServer.PasswordPolicy.Breached = breached
+	}
+	err = seedUser(userStore, os.Getenv(rootPasswordEnv), authServer.PasswordPolicy)
 	if err != nil {
-		log.Fatal("can not seed user")
+		log.Fatal("can not seed user: ", err)
 	}
 
 	// =========================
 	// Laptop server
 	// =========================
-	laptopStore := service.NewInMemoryLaptopStore()
-	imageStore := service.NewDiskImageStore("tmp")
-	ratingStore := service.NewInMemoryRatingStore()
-	laptopServer := service.NewLaptopServer(laptopStore, imageStore, ratingStore, rm)
+	if *s3PartSize < s3util.MinPartSize {
+		log.Fatalf("s3 part size must be at least %d bytes", s3util.MinPartSize)
+	}
+	// organization의 catalog는 처음 요청을 받을 때 만들며 image gc도 catalog마다 따로 돈다
+	catalogs := service.NewCatalogs(func(ctx context.Context, orgID string) (*service.Catalog, error) {
+		org, err := orgStore.Find(orgID)
+		if err != nil {
+			return nil, err
+		}
+		if org == nil {
+			return nil, fmt.Errorf("organization %s is not found", orgID)
+		}
+
+		imageStore, err := newImageStore(*imageStoreType, orgID, *s3Endpoint, *s3Region, *s3Bucket, *s3MultipartThreshold, *s3PartSize)
+		if err != nil {
+			return nil, fmt.Errorf("can not create image store: %w", err)
+		}
+		ratingStore, err := newRatingStore(*ratingStoreType, orgID, rm)
+		if err != nil {
+			return nil, fmt.Errorf("can not create rating store: %w", err)
+		}
+
+		catalog := service.NewCatalog(service.NewInMemoryLaptopStore(), imageStore, ratingStore, service.NewInMemoryReviewStore())
+		if *imageGCInterval > 0 {
+			catalog.ImageGC.Start(ctx, *imageGCInterval, *imageGCDryRun)
+		}
+		return catalog, nil
+	})
+	// default organization의 catalog는 시작할 때 만들어서 설정 오류를 바로 알린다
+	_, err = catalogs.Get(service.DefaultOrgID)
+	if err != nil {
+		log.Fatal("can not create catalog: ", err)
+	}
+	uploadStore := service.NewDiskUploadSessionStore("tmp/uploads", *uploadTTL)
+	uploadStore.StartCleanup(context.Background(), time.Minute)
+	laptopServer := service.NewLaptopServerWithCatalogs(catalogs, uploadStore, rm)
+	laptopServer.ImageLimits = service.ImageLimits{
+		MaxImages:     *maxImages,
+		MaxTotalBytes: *maxImageBytes,
+	}
+	laptopServer.RatingScale = service.RatingScale{
+		Min:  *ratingMin,
+		Max:  *ratingMax,
+		Step: *ratingStep,
+	}
+	if err := laptopServer.RatingScale.Check(); err != nil {
+		log.Fatal("invalid rating scale: ", err)
+	}
+	if *ratingPriorWeight < 0 {
+		log.Fatal("rating prior weight must not be negative")
+	}
+	laptopServer.RatingPrior = service.RatingPrior{
+		Mean:   *ratingPriorMean,
+		Weight: *ratingPriorWeight,
+	}
+	orgServer := service.NewOrgServer(orgStore, userStore, catalogs, refreshTokenStore, revocationStore, apiKeyStore)
+	orgServer.TokenDuration = tokenManager.TokenDuration()
 
 	// =========================
 	// Network
@@ -71,18 +209,107 @@ func main() {
 	}
 
 	if *serverType == "grpc" {
-		err = runGRPCServer(authServer, laptopServer, tokenManager, *enableTls, listener)
+		go reloadOnSignal(*policyPath, *certIdentitiesPath, interceptor)
+		err = runGRPCServer(authServer, userAdminServer, orgServer, laptopServer, interceptor, *enableTls, listener)
 		if err != nil {
 			log.Fatal("can not start grpc server: %w", err)
 		}
 	} else {
-		err = runRESTServer(authServer, laptopServer, tokenManager, *enableTls, listener, *endPoint)
+		err = runRESTServer(authServer, userAdminServer, orgServer, laptopServer, *enableTls, listener, *endPoint)
 		if err != nil {
 			log.Fatal("can not start REST server: %w", err)
 		}
 	}
 }
 
+// s3 자격 증명은 AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY 환경 변수에서 읽는다
+// default organization이 아니면 이미지를 organization 이름의 폴더나 key prefix 아래에 둔다
+func newImageStore(storeType, orgID, endpoint, region, bucket string, threshold int, partSize int) (service.ImageStore, error) {
+	switch storeType {
+	case "disk":
+		if orgID == service.DefaultOrgID {
+			return service.NewDiskImageStore("tmp"), nil
+		}
+		folder := "tmp/orgs/" + orgID
+		if err := os.MkdirAll(folder, 0o755); err != nil {
+			return nil, err
+		}
+		return service.NewDiskImageStore(folder), nil
+	case "s3":
+		client, err := s3util.NewClient(endpoint, region, bucket, os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"))
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("store images of organization %s in s3 bucket %s at %s", orgID, bucket, endpoint)
+		store := service.NewS3ImageStore(client, threshold, partSize)
+		if orgID != service.DefaultOrgID {
+			store.KeyPrefix = "orgs/" + orgID + "/"
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown image store type: %s", storeType)
+	}
+}
+
+func newRatingStore(storeType string, orgID string, rm *redisutil.RedisManager) (service.RatingStore, error) {
+	switch storeType {
+	case "memory":
+		return service.NewInMemoryRatingStore(), nil
+	case "redis":
+		log.Printf("store ratings of organization %s in redis", orgID)
+		return service.NewRedisRatingStore(rm, orgID), nil
+	default:
+		return nil, fmt.Errorf("unknown rating store type: %s", storeType)
+	}
+}
+
+// loadTokenKeys는 파일, 환경 변수 순서로 key set을 찾는다
+// 공개된 개발용 키로 token을 발급하지 않도록 devKey가 켜져 있을 때만 개발용 키를 사용한다
+func loadTokenKeys(path string, devKey bool) (*service.PasetoKeySet, error) {
+	if path != "" {
+		return service.LoadPasetoKeys(path)
+	}
+
+	if value := os.Getenv(tokenKeysEnv); value != "" {
+		return service.ParsePasetoKeys([]byte(value))
+	}
+
+	if !devKey {
+		return nil, fmt.Errorf("no token keys are configured, set -token-keys or %s (or -dev-token-key for development)", tokenKeysEnv)
+	}
+
+	log.Printf("WARNING: no token keys are configured, using the built-in development key")
+	return service.NewPasetoKeySet(service.PurposeLocal, &service.PasetoKey{ID: service.TokenKeyID, Secret: []byte(service.TokenKey)}), nil
+}
+
+func newRevocationStore(storeType string, rm *redisutil.RedisManager) (service.RevocationStore, error) {
+	switch storeType {
+	case "memory":
+		store := service.NewInMemoryRevocationStore()
+		store.StartCleanup(context.Background(), time.Minute)
+		return store, nil
+	case "redis":
+		log.Printf("store token revocations in redis")
+		return service.NewRedisRevocationStore(rm), nil
+	default:
+		return nil, fmt.Errorf("unknown revocation store type: %s", storeType)
+	}
+}
+
+func newLoginAttemptStore(storeType string, rm *redisutil.RedisManager) (service.LoginAttemptStore, error) {
+	switch storeType {
+	case "memory":
+		store := service.NewInMemoryLoginAttemptStore()
+		store.StartCleanup(context.Background(), time.Minute)
+		return store, nil
+	case "redis":
+		log.Printf("store login attempts in redis")
+		return service.NewRedisLoginAttemptStore(rm), nil
+	default:
+		return nil, fmt.Errorf("unknown login attempt store type: %s", storeType)
+	}
+}
+
 func loadTLSCredentials() (credentials.TransportCredentials, error) {
 	pemClientCA, err := os.ReadFile("cert/ca-cert.pem")
 	if err != nil {
@@ -110,12 +337,13 @@ func loadTLSCredentials() (credentials.TransportCredentials, error) {
 
 func runGRPCServer(
 	authServer pb.AuthServiceServer,
+	userAdminServer pb.UserAdminServiceServer,
+	orgServer pb.OrgServiceServer,
 	laptopServer pb.LaptopServiceServer,
-	tokenManager *service.PasetoManager,
+	interceptor *service.AuthInterceptor,
 	enableTLS bool,
 	listener net.Listener,
 ) error {
-	interceptor := service.NewAuthInterceptor(tokenManager, accessibleRole())
 	serverOpts := []grpc.ServerOption{
 		grpc.UnaryInterceptor(interceptor.Unary()),
 		grpc.StreamInterceptor(interceptor.Stream()),
@@ -131,17 +359,25 @@ func runGRPCServer(
 
 	grpcServer := grpc.NewServer(serverOpts...)
 	pb.RegisterAuthServiceServer(grpcServer, authServer)
+	pb.RegisterUserAdminServiceServer(grpcServer, userAdminServer)
+	pb.RegisterOrgServiceServer(grpcServer, orgServer)
 	pb.RegisterLaptopServiceServer(grpcServer, laptopServer)
 	reflection.Register(grpcServer)
 
+	err := interceptor.CheckCoverage(grpcServer.GetServiceInfo())
+	if err != nil {
+		return fmt.Errorf("invalid rbac policy: %w", err)
+	}
+
 	log.Printf("start GRPC server at %s, TLS = %t", listener.Addr().String(), enableTLS)
 	return grpcServer.Serve(listener)
 }
 
 func runRESTServer(
 	authServer pb.AuthServiceServer,
+	userAdminServer pb.UserAdminServiceServer,
+	orgServer pb.OrgServiceServer,
 	laptopServer pb.LaptopServiceServer,
-	tokenManager *service.PasetoManager,
 	enableTLS bool,
 	listener net.Listener,
 	grpcEndpoint string,
@@ -156,6 +392,16 @@ func runRESTServer(
 		return err
 	}
 
+	err = pb.RegisterUserAdminServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, dialOpts)
+	if err != nil {
+		return err
+	}
+
+	err = pb.RegisterOrgServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, dialOpts)
+	if err != nil {
+		return err
+	}
+
 	err = pb.RegisterLaptopServiceHandlerFromEndpoint(ctx, mux, grpcEndpoint, dialOpts)
 	if err != nil {
 		return err
@@ -170,13 +416,19 @@ func runRESTServer(
 	return http.Serve(listener, mux)
 }
 
-func seedUser(userStore service.UserStore) error {
-	err := createUser(userStore, "admin", "secret", "admin")
+// seedUser는 환경 변수로 받은 비밀번호로 root 사용자만 만든다
+// 다른 사용자는 root로 로그인해 만든다
+func seedUser(userStore service.UserStore, rootPassword string, policy service.PasswordPolicy) error {
+	if rootPassword == "" {
+		return fmt.Errorf("%s is not set", rootPasswordEnv)
+	}
+
+	err := policy.Check("root", rootPassword)
 	if err != nil {
-		return err
+		return fmt.Errorf("%s: %w", rootPasswordEnv, err)
 	}
 
-	return createUser(userStore, "user", "secret", "user")
+	return createUser(userStore, "root", rootPassword, service.SuperAdminRole)
 }
 
 func createUser(userStore service.UserStore, username, password, role string) error {
@@ -187,12 +439,32 @@ func createUser(userStore service.UserStore, username, password, role string) er
 	return userStore.Save(user)
 }
 
-func accessibleRole() map[string][]string {
-	const laptopServicePath = "/pcbook.LaptopService/"
+// reloadOnSignal은 SIGHUP을 받으면 policy 파일과 cert identity 파일을 다시 읽는다
+// 새 파일이 잘못되었으면 기존 설정을 계속 사용한다
+func reloadOnSignal(policyPath string, certIdentitiesPath string, interceptor *service.AuthInterceptor) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		policy, err := service.LoadPolicy(policyPath)
+		if err == nil {
+			err = interceptor.SetPolicy(policy)
+		}
+		if err != nil {
+			log.Printf("can not reload rbac policy, keep the previous one: %v", err)
+		} else {
+			log.Printf("reloaded rbac policy from %s", policyPath)
+		}
 
-	return map[string][]string{
-		laptopServicePath + "CreateLaptop": {"admin"},
-		laptopServicePath + "UploadImage":  {"admin"},
-		laptopServicePath + "RateLaptop":   {"admin", "user"},
+		if certIdentitiesPath == "" {
+			continue
+		}
+		identities, err := service.LoadCertIdentities(certIdentitiesPath)
+		if err != nil {
+			log.Printf("can not reload cert identities, keep the previous ones: %v", err)
+			continue
+		}
+		interceptor.SetCertIdentities(identities)
+		log.Printf("reloaded cert identities from %s", certIdentitiesPath)
 	}
 }
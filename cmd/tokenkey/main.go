@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/service"
+)
+
+// tokenkey는 서버가 -token-keys로 읽는 PASETO key set 파일을 관리한다
+//
+//	tokenkey generate -file keys.json [-purpose v2.public]
+//	tokenkey publish -file keys.json
+//	tokenkey activate -file keys.json -key <id> -grace 30m
+//	tokenkey list -file keys.json
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	path := flags.String("file", "token_keys.json", "token key set file")
+	purpose := flags.String("purpose", service.PurposeLocal, "token purpose of a new key set(v2.local/v2.public)")
+	keyID := flags.String("key", "", "id of the published key to activate")
+	grace := flags.Duration("grace", 2*service.TokenDuration, "how long the previous active key keeps verifying tokens after activation")
+	flags.Parse(os.Args[2:])
+
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = generate(*path, *purpose)
+	case "publish":
+		err = publish(*path)
+	case "activate":
+		err = activate(*path, *keyID, *grace)
+	case "list":
+		err = list(*path)
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: tokenkey generate|publish|activate|list -file <path> [-purpose v2.local|v2.public] [-key <id>] [-grace <duration>]")
+	os.Exit(2)
+}
+
+func generate(path string, purpose string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists, use publish to add a new key", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	key, err := service.GeneratePasetoKey()
+	if err != nil {
+		return err
+	}
+
+	keys := service.NewPasetoKeySet(purpose, key)
+	if err := keys.Check(); err != nil {
+		return err
+	}
+
+	err = keys.Save(path)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("generated %s token key %s in %s", purpose, key.ID, path)
+	return nil
+}
+
+// publish한 키는 검증에만 쓰이며 모든 서버가 새 key set을 읽도록 재시작한 뒤에 activate한다
+func publish(path string) error {
+	keys, err := service.LoadPasetoKeys(path)
+	if err != nil {
+		return err
+	}
+
+	key, err := keys.Publish()
+	if err != nil {
+		return err
+	}
+
+	err = keys.Save(path)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("published token key %s, activate it after every server has loaded %s", key.ID, path)
+	return nil
+}
+
+// activate 후에도 모든 서버가 새 key set을 읽도록 재시작해야 한다
+func activate(path string, keyID string, grace time.Duration) error {
+	if keyID == "" {
+		return fmt.Errorf("-key is required")
+	}
+	if grace < service.TokenDuration {
+		return fmt.Errorf("grace must be at least the token duration %s", service.TokenDuration)
+	}
+
+	keys, err := service.LoadPasetoKeys(path)
+	if err != nil {
+		return err
+	}
+
+	previous := keys.ActiveKeyID
+	err = keys.Activate(keyID, grace)
+	if err != nil {
+		return err
+	}
+
+	err = keys.Save(path)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("activated token key %s, %s expires in %s", keyID, previous, grace)
+	return nil
+}
+
+func list(path string) error {
+	keys, err := service.LoadPasetoKeys(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("purpose %s\n", keys.Purpose)
+	for _, key := range keys.Keys {
+		active := ""
+		if key.ID == keys.ActiveKeyID {
+			active = " (active)"
+		}
+
+		expires := "never"
+		if !key.ExpiresAt.IsZero() {
+			expires = key.ExpiresAt.Format(time.RFC3339)
+		}
+
+		fmt.Printf("%s%s created %s, expires %s\n", key.ID, active, key.CreatedAt.Format(time.RFC3339), expires)
+	}
+	return nil
+}
@@ -41,6 +41,7 @@ type Laptop struct {
 	Price         uint32                 `protobuf:"varint,12,opt,name=price,proto3" json:"price,omitempty"`
 	ReleaseYear   uint32                 `protobuf:"varint,13,opt,name=release_year,json=releaseYear,proto3" json:"release_year,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Owner         string                 `protobuf:"bytes,15,opt,name=owner,proto3" json:"owner,omitempty"` // vendor account that created the laptop, set by the server
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -184,6 +185,13 @@ func (x *Laptop) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Laptop) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
 type isLaptop_Weight interface {
 	isLaptop_Weight()
 }
@@ -204,7 +212,7 @@ var File_laptop_proto protoreflect.FileDescriptor
 
 const file_laptop_proto_rawDesc = "" +
 	"\n" +
-	"\flaptop.proto\x12\x06pcbook\x1a\x0fprocessor.proto\x1a\fmemory.proto\x1a\rstorage.proto\x1a\fscreen.proto\x1a\x0ekeyboard.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe3\x03\n" +
+	"\flaptop.proto\x12\x06pcbook\x1a\x0fprocessor.proto\x1a\fmemory.proto\x1a\rstorage.proto\x1a\fscreen.proto\x1a\x0ekeyboard.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf9\x03\n" +
 	"\x06Laptop\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05brand\x18\x02 \x01(\tR\x05brand\x12\x12\n" +
@@ -221,7 +229,8 @@ const file_laptop_proto_rawDesc = "" +
 	"\x05price\x18\f \x01(\rR\x05price\x12!\n" +
 	"\frelease_year\x18\r \x01(\rR\vreleaseYear\x129\n" +
 	"\n" +
-	"updated_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\b\n" +
+	"updated_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x14\n" +
+	"\x05owner\x18\x0f \x01(\tR\x05ownerB\b\n" +
 	"\x06weightB#Z!github.com/JeongWoo-Seo/pcBook/pbb\x06proto3"
 
 var (
@@ -10,6 +10,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -110,6 +111,174 @@ func (x *CreateLaptopResponse) GetId() string {
 	return ""
 }
 
+type UpdateLaptopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Laptop        *Laptop                `protobuf:"bytes,1,opt,name=laptop,proto3" json:"laptop,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLaptopRequest) Reset() {
+	*x = UpdateLaptopRequest{}
+	mi := &file_laptop_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLaptopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLaptopRequest) ProtoMessage() {}
+
+func (x *UpdateLaptopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLaptopRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLaptopRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateLaptopRequest) GetLaptop() *Laptop {
+	if x != nil {
+		return x.Laptop
+	}
+	return nil
+}
+
+type UpdateLaptopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Laptop        *Laptop                `protobuf:"bytes,1,opt,name=laptop,proto3" json:"laptop,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLaptopResponse) Reset() {
+	*x = UpdateLaptopResponse{}
+	mi := &file_laptop_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLaptopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLaptopResponse) ProtoMessage() {}
+
+func (x *UpdateLaptopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLaptopResponse.ProtoReflect.Descriptor instead.
+func (*UpdateLaptopResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateLaptopResponse) GetLaptop() *Laptop {
+	if x != nil {
+		return x.Laptop
+	}
+	return nil
+}
+
+type DeleteLaptopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLaptopRequest) Reset() {
+	*x = DeleteLaptopRequest{}
+	mi := &file_laptop_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLaptopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLaptopRequest) ProtoMessage() {}
+
+func (x *DeleteLaptopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLaptopRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLaptopRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteLaptopRequest) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+type DeleteLaptopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLaptopResponse) Reset() {
+	*x = DeleteLaptopResponse{}
+	mi := &file_laptop_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLaptopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLaptopResponse) ProtoMessage() {}
+
+func (x *DeleteLaptopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLaptopResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLaptopResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{5}
+}
+
 type SearchLaptopRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Filter        *Filter                `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
@@ -119,7 +288,7 @@ type SearchLaptopRequest struct {
 
 func (x *SearchLaptopRequest) Reset() {
 	*x = SearchLaptopRequest{}
-	mi := &file_laptop_service_proto_msgTypes[2]
+	mi := &file_laptop_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -131,7 +300,7 @@ func (x *SearchLaptopRequest) String() string {
 func (*SearchLaptopRequest) ProtoMessage() {}
 
 func (x *SearchLaptopRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_laptop_service_proto_msgTypes[2]
+	mi := &file_laptop_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -144,7 +313,7 @@ func (x *SearchLaptopRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchLaptopRequest.ProtoReflect.Descriptor instead.
 func (*SearchLaptopRequest) Descriptor() ([]byte, []int) {
-	return file_laptop_service_proto_rawDescGZIP(), []int{2}
+	return file_laptop_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SearchLaptopRequest) GetFilter() *Filter {
@@ -163,7 +332,7 @@ type SearchLaptopResponse struct {
 
 func (x *SearchLaptopResponse) Reset() {
 	*x = SearchLaptopResponse{}
-	mi := &file_laptop_service_proto_msgTypes[3]
+	mi := &file_laptop_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -175,7 +344,7 @@ func (x *SearchLaptopResponse) String() string {
 func (*SearchLaptopResponse) ProtoMessage() {}
 
 func (x *SearchLaptopResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_laptop_service_proto_msgTypes[3]
+	mi := &file_laptop_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -188,7 +357,7 @@ func (x *SearchLaptopResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchLaptopResponse.ProtoReflect.Descriptor instead.
 func (*SearchLaptopResponse) Descriptor() ([]byte, []int) {
-	return file_laptop_service_proto_rawDescGZIP(), []int{3}
+	return file_laptop_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *SearchLaptopResponse) GetLaptop() *Laptop {
@@ -211,7 +380,7 @@ type UploadImageRequest struct {
 
 func (x *UploadImageRequest) Reset() {
 	*x = UploadImageRequest{}
-	mi := &file_laptop_service_proto_msgTypes[4]
+	mi := &file_laptop_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -223,7 +392,7 @@ func (x *UploadImageRequest) String() string {
 func (*UploadImageRequest) ProtoMessage() {}
 
 func (x *UploadImageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_laptop_service_proto_msgTypes[4]
+	mi := &file_laptop_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -236,7 +405,7 @@ func (x *UploadImageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadImageRequest.ProtoReflect.Descriptor instead.
 func (*UploadImageRequest) Descriptor() ([]byte, []int) {
-	return file_laptop_service_proto_rawDescGZIP(), []int{4}
+	return file_laptop_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UploadImageRequest) GetData() isUploadImageRequest_Data {
@@ -284,13 +453,19 @@ type ImageInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
 	ImageType     string                 `protobuf:"bytes,2,opt,name=image_type,json=imageType,proto3" json:"image_type,omitempty"`
+	Checksum      string                 `protobuf:"bytes,3,opt,name=checksum,proto3" json:"checksum,omitempty"` // expected SHA-256 of the image (hex), optional
+	Id            string                 `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	Position      uint32                 `protobuf:"varint,5,opt,name=position,proto3" json:"position,omitempty"`
+	IsPrimary     bool                   `protobuf:"varint,6,opt,name=is_primary,json=isPrimary,proto3" json:"is_primary,omitempty"`
+	AltText       string                 `protobuf:"bytes,7,opt,name=alt_text,json=altText,proto3" json:"alt_text,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ImageInfo) Reset() {
 	*x = ImageInfo{}
-	mi := &file_laptop_service_proto_msgTypes[5]
+	mi := &file_laptop_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -302,7 +477,7 @@ func (x *ImageInfo) String() string {
 func (*ImageInfo) ProtoMessage() {}
 
 func (x *ImageInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_laptop_service_proto_msgTypes[5]
+	mi := &file_laptop_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,7 +490,7 @@ func (x *ImageInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageInfo.ProtoReflect.Descriptor instead.
 func (*ImageInfo) Descriptor() ([]byte, []int) {
-	return file_laptop_service_proto_rawDescGZIP(), []int{5}
+	return file_laptop_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ImageInfo) GetLaptopId() string {
@@ -332,17 +507,60 @@ func (x *ImageInfo) GetImageType() string {
 	return ""
 }
 
+func (x *ImageInfo) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *ImageInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImageInfo) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *ImageInfo) GetIsPrimary() bool {
+	if x != nil {
+		return x.IsPrimary
+	}
+	return false
+}
+
+func (x *ImageInfo) GetAltText() string {
+	if x != nil {
+		return x.AltText
+	}
+	return ""
+}
+
+func (x *ImageInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 type UploadImageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Size          uint32                 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Digest        string                 `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"` // SHA-256 of the stored image (hex)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UploadImageResponse) Reset() {
 	*x = UploadImageResponse{}
-	mi := &file_laptop_service_proto_msgTypes[6]
+	mi := &file_laptop_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -354,7 +572,7 @@ func (x *UploadImageResponse) String() string {
 func (*UploadImageResponse) ProtoMessage() {}
 
 func (x *UploadImageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_laptop_service_proto_msgTypes[6]
+	mi := &file_laptop_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -367,7 +585,7 @@ func (x *UploadImageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadImageResponse.ProtoReflect.Descriptor instead.
 func (*UploadImageResponse) Descriptor() ([]byte, []int) {
-	return file_laptop_service_proto_rawDescGZIP(), []int{6}
+	return file_laptop_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *UploadImageResponse) GetId() string {
@@ -384,29 +602,36 @@ func (x *UploadImageResponse) GetSize() uint32 {
 	return 0
 }
 
-type RateLaptopRequest struct {
+func (x *UploadImageResponse) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+type StartImageUploadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
-	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	Info          *ImageInfo             `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	TotalSize     uint32                 `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RateLaptopRequest) Reset() {
-	*x = RateLaptopRequest{}
-	mi := &file_laptop_service_proto_msgTypes[7]
+func (x *StartImageUploadRequest) Reset() {
+	*x = StartImageUploadRequest{}
+	mi := &file_laptop_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RateLaptopRequest) String() string {
+func (x *StartImageUploadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RateLaptopRequest) ProtoMessage() {}
+func (*StartImageUploadRequest) ProtoMessage() {}
 
-func (x *RateLaptopRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_laptop_service_proto_msgTypes[7]
+func (x *StartImageUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -417,49 +642,49 @@ func (x *RateLaptopRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RateLaptopRequest.ProtoReflect.Descriptor instead.
-func (*RateLaptopRequest) Descriptor() ([]byte, []int) {
-	return file_laptop_service_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use StartImageUploadRequest.ProtoReflect.Descriptor instead.
+func (*StartImageUploadRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *RateLaptopRequest) GetLaptopId() string {
+func (x *StartImageUploadRequest) GetInfo() *ImageInfo {
 	if x != nil {
-		return x.LaptopId
+		return x.Info
 	}
-	return ""
+	return nil
 }
 
-func (x *RateLaptopRequest) GetScore() float64 {
+func (x *StartImageUploadRequest) GetTotalSize() uint32 {
 	if x != nil {
-		return x.Score
+		return x.TotalSize
 	}
 	return 0
 }
 
-type RateLaptopResponse struct {
+type StartImageUploadResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
-	RatedCount    uint32                 `protobuf:"varint,2,opt,name=rated_count,json=ratedCount,proto3" json:"rated_count,omitempty"`
-	AverageScore  float64                `protobuf:"fixed64,3,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	ChunkSize     uint32                 `protobuf:"varint,2,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RateLaptopResponse) Reset() {
-	*x = RateLaptopResponse{}
-	mi := &file_laptop_service_proto_msgTypes[8]
+func (x *StartImageUploadResponse) Reset() {
+	*x = StartImageUploadResponse{}
+	mi := &file_laptop_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RateLaptopResponse) String() string {
+func (x *StartImageUploadResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RateLaptopResponse) ProtoMessage() {}
+func (*StartImageUploadResponse) ProtoMessage() {}
 
-func (x *RateLaptopResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_laptop_service_proto_msgTypes[8]
+func (x *StartImageUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -470,54 +695,56 @@ func (x *RateLaptopResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RateLaptopResponse.ProtoReflect.Descriptor instead.
-func (*RateLaptopResponse) Descriptor() ([]byte, []int) {
-	return file_laptop_service_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use StartImageUploadResponse.ProtoReflect.Descriptor instead.
+func (*StartImageUploadResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *RateLaptopResponse) GetLaptopId() string {
+func (x *StartImageUploadResponse) GetUploadId() string {
 	if x != nil {
-		return x.LaptopId
+		return x.UploadId
 	}
 	return ""
 }
 
-func (x *RateLaptopResponse) GetRatedCount() uint32 {
+func (x *StartImageUploadResponse) GetChunkSize() uint32 {
 	if x != nil {
-		return x.RatedCount
+		return x.ChunkSize
 	}
 	return 0
 }
 
-func (x *RateLaptopResponse) GetAverageScore() float64 {
+func (x *StartImageUploadResponse) GetExpiresAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.AverageScore
+		return x.ExpiresAt
 	}
-	return 0
+	return nil
 }
 
-type SendLaptopInfoRequest struct {
+type UploadImageChunkRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Laptop        *LaptopInfo            `protobuf:"bytes,1,opt,name=laptop,proto3" json:"laptop,omitempty"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	Offset        uint32                 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	ChunkData     []byte                 `protobuf:"bytes,3,opt,name=chunk_data,json=chunkData,proto3" json:"chunk_data,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendLaptopInfoRequest) Reset() {
-	*x = SendLaptopInfoRequest{}
-	mi := &file_laptop_service_proto_msgTypes[9]
+func (x *UploadImageChunkRequest) Reset() {
+	*x = UploadImageChunkRequest{}
+	mi := &file_laptop_service_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendLaptopInfoRequest) String() string {
+func (x *UploadImageChunkRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendLaptopInfoRequest) ProtoMessage() {}
+func (*UploadImageChunkRequest) ProtoMessage() {}
 
-func (x *SendLaptopInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_laptop_service_proto_msgTypes[9]
+func (x *UploadImageChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -528,19 +755,1861 @@ func (x *SendLaptopInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendLaptopInfoRequest.ProtoReflect.Descriptor instead.
-func (*SendLaptopInfoRequest) Descriptor() ([]byte, []int) {
-	return file_laptop_service_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use UploadImageChunkRequest.ProtoReflect.Descriptor instead.
+func (*UploadImageChunkRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *SendLaptopInfoRequest) GetLaptop() *LaptopInfo {
+func (x *UploadImageChunkRequest) GetUploadId() string {
 	if x != nil {
-		return x.Laptop
+		return x.UploadId
 	}
-	return nil
+	return ""
 }
 
-type SendLaptopInfoResponse struct {
+func (x *UploadImageChunkRequest) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *UploadImageChunkRequest) GetChunkData() []byte {
+	if x != nil {
+		return x.ChunkData
+	}
+	return nil
+}
+
+type UploadImageChunkResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UploadId        string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	CommittedOffset uint32                 `protobuf:"varint,2,opt,name=committed_offset,json=committedOffset,proto3" json:"committed_offset,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UploadImageChunkResponse) Reset() {
+	*x = UploadImageChunkResponse{}
+	mi := &file_laptop_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadImageChunkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadImageChunkResponse) ProtoMessage() {}
+
+func (x *UploadImageChunkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadImageChunkResponse.ProtoReflect.Descriptor instead.
+func (*UploadImageChunkResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UploadImageChunkResponse) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadImageChunkResponse) GetCommittedOffset() uint32 {
+	if x != nil {
+		return x.CommittedOffset
+	}
+	return 0
+}
+
+type GetImageUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetImageUploadRequest) Reset() {
+	*x = GetImageUploadRequest{}
+	mi := &file_laptop_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetImageUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetImageUploadRequest) ProtoMessage() {}
+
+func (x *GetImageUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetImageUploadRequest.ProtoReflect.Descriptor instead.
+func (*GetImageUploadRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetImageUploadRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+type GetImageUploadResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UploadId        string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	CommittedOffset uint32                 `protobuf:"varint,2,opt,name=committed_offset,json=committedOffset,proto3" json:"committed_offset,omitempty"`
+	TotalSize       uint32                 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	ExpiresAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetImageUploadResponse) Reset() {
+	*x = GetImageUploadResponse{}
+	mi := &file_laptop_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetImageUploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetImageUploadResponse) ProtoMessage() {}
+
+func (x *GetImageUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetImageUploadResponse.ProtoReflect.Descriptor instead.
+func (*GetImageUploadResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetImageUploadResponse) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *GetImageUploadResponse) GetCommittedOffset() uint32 {
+	if x != nil {
+		return x.CommittedOffset
+	}
+	return 0
+}
+
+func (x *GetImageUploadResponse) GetTotalSize() uint32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *GetImageUploadResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type CommitImageUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitImageUploadRequest) Reset() {
+	*x = CommitImageUploadRequest{}
+	mi := &file_laptop_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitImageUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitImageUploadRequest) ProtoMessage() {}
+
+func (x *CommitImageUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitImageUploadRequest.ProtoReflect.Descriptor instead.
+func (*CommitImageUploadRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CommitImageUploadRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+type ListImagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListImagesRequest) Reset() {
+	*x = ListImagesRequest{}
+	mi := &file_laptop_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListImagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListImagesRequest) ProtoMessage() {}
+
+func (x *ListImagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListImagesRequest.ProtoReflect.Descriptor instead.
+func (*ListImagesRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListImagesRequest) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+type ListImagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Images        []*ImageInfo           `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListImagesResponse) Reset() {
+	*x = ListImagesResponse{}
+	mi := &file_laptop_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListImagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListImagesResponse) ProtoMessage() {}
+
+func (x *ListImagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListImagesResponse.ProtoReflect.Descriptor instead.
+func (*ListImagesResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListImagesResponse) GetImages() []*ImageInfo {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+type ReorderImagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	ImageIds      []string               `protobuf:"bytes,2,rep,name=image_ids,json=imageIds,proto3" json:"image_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderImagesRequest) Reset() {
+	*x = ReorderImagesRequest{}
+	mi := &file_laptop_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderImagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderImagesRequest) ProtoMessage() {}
+
+func (x *ReorderImagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderImagesRequest.ProtoReflect.Descriptor instead.
+func (*ReorderImagesRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReorderImagesRequest) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+func (x *ReorderImagesRequest) GetImageIds() []string {
+	if x != nil {
+		return x.ImageIds
+	}
+	return nil
+}
+
+type ReorderImagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Images        []*ImageInfo           `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderImagesResponse) Reset() {
+	*x = ReorderImagesResponse{}
+	mi := &file_laptop_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderImagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderImagesResponse) ProtoMessage() {}
+
+func (x *ReorderImagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderImagesResponse.ProtoReflect.Descriptor instead.
+func (*ReorderImagesResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReorderImagesResponse) GetImages() []*ImageInfo {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+type SetPrimaryImageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	ImageId       string                 `protobuf:"bytes,2,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPrimaryImageRequest) Reset() {
+	*x = SetPrimaryImageRequest{}
+	mi := &file_laptop_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPrimaryImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPrimaryImageRequest) ProtoMessage() {}
+
+func (x *SetPrimaryImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPrimaryImageRequest.ProtoReflect.Descriptor instead.
+func (*SetPrimaryImageRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetPrimaryImageRequest) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+func (x *SetPrimaryImageRequest) GetImageId() string {
+	if x != nil {
+		return x.ImageId
+	}
+	return ""
+}
+
+type SetPrimaryImageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Images        []*ImageInfo           `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPrimaryImageResponse) Reset() {
+	*x = SetPrimaryImageResponse{}
+	mi := &file_laptop_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPrimaryImageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPrimaryImageResponse) ProtoMessage() {}
+
+func (x *SetPrimaryImageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPrimaryImageResponse.ProtoReflect.Descriptor instead.
+func (*SetPrimaryImageResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SetPrimaryImageResponse) GetImages() []*ImageInfo {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+type CollectImageGarbageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DryRun        bool                   `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectImageGarbageRequest) Reset() {
+	*x = CollectImageGarbageRequest{}
+	mi := &file_laptop_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectImageGarbageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectImageGarbageRequest) ProtoMessage() {}
+
+func (x *CollectImageGarbageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectImageGarbageRequest.ProtoReflect.Descriptor instead.
+func (*CollectImageGarbageRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CollectImageGarbageRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type CollectImageGarbageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DryRun        bool                   `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	OrphanFiles   []string               `protobuf:"bytes,2,rep,name=orphan_files,json=orphanFiles,proto3" json:"orphan_files,omitempty"`       // files with no index entry
+	MissingImages []string               `protobuf:"bytes,3,rep,name=missing_images,json=missingImages,proto3" json:"missing_images,omitempty"` // image ids whose file no longer exists
+	OrphanImages  []string               `protobuf:"bytes,4,rep,name=orphan_images,json=orphanImages,proto3" json:"orphan_images,omitempty"`    // image ids whose laptop no longer exists
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectImageGarbageResponse) Reset() {
+	*x = CollectImageGarbageResponse{}
+	mi := &file_laptop_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectImageGarbageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectImageGarbageResponse) ProtoMessage() {}
+
+func (x *CollectImageGarbageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectImageGarbageResponse.ProtoReflect.Descriptor instead.
+func (*CollectImageGarbageResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CollectImageGarbageResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *CollectImageGarbageResponse) GetOrphanFiles() []string {
+	if x != nil {
+		return x.OrphanFiles
+	}
+	return nil
+}
+
+func (x *CollectImageGarbageResponse) GetMissingImages() []string {
+	if x != nil {
+		return x.MissingImages
+	}
+	return nil
+}
+
+func (x *CollectImageGarbageResponse) GetOrphanImages() []string {
+	if x != nil {
+		return x.OrphanImages
+	}
+	return nil
+}
+
+type RateLaptopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLaptopRequest) Reset() {
+	*x = RateLaptopRequest{}
+	mi := &file_laptop_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLaptopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLaptopRequest) ProtoMessage() {}
+
+func (x *RateLaptopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLaptopRequest.ProtoReflect.Descriptor instead.
+func (*RateLaptopRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RateLaptopRequest) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+func (x *RateLaptopRequest) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type RateLaptopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	RatedCount    uint32                 `protobuf:"varint,2,opt,name=rated_count,json=ratedCount,proto3" json:"rated_count,omitempty"`
+	AverageScore  float64                `protobuf:"fixed64,3,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	Error         *RateError             `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // set when this rating was rejected, the stream stays open
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLaptopResponse) Reset() {
+	*x = RateLaptopResponse{}
+	mi := &file_laptop_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLaptopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLaptopResponse) ProtoMessage() {}
+
+func (x *RateLaptopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLaptopResponse.ProtoReflect.Descriptor instead.
+func (*RateLaptopResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RateLaptopResponse) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+func (x *RateLaptopResponse) GetRatedCount() uint32 {
+	if x != nil {
+		return x.RatedCount
+	}
+	return 0
+}
+
+func (x *RateLaptopResponse) GetAverageScore() float64 {
+	if x != nil {
+		return x.AverageScore
+	}
+	return 0
+}
+
+func (x *RateLaptopResponse) GetError() *RateError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type RateError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          uint32                 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"` // grpc status code
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateError) Reset() {
+	*x = RateError{}
+	mi := &file_laptop_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateError) ProtoMessage() {}
+
+func (x *RateError) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateError.ProtoReflect.Descriptor instead.
+func (*RateError) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RateError) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *RateError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ScoreBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Score         float64                `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoreBucket) Reset() {
+	*x = ScoreBucket{}
+	mi := &file_laptop_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreBucket) ProtoMessage() {}
+
+func (x *ScoreBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreBucket.ProtoReflect.Descriptor instead.
+func (*ScoreBucket) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ScoreBucket) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ScoreBucket) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type LaptopRating struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId        string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	RatedCount      uint32                 `protobuf:"varint,2,opt,name=rated_count,json=ratedCount,proto3" json:"rated_count,omitempty"`
+	AverageScore    float64                `protobuf:"fixed64,3,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	BayesianAverage float64                `protobuf:"fixed64,4,opt,name=bayesian_average,json=bayesianAverage,proto3" json:"bayesian_average,omitempty"`
+	Histogram       []*ScoreBucket         `protobuf:"bytes,5,rep,name=histogram,proto3" json:"histogram,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LaptopRating) Reset() {
+	*x = LaptopRating{}
+	mi := &file_laptop_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LaptopRating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LaptopRating) ProtoMessage() {}
+
+func (x *LaptopRating) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LaptopRating.ProtoReflect.Descriptor instead.
+func (*LaptopRating) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *LaptopRating) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+func (x *LaptopRating) GetRatedCount() uint32 {
+	if x != nil {
+		return x.RatedCount
+	}
+	return 0
+}
+
+func (x *LaptopRating) GetAverageScore() float64 {
+	if x != nil {
+		return x.AverageScore
+	}
+	return 0
+}
+
+func (x *LaptopRating) GetBayesianAverage() float64 {
+	if x != nil {
+		return x.BayesianAverage
+	}
+	return 0
+}
+
+func (x *LaptopRating) GetHistogram() []*ScoreBucket {
+	if x != nil {
+		return x.Histogram
+	}
+	return nil
+}
+
+type GetLaptopRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLaptopRatingRequest) Reset() {
+	*x = GetLaptopRatingRequest{}
+	mi := &file_laptop_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLaptopRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLaptopRatingRequest) ProtoMessage() {}
+
+func (x *GetLaptopRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLaptopRatingRequest.ProtoReflect.Descriptor instead.
+func (*GetLaptopRatingRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetLaptopRatingRequest) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+type GetLaptopRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rating        *LaptopRating          `protobuf:"bytes,1,opt,name=rating,proto3" json:"rating,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLaptopRatingResponse) Reset() {
+	*x = GetLaptopRatingResponse{}
+	mi := &file_laptop_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLaptopRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLaptopRatingResponse) ProtoMessage() {}
+
+func (x *GetLaptopRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLaptopRatingResponse.ProtoReflect.Descriptor instead.
+func (*GetLaptopRatingResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetLaptopRatingResponse) GetRating() *LaptopRating {
+	if x != nil {
+		return x.Rating
+	}
+	return nil
+}
+
+type GetLaptopRatingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopIds     []string               `protobuf:"bytes,1,rep,name=laptop_ids,json=laptopIds,proto3" json:"laptop_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLaptopRatingsRequest) Reset() {
+	*x = GetLaptopRatingsRequest{}
+	mi := &file_laptop_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLaptopRatingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLaptopRatingsRequest) ProtoMessage() {}
+
+func (x *GetLaptopRatingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLaptopRatingsRequest.ProtoReflect.Descriptor instead.
+func (*GetLaptopRatingsRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetLaptopRatingsRequest) GetLaptopIds() []string {
+	if x != nil {
+		return x.LaptopIds
+	}
+	return nil
+}
+
+type GetLaptopRatingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ratings       []*LaptopRating        `protobuf:"bytes,1,rep,name=ratings,proto3" json:"ratings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLaptopRatingsResponse) Reset() {
+	*x = GetLaptopRatingsResponse{}
+	mi := &file_laptop_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLaptopRatingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLaptopRatingsResponse) ProtoMessage() {}
+
+func (x *GetLaptopRatingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLaptopRatingsResponse.ProtoReflect.Descriptor instead.
+func (*GetLaptopRatingsResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetLaptopRatingsResponse) GetRatings() []*LaptopRating {
+	if x != nil {
+		return x.Ratings
+	}
+	return nil
+}
+
+type TopRatedLaptopsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Limit          uint32                 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // default 10
+	Brand          string                 `protobuf:"bytes,2,opt,name=brand,proto3" json:"brand,omitempty"`
+	Filter         *Filter                `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	MinRatingCount uint32                 `protobuf:"varint,4,opt,name=min_rating_count,json=minRatingCount,proto3" json:"min_rating_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TopRatedLaptopsRequest) Reset() {
+	*x = TopRatedLaptopsRequest{}
+	mi := &file_laptop_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopRatedLaptopsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopRatedLaptopsRequest) ProtoMessage() {}
+
+func (x *TopRatedLaptopsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopRatedLaptopsRequest.ProtoReflect.Descriptor instead.
+func (*TopRatedLaptopsRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *TopRatedLaptopsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *TopRatedLaptopsRequest) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *TopRatedLaptopsRequest) GetFilter() *Filter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *TopRatedLaptopsRequest) GetMinRatingCount() uint32 {
+	if x != nil {
+		return x.MinRatingCount
+	}
+	return 0
+}
+
+type RankedLaptop struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rank          uint32                 `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Laptop        *Laptop                `protobuf:"bytes,2,opt,name=laptop,proto3" json:"laptop,omitempty"`
+	RatedCount    uint32                 `protobuf:"varint,3,opt,name=rated_count,json=ratedCount,proto3" json:"rated_count,omitempty"`
+	AverageScore  float64                `protobuf:"fixed64,4,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RankedLaptop) Reset() {
+	*x = RankedLaptop{}
+	mi := &file_laptop_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RankedLaptop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RankedLaptop) ProtoMessage() {}
+
+func (x *RankedLaptop) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RankedLaptop.ProtoReflect.Descriptor instead.
+func (*RankedLaptop) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RankedLaptop) GetRank() uint32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *RankedLaptop) GetLaptop() *Laptop {
+	if x != nil {
+		return x.Laptop
+	}
+	return nil
+}
+
+func (x *RankedLaptop) GetRatedCount() uint32 {
+	if x != nil {
+		return x.RatedCount
+	}
+	return 0
+}
+
+func (x *RankedLaptop) GetAverageScore() float64 {
+	if x != nil {
+		return x.AverageScore
+	}
+	return 0
+}
+
+type TopRatedLaptopsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Laptops       []*RankedLaptop        `protobuf:"bytes,1,rep,name=laptops,proto3" json:"laptops,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopRatedLaptopsResponse) Reset() {
+	*x = TopRatedLaptopsResponse{}
+	mi := &file_laptop_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopRatedLaptopsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopRatedLaptopsResponse) ProtoMessage() {}
+
+func (x *TopRatedLaptopsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopRatedLaptopsResponse.ProtoReflect.Descriptor instead.
+func (*TopRatedLaptopsResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *TopRatedLaptopsResponse) GetLaptops() []*RankedLaptop {
+	if x != nil {
+		return x.Laptops
+	}
+	return nil
+}
+
+type DeleteRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRatingRequest) Reset() {
+	*x = DeleteRatingRequest{}
+	mi := &file_laptop_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRatingRequest) ProtoMessage() {}
+
+func (x *DeleteRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRatingRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRatingRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DeleteRatingRequest) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+type DeleteRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	RatedCount    uint32                 `protobuf:"varint,2,opt,name=rated_count,json=ratedCount,proto3" json:"rated_count,omitempty"`
+	AverageScore  float64                `protobuf:"fixed64,3,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRatingResponse) Reset() {
+	*x = DeleteRatingResponse{}
+	mi := &file_laptop_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRatingResponse) ProtoMessage() {}
+
+func (x *DeleteRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRatingResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRatingResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DeleteRatingResponse) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+func (x *DeleteRatingResponse) GetRatedCount() uint32 {
+	if x != nil {
+		return x.RatedCount
+	}
+	return 0
+}
+
+func (x *DeleteRatingResponse) GetAverageScore() float64 {
+	if x != nil {
+		return x.AverageScore
+	}
+	return 0
+}
+
+type CreateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Score         float64                `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"` // also recorded as the author's rating
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReviewRequest) Reset() {
+	*x = CreateReviewRequest{}
+	mi := &file_laptop_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReviewRequest) ProtoMessage() {}
+
+func (x *CreateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReviewRequest.ProtoReflect.Descriptor instead.
+func (*CreateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CreateReviewRequest) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type CreateReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReviewResponse) Reset() {
+	*x = CreateReviewResponse{}
+	mi := &file_laptop_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReviewResponse) ProtoMessage() {}
+
+func (x *CreateReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReviewResponse.ProtoReflect.Descriptor instead.
+func (*CreateReviewResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CreateReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type ListReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LaptopId      string                 `protobuf:"bytes,1,opt,name=laptop_id,json=laptopId,proto3" json:"laptop_id,omitempty"`
+	Sort          Review_Sort            `protobuf:"varint,2,opt,name=sort,proto3,enum=pcbook.Review_Sort" json:"sort,omitempty"`
+	PageSize      uint32                 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsRequest) Reset() {
+	*x = ListReviewsRequest{}
+	mi := &file_laptop_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsRequest) ProtoMessage() {}
+
+func (x *ListReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListReviewsRequest) GetLaptopId() string {
+	if x != nil {
+		return x.LaptopId
+	}
+	return ""
+}
+
+func (x *ListReviewsRequest) GetSort() Review_Sort {
+	if x != nil {
+		return x.Sort
+	}
+	return Review_NEWEST
+}
+
+func (x *ListReviewsRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListReviewsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalCount    uint32                 `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsResponse) Reset() {
+	*x = ListReviewsResponse{}
+	mi := &file_laptop_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsResponse) ProtoMessage() {}
+
+func (x *ListReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListReviewsResponse) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+func (x *ListReviewsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListReviewsResponse) GetTotalCount() uint32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type VoteReviewHelpfulRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoteReviewHelpfulRequest) Reset() {
+	*x = VoteReviewHelpfulRequest{}
+	mi := &file_laptop_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoteReviewHelpfulRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteReviewHelpfulRequest) ProtoMessage() {}
+
+func (x *VoteReviewHelpfulRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteReviewHelpfulRequest.ProtoReflect.Descriptor instead.
+func (*VoteReviewHelpfulRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *VoteReviewHelpfulRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+type VoteReviewHelpfulResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoteReviewHelpfulResponse) Reset() {
+	*x = VoteReviewHelpfulResponse{}
+	mi := &file_laptop_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoteReviewHelpfulResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteReviewHelpfulResponse) ProtoMessage() {}
+
+func (x *VoteReviewHelpfulResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteReviewHelpfulResponse.ProtoReflect.Descriptor instead.
+func (*VoteReviewHelpfulResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *VoteReviewHelpfulResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type ModerateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	State         Review_State           `protobuf:"varint,2,opt,name=state,proto3,enum=pcbook.Review_State" json:"state,omitempty"` // HIDDEN or APPROVED
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateReviewRequest) Reset() {
+	*x = ModerateReviewRequest{}
+	mi := &file_laptop_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateReviewRequest) ProtoMessage() {}
+
+func (x *ModerateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateReviewRequest.ProtoReflect.Descriptor instead.
+func (*ModerateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ModerateReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *ModerateReviewRequest) GetState() Review_State {
+	if x != nil {
+		return x.State
+	}
+	return Review_UNKNOWN
+}
+
+type ModerateReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateReviewResponse) Reset() {
+	*x = ModerateReviewResponse{}
+	mi := &file_laptop_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateReviewResponse) ProtoMessage() {}
+
+func (x *ModerateReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateReviewResponse.ProtoReflect.Descriptor instead.
+func (*ModerateReviewResponse) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ModerateReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type SendLaptopInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Laptop        *LaptopInfo            `protobuf:"bytes,1,opt,name=laptop,proto3" json:"laptop,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendLaptopInfoRequest) Reset() {
+	*x = SendLaptopInfoRequest{}
+	mi := &file_laptop_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendLaptopInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendLaptopInfoRequest) ProtoMessage() {}
+
+func (x *SendLaptopInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_laptop_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendLaptopInfoRequest.ProtoReflect.Descriptor instead.
+func (*SendLaptopInfoRequest) Descriptor() ([]byte, []int) {
+	return file_laptop_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SendLaptopInfoRequest) GetLaptop() *LaptopInfo {
+	if x != nil {
+		return x.Laptop
+	}
+	return nil
+}
+
+type SendLaptopInfoResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Msg           string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -549,7 +2618,7 @@ type SendLaptopInfoResponse struct {
 
 func (x *SendLaptopInfoResponse) Reset() {
 	*x = SendLaptopInfoResponse{}
-	mi := &file_laptop_service_proto_msgTypes[10]
+	mi := &file_laptop_service_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -561,7 +2630,7 @@ func (x *SendLaptopInfoResponse) String() string {
 func (*SendLaptopInfoResponse) ProtoMessage() {}
 
 func (x *SendLaptopInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_laptop_service_proto_msgTypes[10]
+	mi := &file_laptop_service_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -574,7 +2643,7 @@ func (x *SendLaptopInfoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendLaptopInfoResponse.ProtoReflect.Descriptor instead.
 func (*SendLaptopInfoResponse) Descriptor() ([]byte, []int) {
-	return file_laptop_service_proto_rawDescGZIP(), []int{10}
+	return file_laptop_service_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *SendLaptopInfoResponse) GetMsg() string {
@@ -588,11 +2657,18 @@ var File_laptop_service_proto protoreflect.FileDescriptor
 
 const file_laptop_service_proto_rawDesc = "" +
 	"\n" +
-	"\x14laptop_service.proto\x12\x06pcbook\x1a\flaptop.proto\x1a\ffilter.proto\x1a\x10laptopInfo.proto\x1a\x1cgoogle/api/annotations.proto\"=\n" +
+	"\x14laptop_service.proto\x12\x06pcbook\x1a\flaptop.proto\x1a\ffilter.proto\x1a\x10laptopInfo.proto\x1a\freview.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"=\n" +
 	"\x13CreateLaptopRequest\x12&\n" +
 	"\x06laptop\x18\x01 \x01(\v2\x0e.pcbook.LaptopR\x06laptop\"&\n" +
 	"\x14CreateLaptopResponse\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\"=\n" +
+	"\x13UpdateLaptopRequest\x12&\n" +
+	"\x06laptop\x18\x01 \x01(\v2\x0e.pcbook.LaptopR\x06laptop\">\n" +
+	"\x14UpdateLaptopResponse\x12&\n" +
+	"\x06laptop\x18\x01 \x01(\v2\x0e.pcbook.LaptopR\x06laptop\"2\n" +
+	"\x13DeleteLaptopRequest\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\"\x16\n" +
+	"\x14DeleteLaptopResponse\"=\n" +
 	"\x13SearchLaptopRequest\x12&\n" +
 	"\x06filter\x18\x01 \x01(\v2\x0e.pcbook.FilterR\x06filter\">\n" +
 	"\x14SearchLaptopResponse\x12&\n" +
@@ -601,32 +2677,180 @@ const file_laptop_service_proto_rawDesc = "" +
 	"\x04info\x18\x01 \x01(\v2\x11.pcbook.ImageInfoH\x00R\x04info\x12\x1f\n" +
 	"\n" +
 	"chunk_data\x18\x02 \x01(\fH\x00R\tchunkDataB\x06\n" +
-	"\x04data\"G\n" +
+	"\x04data\"\x84\x02\n" +
 	"\tImageInfo\x12\x1b\n" +
 	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\x12\x1d\n" +
 	"\n" +
-	"image_type\x18\x02 \x01(\tR\timageType\"9\n" +
+	"image_type\x18\x02 \x01(\tR\timageType\x12\x1a\n" +
+	"\bchecksum\x18\x03 \x01(\tR\bchecksum\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\x12\x1a\n" +
+	"\bposition\x18\x05 \x01(\rR\bposition\x12\x1d\n" +
+	"\n" +
+	"is_primary\x18\x06 \x01(\bR\tisPrimary\x12\x19\n" +
+	"\balt_text\x18\a \x01(\tR\aaltText\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"Q\n" +
 	"\x13UploadImageResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04size\x18\x02 \x01(\rR\x04size\"F\n" +
+	"\x04size\x18\x02 \x01(\rR\x04size\x12\x16\n" +
+	"\x06digest\x18\x03 \x01(\tR\x06digest\"_\n" +
+	"\x17StartImageUploadRequest\x12%\n" +
+	"\x04info\x18\x01 \x01(\v2\x11.pcbook.ImageInfoR\x04info\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\rR\ttotalSize\"\x91\x01\n" +
+	"\x18StartImageUploadResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x1d\n" +
+	"\n" +
+	"chunk_size\x18\x02 \x01(\rR\tchunkSize\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"m\n" +
+	"\x17UploadImageChunkRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\rR\x06offset\x12\x1d\n" +
+	"\n" +
+	"chunk_data\x18\x03 \x01(\fR\tchunkData\"b\n" +
+	"\x18UploadImageChunkResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12)\n" +
+	"\x10committed_offset\x18\x02 \x01(\rR\x0fcommittedOffset\"4\n" +
+	"\x15GetImageUploadRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\"\xba\x01\n" +
+	"\x16GetImageUploadResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12)\n" +
+	"\x10committed_offset\x18\x02 \x01(\rR\x0fcommittedOffset\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\rR\ttotalSize\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"7\n" +
+	"\x18CommitImageUploadRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\"0\n" +
+	"\x11ListImagesRequest\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\"?\n" +
+	"\x12ListImagesResponse\x12)\n" +
+	"\x06images\x18\x01 \x03(\v2\x11.pcbook.ImageInfoR\x06images\"P\n" +
+	"\x14ReorderImagesRequest\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\x12\x1b\n" +
+	"\timage_ids\x18\x02 \x03(\tR\bimageIds\"B\n" +
+	"\x15ReorderImagesResponse\x12)\n" +
+	"\x06images\x18\x01 \x03(\v2\x11.pcbook.ImageInfoR\x06images\"P\n" +
+	"\x16SetPrimaryImageRequest\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\x12\x19\n" +
+	"\bimage_id\x18\x02 \x01(\tR\aimageId\"D\n" +
+	"\x17SetPrimaryImageResponse\x12)\n" +
+	"\x06images\x18\x01 \x03(\v2\x11.pcbook.ImageInfoR\x06images\"5\n" +
+	"\x1aCollectImageGarbageRequest\x12\x17\n" +
+	"\adry_run\x18\x01 \x01(\bR\x06dryRun\"\xa5\x01\n" +
+	"\x1bCollectImageGarbageResponse\x12\x17\n" +
+	"\adry_run\x18\x01 \x01(\bR\x06dryRun\x12!\n" +
+	"\forphan_files\x18\x02 \x03(\tR\vorphanFiles\x12%\n" +
+	"\x0emissing_images\x18\x03 \x03(\tR\rmissingImages\x12#\n" +
+	"\rorphan_images\x18\x04 \x03(\tR\forphanImages\"F\n" +
 	"\x11RateLaptopRequest\x12\x1b\n" +
 	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\x12\x14\n" +
-	"\x05score\x18\x02 \x01(\x01R\x05score\"w\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"\xa0\x01\n" +
 	"\x12RateLaptopResponse\x12\x1b\n" +
 	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\x12\x1f\n" +
 	"\vrated_count\x18\x02 \x01(\rR\n" +
 	"ratedCount\x12#\n" +
-	"\raverage_score\x18\x03 \x01(\x01R\faverageScore\"C\n" +
+	"\raverage_score\x18\x03 \x01(\x01R\faverageScore\x12'\n" +
+	"\x05error\x18\x04 \x01(\v2\x11.pcbook.RateErrorR\x05error\"9\n" +
+	"\tRateError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\rR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"9\n" +
+	"\vScoreBucket\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x01R\x05score\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\"\xcf\x01\n" +
+	"\fLaptopRating\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\x12\x1f\n" +
+	"\vrated_count\x18\x02 \x01(\rR\n" +
+	"ratedCount\x12#\n" +
+	"\raverage_score\x18\x03 \x01(\x01R\faverageScore\x12)\n" +
+	"\x10bayesian_average\x18\x04 \x01(\x01R\x0fbayesianAverage\x121\n" +
+	"\thistogram\x18\x05 \x03(\v2\x13.pcbook.ScoreBucketR\thistogram\"5\n" +
+	"\x16GetLaptopRatingRequest\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\"G\n" +
+	"\x17GetLaptopRatingResponse\x12,\n" +
+	"\x06rating\x18\x01 \x01(\v2\x14.pcbook.LaptopRatingR\x06rating\"8\n" +
+	"\x17GetLaptopRatingsRequest\x12\x1d\n" +
+	"\n" +
+	"laptop_ids\x18\x01 \x03(\tR\tlaptopIds\"J\n" +
+	"\x18GetLaptopRatingsResponse\x12.\n" +
+	"\aratings\x18\x01 \x03(\v2\x14.pcbook.LaptopRatingR\aratings\"\x96\x01\n" +
+	"\x16TopRatedLaptopsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\rR\x05limit\x12\x14\n" +
+	"\x05brand\x18\x02 \x01(\tR\x05brand\x12&\n" +
+	"\x06filter\x18\x03 \x01(\v2\x0e.pcbook.FilterR\x06filter\x12(\n" +
+	"\x10min_rating_count\x18\x04 \x01(\rR\x0eminRatingCount\"\x90\x01\n" +
+	"\fRankedLaptop\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\rR\x04rank\x12&\n" +
+	"\x06laptop\x18\x02 \x01(\v2\x0e.pcbook.LaptopR\x06laptop\x12\x1f\n" +
+	"\vrated_count\x18\x03 \x01(\rR\n" +
+	"ratedCount\x12#\n" +
+	"\raverage_score\x18\x04 \x01(\x01R\faverageScore\"I\n" +
+	"\x17TopRatedLaptopsResponse\x12.\n" +
+	"\alaptops\x18\x01 \x03(\v2\x14.pcbook.RankedLaptopR\alaptops\"2\n" +
+	"\x13DeleteRatingRequest\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\"y\n" +
+	"\x14DeleteRatingResponse\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\x12\x1f\n" +
+	"\vrated_count\x18\x02 \x01(\rR\n" +
+	"ratedCount\x12#\n" +
+	"\raverage_score\x18\x03 \x01(\x01R\faverageScore\"r\n" +
+	"\x13CreateReviewRequest\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\">\n" +
+	"\x14CreateReviewResponse\x12&\n" +
+	"\x06review\x18\x01 \x01(\v2\x0e.pcbook.ReviewR\x06review\"\x96\x01\n" +
+	"\x12ListReviewsRequest\x12\x1b\n" +
+	"\tlaptop_id\x18\x01 \x01(\tR\blaptopId\x12'\n" +
+	"\x04sort\x18\x02 \x01(\x0e2\x13.pcbook.Review.SortR\x04sort\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\rR\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\x88\x01\n" +
+	"\x13ListReviewsResponse\x12(\n" +
+	"\areviews\x18\x01 \x03(\v2\x0e.pcbook.ReviewR\areviews\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1f\n" +
+	"\vtotal_count\x18\x03 \x01(\rR\n" +
+	"totalCount\"7\n" +
+	"\x18VoteReviewHelpfulRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\"C\n" +
+	"\x19VoteReviewHelpfulResponse\x12&\n" +
+	"\x06review\x18\x01 \x01(\v2\x0e.pcbook.ReviewR\x06review\"`\n" +
+	"\x15ModerateReviewRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\x12*\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x14.pcbook.Review.StateR\x05state\"@\n" +
+	"\x16ModerateReviewResponse\x12&\n" +
+	"\x06review\x18\x01 \x01(\v2\x0e.pcbook.ReviewR\x06review\"C\n" +
 	"\x15SendLaptopInfoRequest\x12*\n" +
 	"\x06laptop\x18\x01 \x01(\v2\x12.pcbook.LaptopInfoR\x06laptop\"*\n" +
 	"\x16SendLaptopInfoResponse\x12\x10\n" +
-	"\x03msg\x18\x01 \x01(\tR\x03msg2\x98\x04\n" +
+	"\x03msg\x18\x01 \x01(\tR\x03msg2\xf3\x14\n" +
 	"\rLaptopService\x12d\n" +
-	"\fCreateLaptop\x12\x1b.pcbook.CreateLaptopRequest\x1a\x1c.pcbook.CreateLaptopResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/laptop/create\x12c\n" +
+	"\fCreateLaptop\x12\x1b.pcbook.CreateLaptopRequest\x1a\x1c.pcbook.CreateLaptopResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/laptop/create\x12n\n" +
+	"\fUpdateLaptop\x12\x1b.pcbook.UpdateLaptopRequest\x1a\x1c.pcbook.UpdateLaptopResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x06laptop\x1a\x13/laptop/{laptop.id}\x12f\n" +
+	"\fDeleteLaptop\x12\x1b.pcbook.DeleteLaptopRequest\x1a\x1c.pcbook.DeleteLaptopResponse\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/laptop/{laptop_id}\x12c\n" +
 	"\fSearchLaptop\x12\x1b.pcbook.SearchLaptopRequest\x1a\x1c.pcbook.SearchLaptopResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/laptop/search0\x01\x12i\n" +
-	"\vUploadImage\x12\x1a.pcbook.UploadImageRequest\x1a\x1b.pcbook.UploadImageResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/laptop/uplaod_image(\x01\x12`\n" +
+	"\vUploadImage\x12\x1a.pcbook.UploadImageRequest\x1a\x1b.pcbook.UploadImageResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/laptop/uplaod_image(\x01\x12|\n" +
+	"\x10StartImageUpload\x12\x1f.pcbook.StartImageUploadRequest\x1a .pcbook.StartImageUploadResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/laptop/upload_image/start\x12|\n" +
+	"\x10UploadImageChunk\x12\x1f.pcbook.UploadImageChunkRequest\x1a .pcbook.UploadImageChunkResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/laptop/upload_image/chunk\x12y\n" +
+	"\x0eGetImageUpload\x12\x1d.pcbook.GetImageUploadRequest\x1a\x1e.pcbook.GetImageUploadResponse\"(\x82\xd3\xe4\x93\x02\"\x12 /laptop/upload_image/{upload_id}\x12z\n" +
+	"\x11CommitImageUpload\x12 .pcbook.CommitImageUploadRequest\x1a\x1b.pcbook.UploadImageResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/laptop/upload_image/commit\x12g\n" +
+	"\n" +
+	"ListImages\x12\x19.pcbook.ListImagesRequest\x1a\x1a.pcbook.ListImagesResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/laptop/{laptop_id}/images\x12{\n" +
+	"\rReorderImages\x12\x1c.pcbook.ReorderImagesRequest\x1a\x1d.pcbook.ReorderImagesResponse\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/laptop/{laptop_id}/images/reorder\x12\x81\x01\n" +
+	"\x0fSetPrimaryImage\x12\x1e.pcbook.SetPrimaryImageRequest\x1a\x1f.pcbook.SetPrimaryImageResponse\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/laptop/{laptop_id}/images/primary\x12{\n" +
+	"\x13CollectImageGarbage\x12\".pcbook.CollectImageGarbageRequest\x1a#.pcbook.CollectImageGarbageResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/admin/images/gc\x12`\n" +
 	"\n" +
-	"RateLaptop\x12\x19.pcbook.RateLaptopRequest\x1a\x1a.pcbook.RateLaptopResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/laptop/rate(\x010\x01\x12o\n" +
+	"RateLaptop\x12\x19.pcbook.RateLaptopRequest\x1a\x1a.pcbook.RateLaptopResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/laptop/rate(\x010\x01\x12v\n" +
+	"\x0fGetLaptopRating\x12\x1e.pcbook.GetLaptopRatingRequest\x1a\x1f.pcbook.GetLaptopRatingResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/laptop/{laptop_id}/rating\x12n\n" +
+	"\x10GetLaptopRatings\x12\x1f.pcbook.GetLaptopRatingsRequest\x1a .pcbook.GetLaptopRatingsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/laptop/ratings\x12m\n" +
+	"\x0fTopRatedLaptops\x12\x1e.pcbook.TopRatedLaptopsRequest\x1a\x1f.pcbook.TopRatedLaptopsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/laptop/top_rated\x12m\n" +
+	"\fDeleteRating\x12\x1b.pcbook.DeleteRatingRequest\x1a\x1c.pcbook.DeleteRatingResponse\"\"\x82\xd3\xe4\x93\x02\x1c*\x1a/laptop/{laptop_id}/rating\x12q\n" +
+	"\fCreateReview\x12\x1b.pcbook.CreateReviewRequest\x1a\x1c.pcbook.CreateReviewResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/laptop/{laptop_id}/reviews\x12k\n" +
+	"\vListReviews\x12\x1a.pcbook.ListReviewsRequest\x1a\x1b.pcbook.ListReviewsResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/laptop/{laptop_id}/reviews\x12\x80\x01\n" +
+	"\x11VoteReviewHelpful\x12 .pcbook.VoteReviewHelpfulRequest\x1a!.pcbook.VoteReviewHelpfulResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/review/{review_id}/helpful\x12~\n" +
+	"\x0eModerateReview\x12\x1d.pcbook.ModerateReviewRequest\x1a\x1e.pcbook.ModerateReviewResponse\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/admin/review/{review_id}/moderate\x12o\n" +
 	"\x0eSendLaptopInfo\x12\x1d.pcbook.SendLaptopInfoRequest\x1a\x1e.pcbook.SendLaptopInfoResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/laptop/send_info(\x01B#Z!github.com/JeongWoo-Seo/pcBook/pbb\x06proto3"
 
 var (
@@ -641,44 +2865,145 @@ func file_laptop_service_proto_rawDescGZIP() []byte {
 	return file_laptop_service_proto_rawDescData
 }
 
-var file_laptop_service_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_laptop_service_proto_msgTypes = make([]protoimpl.MessageInfo, 50)
 var file_laptop_service_proto_goTypes = []any{
-	(*CreateLaptopRequest)(nil),    // 0: pcbook.CreateLaptopRequest
-	(*CreateLaptopResponse)(nil),   // 1: pcbook.CreateLaptopResponse
-	(*SearchLaptopRequest)(nil),    // 2: pcbook.SearchLaptopRequest
-	(*SearchLaptopResponse)(nil),   // 3: pcbook.SearchLaptopResponse
-	(*UploadImageRequest)(nil),     // 4: pcbook.UploadImageRequest
-	(*ImageInfo)(nil),              // 5: pcbook.ImageInfo
-	(*UploadImageResponse)(nil),    // 6: pcbook.UploadImageResponse
-	(*RateLaptopRequest)(nil),      // 7: pcbook.RateLaptopRequest
-	(*RateLaptopResponse)(nil),     // 8: pcbook.RateLaptopResponse
-	(*SendLaptopInfoRequest)(nil),  // 9: pcbook.SendLaptopInfoRequest
-	(*SendLaptopInfoResponse)(nil), // 10: pcbook.SendLaptopInfoResponse
-	(*Laptop)(nil),                 // 11: pcbook.Laptop
-	(*Filter)(nil),                 // 12: pcbook.Filter
-	(*LaptopInfo)(nil),             // 13: pcbook.LaptopInfo
+	(*CreateLaptopRequest)(nil),         // 0: pcbook.CreateLaptopRequest
+	(*CreateLaptopResponse)(nil),        // 1: pcbook.CreateLaptopResponse
+	(*UpdateLaptopRequest)(nil),         // 2: pcbook.UpdateLaptopRequest
+	(*UpdateLaptopResponse)(nil),        // 3: pcbook.UpdateLaptopResponse
+	(*DeleteLaptopRequest)(nil),         // 4: pcbook.DeleteLaptopRequest
+	(*DeleteLaptopResponse)(nil),        // 5: pcbook.DeleteLaptopResponse
+	(*SearchLaptopRequest)(nil),         // 6: pcbook.SearchLaptopRequest
+	(*SearchLaptopResponse)(nil),        // 7: pcbook.SearchLaptopResponse
+	(*UploadImageRequest)(nil),          // 8: pcbook.UploadImageRequest
+	(*ImageInfo)(nil),                   // 9: pcbook.ImageInfo
+	(*UploadImageResponse)(nil),         // 10: pcbook.UploadImageResponse
+	(*StartImageUploadRequest)(nil),     // 11: pcbook.StartImageUploadRequest
+	(*StartImageUploadResponse)(nil),    // 12: pcbook.StartImageUploadResponse
+	(*UploadImageChunkRequest)(nil),     // 13: pcbook.UploadImageChunkRequest
+	(*UploadImageChunkResponse)(nil),    // 14: pcbook.UploadImageChunkResponse
+	(*GetImageUploadRequest)(nil),       // 15: pcbook.GetImageUploadRequest
+	(*GetImageUploadResponse)(nil),      // 16: pcbook.GetImageUploadResponse
+	(*CommitImageUploadRequest)(nil),    // 17: pcbook.CommitImageUploadRequest
+	(*ListImagesRequest)(nil),           // 18: pcbook.ListImagesRequest
+	(*ListImagesResponse)(nil),          // 19: pcbook.ListImagesResponse
+	(*ReorderImagesRequest)(nil),        // 20: pcbook.ReorderImagesRequest
+	(*ReorderImagesResponse)(nil),       // 21: pcbook.ReorderImagesResponse
+	(*SetPrimaryImageRequest)(nil),      // 22: pcbook.SetPrimaryImageRequest
+	(*SetPrimaryImageResponse)(nil),     // 23: pcbook.SetPrimaryImageResponse
+	(*CollectImageGarbageRequest)(nil),  // 24: pcbook.CollectImageGarbageRequest
+	(*CollectImageGarbageResponse)(nil), // 25: pcbook.CollectImageGarbageResponse
+	(*RateLaptopRequest)(nil),           // 26: pcbook.RateLaptopRequest
+	(*RateLaptopResponse)(nil),          // 27: pcbook.RateLaptopResponse
+	(*RateError)(nil),                   // 28: pcbook.RateError
+	(*ScoreBucket)(nil),                 // 29: pcbook.ScoreBucket
+	(*LaptopRating)(nil),                // 30: pcbook.LaptopRating
+	(*GetLaptopRatingRequest)(nil),      // 31: pcbook.GetLaptopRatingRequest
+	(*GetLaptopRatingResponse)(nil),     // 32: pcbook.GetLaptopRatingResponse
+	(*GetLaptopRatingsRequest)(nil),     // 33: pcbook.GetLaptopRatingsRequest
+	(*GetLaptopRatingsResponse)(nil),    // 34: pcbook.GetLaptopRatingsResponse
+	(*TopRatedLaptopsRequest)(nil),      // 35: pcbook.TopRatedLaptopsRequest
+	(*RankedLaptop)(nil),                // 36: pcbook.RankedLaptop
+	(*TopRatedLaptopsResponse)(nil),     // 37: pcbook.TopRatedLaptopsResponse
+	(*DeleteRatingRequest)(nil),         // 38: pcbook.DeleteRatingRequest
+	(*DeleteRatingResponse)(nil),        // 39: pcbook.DeleteRatingResponse
+	(*CreateReviewRequest)(nil),         // 40: pcbook.CreateReviewRequest
+	(*CreateReviewResponse)(nil),        // 41: pcbook.CreateReviewResponse
+	(*ListReviewsRequest)(nil),          // 42: pcbook.ListReviewsRequest
+	(*ListReviewsResponse)(nil),         // 43: pcbook.ListReviewsResponse
+	(*VoteReviewHelpfulRequest)(nil),    // 44: pcbook.VoteReviewHelpfulRequest
+	(*VoteReviewHelpfulResponse)(nil),   // 45: pcbook.VoteReviewHelpfulResponse
+	(*ModerateReviewRequest)(nil),       // 46: pcbook.ModerateReviewRequest
+	(*ModerateReviewResponse)(nil),      // 47: pcbook.ModerateReviewResponse
+	(*SendLaptopInfoRequest)(nil),       // 48: pcbook.SendLaptopInfoRequest
+	(*SendLaptopInfoResponse)(nil),      // 49: pcbook.SendLaptopInfoResponse
+	(*Laptop)(nil),                      // 50: pcbook.Laptop
+	(*Filter)(nil),                      // 51: pcbook.Filter
+	(*timestamppb.Timestamp)(nil),       // 52: google.protobuf.Timestamp
+	(*Review)(nil),                      // 53: pcbook.Review
+	(Review_Sort)(0),                    // 54: pcbook.Review.Sort
+	(Review_State)(0),                   // 55: pcbook.Review.State
+	(*LaptopInfo)(nil),                  // 56: pcbook.LaptopInfo
 }
 var file_laptop_service_proto_depIdxs = []int32{
-	11, // 0: pcbook.CreateLaptopRequest.laptop:type_name -> pcbook.Laptop
-	12, // 1: pcbook.SearchLaptopRequest.filter:type_name -> pcbook.Filter
-	11, // 2: pcbook.SearchLaptopResponse.laptop:type_name -> pcbook.Laptop
-	5,  // 3: pcbook.UploadImageRequest.info:type_name -> pcbook.ImageInfo
-	13, // 4: pcbook.SendLaptopInfoRequest.laptop:type_name -> pcbook.LaptopInfo
-	0,  // 5: pcbook.LaptopService.CreateLaptop:input_type -> pcbook.CreateLaptopRequest
-	2,  // 6: pcbook.LaptopService.SearchLaptop:input_type -> pcbook.SearchLaptopRequest
-	4,  // 7: pcbook.LaptopService.UploadImage:input_type -> pcbook.UploadImageRequest
-	7,  // 8: pcbook.LaptopService.RateLaptop:input_type -> pcbook.RateLaptopRequest
-	9,  // 9: pcbook.LaptopService.SendLaptopInfo:input_type -> pcbook.SendLaptopInfoRequest
-	1,  // 10: pcbook.LaptopService.CreateLaptop:output_type -> pcbook.CreateLaptopResponse
-	3,  // 11: pcbook.LaptopService.SearchLaptop:output_type -> pcbook.SearchLaptopResponse
-	6,  // 12: pcbook.LaptopService.UploadImage:output_type -> pcbook.UploadImageResponse
-	8,  // 13: pcbook.LaptopService.RateLaptop:output_type -> pcbook.RateLaptopResponse
-	10, // 14: pcbook.LaptopService.SendLaptopInfo:output_type -> pcbook.SendLaptopInfoResponse
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	50, // 0: pcbook.CreateLaptopRequest.laptop:type_name -> pcbook.Laptop
+	50, // 1: pcbook.UpdateLaptopRequest.laptop:type_name -> pcbook.Laptop
+	50, // 2: pcbook.UpdateLaptopResponse.laptop:type_name -> pcbook.Laptop
+	51, // 3: pcbook.SearchLaptopRequest.filter:type_name -> pcbook.Filter
+	50, // 4: pcbook.SearchLaptopResponse.laptop:type_name -> pcbook.Laptop
+	9,  // 5: pcbook.UploadImageRequest.info:type_name -> pcbook.ImageInfo
+	52, // 6: pcbook.ImageInfo.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 7: pcbook.StartImageUploadRequest.info:type_name -> pcbook.ImageInfo
+	52, // 8: pcbook.StartImageUploadResponse.expires_at:type_name -> google.protobuf.Timestamp
+	52, // 9: pcbook.GetImageUploadResponse.expires_at:type_name -> google.protobuf.Timestamp
+	9,  // 10: pcbook.ListImagesResponse.images:type_name -> pcbook.ImageInfo
+	9,  // 11: pcbook.ReorderImagesResponse.images:type_name -> pcbook.ImageInfo
+	9,  // 12: pcbook.SetPrimaryImageResponse.images:type_name -> pcbook.ImageInfo
+	28, // 13: pcbook.RateLaptopResponse.error:type_name -> pcbook.RateError
+	29, // 14: pcbook.LaptopRating.histogram:type_name -> pcbook.ScoreBucket
+	30, // 15: pcbook.GetLaptopRatingResponse.rating:type_name -> pcbook.LaptopRating
+	30, // 16: pcbook.GetLaptopRatingsResponse.ratings:type_name -> pcbook.LaptopRating
+	51, // 17: pcbook.TopRatedLaptopsRequest.filter:type_name -> pcbook.Filter
+	50, // 18: pcbook.RankedLaptop.laptop:type_name -> pcbook.Laptop
+	36, // 19: pcbook.TopRatedLaptopsResponse.laptops:type_name -> pcbook.RankedLaptop
+	53, // 20: pcbook.CreateReviewResponse.review:type_name -> pcbook.Review
+	54, // 21: pcbook.ListReviewsRequest.sort:type_name -> pcbook.Review.Sort
+	53, // 22: pcbook.ListReviewsResponse.reviews:type_name -> pcbook.Review
+	53, // 23: pcbook.VoteReviewHelpfulResponse.review:type_name -> pcbook.Review
+	55, // 24: pcbook.ModerateReviewRequest.state:type_name -> pcbook.Review.State
+	53, // 25: pcbook.ModerateReviewResponse.review:type_name -> pcbook.Review
+	56, // 26: pcbook.SendLaptopInfoRequest.laptop:type_name -> pcbook.LaptopInfo
+	0,  // 27: pcbook.LaptopService.CreateLaptop:input_type -> pcbook.CreateLaptopRequest
+	2,  // 28: pcbook.LaptopService.UpdateLaptop:input_type -> pcbook.UpdateLaptopRequest
+	4,  // 29: pcbook.LaptopService.DeleteLaptop:input_type -> pcbook.DeleteLaptopRequest
+	6,  // 30: pcbook.LaptopService.SearchLaptop:input_type -> pcbook.SearchLaptopRequest
+	8,  // 31: pcbook.LaptopService.UploadImage:input_type -> pcbook.UploadImageRequest
+	11, // 32: pcbook.LaptopService.StartImageUpload:input_type -> pcbook.StartImageUploadRequest
+	13, // 33: pcbook.LaptopService.UploadImageChunk:input_type -> pcbook.UploadImageChunkRequest
+	15, // 34: pcbook.LaptopService.GetImageUpload:input_type -> pcbook.GetImageUploadRequest
+	17, // 35: pcbook.LaptopService.CommitImageUpload:input_type -> pcbook.CommitImageUploadRequest
+	18, // 36: pcbook.LaptopService.ListImages:input_type -> pcbook.ListImagesRequest
+	20, // 37: pcbook.LaptopService.ReorderImages:input_type -> pcbook.ReorderImagesRequest
+	22, // 38: pcbook.LaptopService.SetPrimaryImage:input_type -> pcbook.SetPrimaryImageRequest
+	24, // 39: pcbook.LaptopService.CollectImageGarbage:input_type -> pcbook.CollectImageGarbageRequest
+	26, // 40: pcbook.LaptopService.RateLaptop:input_type -> pcbook.RateLaptopRequest
+	31, // 41: pcbook.LaptopService.GetLaptopRating:input_type -> pcbook.GetLaptopRatingRequest
+	33, // 42: pcbook.LaptopService.GetLaptopRatings:input_type -> pcbook.GetLaptopRatingsRequest
+	35, // 43: pcbook.LaptopService.TopRatedLaptops:input_type -> pcbook.TopRatedLaptopsRequest
+	38, // 44: pcbook.LaptopService.DeleteRating:input_type -> pcbook.DeleteRatingRequest
+	40, // 45: pcbook.LaptopService.CreateReview:input_type -> pcbook.CreateReviewRequest
+	42, // 46: pcbook.LaptopService.ListReviews:input_type -> pcbook.ListReviewsRequest
+	44, // 47: pcbook.LaptopService.VoteReviewHelpful:input_type -> pcbook.VoteReviewHelpfulRequest
+	46, // 48: pcbook.LaptopService.ModerateReview:input_type -> pcbook.ModerateReviewRequest
+	48, // 49: pcbook.LaptopService.SendLaptopInfo:input_type -> pcbook.SendLaptopInfoRequest
+	1,  // 50: pcbook.LaptopService.CreateLaptop:output_type -> pcbook.CreateLaptopResponse
+	3,  // 51: pcbook.LaptopService.UpdateLaptop:output_type -> pcbook.UpdateLaptopResponse
+	5,  // 52: pcbook.LaptopService.DeleteLaptop:output_type -> pcbook.DeleteLaptopResponse
+	7,  // 53: pcbook.LaptopService.SearchLaptop:output_type -> pcbook.SearchLaptopResponse
+	10, // 54: pcbook.LaptopService.UploadImage:output_type -> pcbook.UploadImageResponse
+	12, // 55: pcbook.LaptopService.StartImageUpload:output_type -> pcbook.StartImageUploadResponse
+	14, // 56: pcbook.LaptopService.UploadImageChunk:output_type -> pcbook.UploadImageChunkResponse
+	16, // 57: pcbook.LaptopService.GetImageUpload:output_type -> pcbook.GetImageUploadResponse
+	10, // 58: pcbook.LaptopService.CommitImageUpload:output_type -> pcbook.UploadImageResponse
+	19, // 59: pcbook.LaptopService.ListImages:output_type -> pcbook.ListImagesResponse
+	21, // 60: pcbook.LaptopService.ReorderImages:output_type -> pcbook.ReorderImagesResponse
+	23, // 61: pcbook.LaptopService.SetPrimaryImage:output_type -> pcbook.SetPrimaryImageResponse
+	25, // 62: pcbook.LaptopService.CollectImageGarbage:output_type -> pcbook.CollectImageGarbageResponse
+	27, // 63: pcbook.LaptopService.RateLaptop:output_type -> pcbook.RateLaptopResponse
+	32, // 64: pcbook.LaptopService.GetLaptopRating:output_type -> pcbook.GetLaptopRatingResponse
+	34, // 65: pcbook.LaptopService.GetLaptopRatings:output_type -> pcbook.GetLaptopRatingsResponse
+	37, // 66: pcbook.LaptopService.TopRatedLaptops:output_type -> pcbook.TopRatedLaptopsResponse
+	39, // 67: pcbook.LaptopService.DeleteRating:output_type -> pcbook.DeleteRatingResponse
+	41, // 68: pcbook.LaptopService.CreateReview:output_type -> pcbook.CreateReviewResponse
+	43, // 69: pcbook.LaptopService.ListReviews:output_type -> pcbook.ListReviewsResponse
+	45, // 70: pcbook.LaptopService.VoteReviewHelpful:output_type -> pcbook.VoteReviewHelpfulResponse
+	47, // 71: pcbook.LaptopService.ModerateReview:output_type -> pcbook.ModerateReviewResponse
+	49, // 72: pcbook.LaptopService.SendLaptopInfo:output_type -> pcbook.SendLaptopInfoResponse
+	50, // [50:73] is the sub-list for method output_type
+	27, // [27:50] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_laptop_service_proto_init() }
@@ -689,7 +3014,8 @@ func file_laptop_service_proto_init() {
 	file_laptop_proto_init()
 	file_filter_proto_init()
 	file_laptopInfo_proto_init()
-	file_laptop_service_proto_msgTypes[4].OneofWrappers = []any{
+	file_review_proto_init()
+	file_laptop_service_proto_msgTypes[8].OneofWrappers = []any{
 		(*UploadImageRequest_Info)(nil),
 		(*UploadImageRequest_ChunkData)(nil),
 	}
@@ -699,7 +3025,7 @@ func file_laptop_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_laptop_service_proto_rawDesc), len(file_laptop_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   50,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
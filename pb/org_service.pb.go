@@ -0,0 +1,543 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: org_service.proto
+
+package pb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Org struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // 2-32 lowercase letters, digits or '-'
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Org) Reset() {
+	*x = Org{}
+	mi := &file_org_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Org) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Org) ProtoMessage() {}
+
+func (x *Org) ProtoReflect() protoreflect.Message {
+	mi := &file_org_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Org.ProtoReflect.Descriptor instead.
+func (*Org) Descriptor() ([]byte, []int) {
+	return file_org_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Org) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Org) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Org) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateOrgRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrgRequest) Reset() {
+	*x = CreateOrgRequest{}
+	mi := &file_org_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrgRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrgRequest) ProtoMessage() {}
+
+func (x *CreateOrgRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrgRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrgRequest) Descriptor() ([]byte, []int) {
+	return file_org_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateOrgRequest) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *CreateOrgRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateOrgResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Org           *Org                   `protobuf:"bytes,1,opt,name=org,proto3" json:"org,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrgResponse) Reset() {
+	*x = CreateOrgResponse{}
+	mi := &file_org_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrgResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrgResponse) ProtoMessage() {}
+
+func (x *CreateOrgResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_org_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrgResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrgResponse) Descriptor() ([]byte, []int) {
+	return file_org_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateOrgResponse) GetOrg() *Org {
+	if x != nil {
+		return x.Org
+	}
+	return nil
+}
+
+type ListOrgsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrgsRequest) Reset() {
+	*x = ListOrgsRequest{}
+	mi := &file_org_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrgsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrgsRequest) ProtoMessage() {}
+
+func (x *ListOrgsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrgsRequest.ProtoReflect.Descriptor instead.
+func (*ListOrgsRequest) Descriptor() ([]byte, []int) {
+	return file_org_service_proto_rawDescGZIP(), []int{3}
+}
+
+type ListOrgsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orgs          []*Org                 `protobuf:"bytes,1,rep,name=orgs,proto3" json:"orgs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrgsResponse) Reset() {
+	*x = ListOrgsResponse{}
+	mi := &file_org_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrgsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrgsResponse) ProtoMessage() {}
+
+func (x *ListOrgsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_org_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrgsResponse.ProtoReflect.Descriptor instead.
+func (*ListOrgsResponse) Descriptor() ([]byte, []int) {
+	return file_org_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListOrgsResponse) GetOrgs() []*Org {
+	if x != nil {
+		return x.Orgs
+	}
+	return nil
+}
+
+// only an org without users can be deleted, its catalog is dropped
+type DeleteOrgRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrgId         string                 `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteOrgRequest) Reset() {
+	*x = DeleteOrgRequest{}
+	mi := &file_org_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteOrgRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOrgRequest) ProtoMessage() {}
+
+func (x *DeleteOrgRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOrgRequest.ProtoReflect.Descriptor instead.
+func (*DeleteOrgRequest) Descriptor() ([]byte, []int) {
+	return file_org_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteOrgRequest) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+type DeleteOrgResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteOrgResponse) Reset() {
+	*x = DeleteOrgResponse{}
+	mi := &file_org_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteOrgResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOrgResponse) ProtoMessage() {}
+
+func (x *DeleteOrgResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_org_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOrgResponse.ProtoReflect.Descriptor instead.
+func (*DeleteOrgResponse) Descriptor() ([]byte, []int) {
+	return file_org_service_proto_rawDescGZIP(), []int{6}
+}
+
+// moving a user revokes their sessions so new tokens carry the new org
+type SetUserOrgRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	OrgId         string                 `protobuf:"bytes,2,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserOrgRequest) Reset() {
+	*x = SetUserOrgRequest{}
+	mi := &file_org_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserOrgRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserOrgRequest) ProtoMessage() {}
+
+func (x *SetUserOrgRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserOrgRequest.ProtoReflect.Descriptor instead.
+func (*SetUserOrgRequest) Descriptor() ([]byte, []int) {
+	return file_org_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SetUserOrgRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetUserOrgRequest) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+type SetUserOrgResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *UserInfo              `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserOrgResponse) Reset() {
+	*x = SetUserOrgResponse{}
+	mi := &file_org_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserOrgResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserOrgResponse) ProtoMessage() {}
+
+func (x *SetUserOrgResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_org_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserOrgResponse.ProtoReflect.Descriptor instead.
+func (*SetUserOrgResponse) Descriptor() ([]byte, []int) {
+	return file_org_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetUserOrgResponse) GetUser() *UserInfo {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+var File_org_service_proto protoreflect.FileDescriptor
+
+const file_org_service_proto_rawDesc = "" +
+	"\n" +
+	"\x11org_service.proto\x12\x06pcbook\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x18user_admin_service.proto\"d\n" +
+	"\x03Org\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"=\n" +
+	"\x10CreateOrgRequest\x12\x15\n" +
+	"\x06org_id\x18\x01 \x01(\tR\x05orgId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"2\n" +
+	"\x11CreateOrgResponse\x12\x1d\n" +
+	"\x03org\x18\x01 \x01(\v2\v.pcbook.OrgR\x03org\"\x11\n" +
+	"\x0fListOrgsRequest\"3\n" +
+	"\x10ListOrgsResponse\x12\x1f\n" +
+	"\x04orgs\x18\x01 \x03(\v2\v.pcbook.OrgR\x04orgs\")\n" +
+	"\x10DeleteOrgRequest\x12\x15\n" +
+	"\x06org_id\x18\x01 \x01(\tR\x05orgId\"\x13\n" +
+	"\x11DeleteOrgResponse\"F\n" +
+	"\x11SetUserOrgRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x15\n" +
+	"\x06org_id\x18\x02 \x01(\tR\x05orgId\":\n" +
+	"\x12SetUserOrgResponse\x12$\n" +
+	"\x04user\x18\x01 \x01(\v2\x10.pcbook.UserInfoR\x04user2\x87\x03\n" +
+	"\n" +
+	"OrgService\x12X\n" +
+	"\tCreateOrg\x12\x18.pcbook.CreateOrgRequest\x1a\x19.pcbook.CreateOrgResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/admin/orgs\x12R\n" +
+	"\bListOrgs\x12\x17.pcbook.ListOrgsRequest\x1a\x18.pcbook.ListOrgsResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/admin/orgs\x12^\n" +
+	"\tDeleteOrg\x12\x18.pcbook.DeleteOrgRequest\x1a\x19.pcbook.DeleteOrgResponse\"\x1c\x82\xd3\xe4\x93\x02\x16*\x14/admin/orgs/{org_id}\x12k\n" +
+	"\n" +
+	"SetUserOrg\x12\x19.pcbook.SetUserOrgRequest\x1a\x1a.pcbook.SetUserOrgResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/admin/users/{username}/orgB#Z!github.com/JeongWoo-Seo/pcBook/pbb\x06proto3"
+
+var (
+	file_org_service_proto_rawDescOnce sync.Once
+	file_org_service_proto_rawDescData []byte
+)
+
+func file_org_service_proto_rawDescGZIP() []byte {
+	file_org_service_proto_rawDescOnce.Do(func() {
+		file_org_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_org_service_proto_rawDesc), len(file_org_service_proto_rawDesc)))
+	})
+	return file_org_service_proto_rawDescData
+}
+
+var file_org_service_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_org_service_proto_goTypes = []any{
+	(*Org)(nil),                   // 0: pcbook.Org
+	(*CreateOrgRequest)(nil),      // 1: pcbook.CreateOrgRequest
+	(*CreateOrgResponse)(nil),     // 2: pcbook.CreateOrgResponse
+	(*ListOrgsRequest)(nil),       // 3: pcbook.ListOrgsRequest
+	(*ListOrgsResponse)(nil),      // 4: pcbook.ListOrgsResponse
+	(*DeleteOrgRequest)(nil),      // 5: pcbook.DeleteOrgRequest
+	(*DeleteOrgResponse)(nil),     // 6: pcbook.DeleteOrgResponse
+	(*SetUserOrgRequest)(nil),     // 7: pcbook.SetUserOrgRequest
+	(*SetUserOrgResponse)(nil),    // 8: pcbook.SetUserOrgResponse
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*UserInfo)(nil),              // 10: pcbook.UserInfo
+}
+var file_org_service_proto_depIdxs = []int32{
+	9,  // 0: pcbook.Org.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: pcbook.CreateOrgResponse.org:type_name -> pcbook.Org
+	0,  // 2: pcbook.ListOrgsResponse.orgs:type_name -> pcbook.Org
+	10, // 3: pcbook.SetUserOrgResponse.user:type_name -> pcbook.UserInfo
+	1,  // 4: pcbook.OrgService.CreateOrg:input_type -> pcbook.CreateOrgRequest
+	3,  // 5: pcbook.OrgService.ListOrgs:input_type -> pcbook.ListOrgsRequest
+	5,  // 6: pcbook.OrgService.DeleteOrg:input_type -> pcbook.DeleteOrgRequest
+	7,  // 7: pcbook.OrgService.SetUserOrg:input_type -> pcbook.SetUserOrgRequest
+	2,  // 8: pcbook.OrgService.CreateOrg:output_type -> pcbook.CreateOrgResponse
+	4,  // 9: pcbook.OrgService.ListOrgs:output_type -> pcbook.ListOrgsResponse
+	6,  // 10: pcbook.OrgService.DeleteOrg:output_type -> pcbook.DeleteOrgResponse
+	8,  // 11: pcbook.OrgService.SetUserOrg:output_type -> pcbook.SetUserOrgResponse
+	8,  // [8:12] is the sub-list for method output_type
+	4,  // [4:8] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_org_service_proto_init() }
+func file_org_service_proto_init() {
+	if File_org_service_proto != nil {
+		return
+	}
+	file_user_admin_service_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_org_service_proto_rawDesc), len(file_org_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_org_service_proto_goTypes,
+		DependencyIndexes: file_org_service_proto_depIdxs,
+		MessageInfos:      file_org_service_proto_msgTypes,
+	}.Build()
+	File_org_service_proto = out.File
+	file_org_service_proto_goTypes = nil
+	file_org_service_proto_depIdxs = nil
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func UpdateLaptopHeartbeat(ctx context.Context, rm *RedisManager, laptopID string) error {
+// UpdateLaptopHeartbeat의 prefix는 organization마다 heartbeat를 나누는 데 쓰인다
+// cleanup이 sorted set 하나만 정리하면 되도록 key 대신 member 앞에 붙인다
+func UpdateLaptopHeartbeat(ctx context.Context, rm *RedisManager, prefix string, laptopID string) error {
 	if err := rm.AllowRequest(); err != nil {
 		return err
 	}
@@ -18,7 +20,7 @@ func UpdateLaptopHeartbeat(ctx context.Context, rm *RedisManager, laptopID strin
 
 	err := rm.Client.ZAdd(ctx, "laptop:alive", redis.Z{
 		Score:  float64(now),
-		Member: laptopID,
+		Member: prefix + laptopID,
 	}).Err()
 
 	if err != nil {
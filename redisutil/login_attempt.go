@@ -0,0 +1,157 @@
+package redisutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginReservation은 ReserveLogin이 결과를 알기 전에 실패로 센 로그인 시도이다
+// LockedUntil은 이미 잠겨 있어서 시도를 세지 않았을 때의 잠금 시각이며 Lock은 이 시도가 건 잠금이다
+type LoginReservation struct {
+	Failures    int64
+	LockedUntil time.Time
+	Lock        time.Time
+}
+
+// ReserveLogin은 잠겨 있지 않으면 실패 횟수를 늘리고 delay가 0보다 크면 그동안 잠근다
+// 잠금 확인과 횟수 증가는 WATCH한 transaction 안에서 함께 일어나므로 동시에 들어온 시도도 하나씩 센다
+// window 동안 실패가 없으면 redis가 TTL로 횟수를 지운다
+func ReserveLogin(ctx context.Context, rm *RedisManager, key string, window time.Duration, delay func(failures int64) time.Duration) (*LoginReservation, error) {
+	if err := rm.AllowRequest(); err != nil {
+		return nil, err
+	}
+
+	failureKey := loginFailureKey(key)
+	lockKey := loginLockKey(key)
+
+	var reservation *LoginReservation
+	err := watch(ctx, rm, func(tx *redis.Tx) error {
+		until, err := tx.Get(ctx, lockKey).Int64()
+		if err == nil {
+			reservation = &LoginReservation{LockedUntil: time.Unix(0, until)}
+			return nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		failures, err := tx.Get(ctx, failureKey).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		reservation = &LoginReservation{Failures: failures + 1}
+		lockDelay := delay(reservation.Failures)
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Incr(ctx, failureKey)
+			pipe.PExpire(ctx, failureKey, window)
+			if lockDelay > 0 {
+				reservation.Lock = time.Now().Add(lockDelay)
+				pipe.Set(ctx, lockKey, reservation.Lock.UnixNano(), lockDelay)
+			}
+			return nil
+		})
+		return err
+	}, failureKey, lockKey)
+	if errors.Is(err, ErrConcurrentUpdate) {
+		rm.connectionSuccess()
+		return nil, fmt.Errorf("login attempts of %s: %w", key, err)
+	}
+	if err != nil {
+		rm.connectionFailure(err)
+		return nil, err
+	}
+
+	rm.connectionSuccess()
+	return reservation, nil
+}
+
+// ReleaseLogin은 ReserveLogin이 센 시도를 되돌리고 그 시도가 건 lock이 그대로 남아 있으면 푼다
+func ReleaseLogin(ctx context.Context, rm *RedisManager, key string, lock time.Time) error {
+	if err := rm.AllowRequest(); err != nil {
+		return err
+	}
+
+	failureKey := loginFailureKey(key)
+	lockKey := loginLockKey(key)
+
+	err := watch(ctx, rm, func(tx *redis.Tx) error {
+		failures, err := tx.Get(ctx, failureKey).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		until, err := tx.Get(ctx, lockKey).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if failures > 0 {
+				pipe.Decr(ctx, failureKey)
+			}
+			if !lock.IsZero() && until == lock.UnixNano() {
+				pipe.Del(ctx, lockKey)
+			}
+			return nil
+		})
+		return err
+	}, failureKey, lockKey)
+	if errors.Is(err, ErrConcurrentUpdate) {
+		rm.connectionSuccess()
+		return fmt.Errorf("login attempts of %s: %w", key, err)
+	}
+	if err != nil {
+		rm.connectionFailure(err)
+		return err
+	}
+
+	rm.connectionSuccess()
+	return nil
+}
+
+// LoginLockedUntil은 잠겨 있지 않으면 zero time을 반환한다
+func LoginLockedUntil(ctx context.Context, rm *RedisManager, key string) (time.Time, error) {
+	if err := rm.AllowRequest(); err != nil {
+		return time.Time{}, err
+	}
+
+	until, err := rm.Client.Get(ctx, loginLockKey(key)).Int64()
+	if errors.Is(err, redis.Nil) {
+		rm.connectionSuccess()
+		return time.Time{}, nil
+	}
+	if err != nil {
+		rm.connectionFailure(err)
+		return time.Time{}, err
+	}
+
+	rm.connectionSuccess()
+	return time.Unix(0, until), nil
+}
+
+func ResetLoginFailures(ctx context.Context, rm *RedisManager, key string) error {
+	if err := rm.AllowRequest(); err != nil {
+		return err
+	}
+
+	err := rm.Client.Del(ctx, loginFailureKey(key), loginLockKey(key)).Err()
+	if err != nil {
+		rm.connectionFailure(err)
+		return err
+	}
+
+	rm.connectionSuccess()
+	return nil
+}
+
+func loginFailureKey(key string) string {
+	return "login:failures:" + key
+}
+
+func loginLockKey(key string) string {
+	return "login:lock:" + key
+}
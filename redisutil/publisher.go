@@ -30,9 +30,9 @@ type RedisManager struct {
 	halfOpen bool //openDuration 이후 redis 연결 test
 }
 
-func NewRedisManager() *RedisManager {
+func NewRedisManager(addr string) *RedisManager {
 	rdb := redis.NewClient(&redis.Options{
-		Addr:            "localhost:6379",
+		Addr:            addr,
 		DB:              0,
 		MaxRetries:      3,
 		MinRetryBackoff: 100 * time.Millisecond,
@@ -132,7 +132,8 @@ func (rm *RedisManager) connectionFailure(err error) {
 	log.Println("reids fail:", err)
 }
 
-func PublishToRedis(ctx context.Context, rm *RedisManager, laptop *pb.LaptopInfo) error {
+// PublishToRedis의 prefix는 channel 앞에 붙으며 organization마다 laptop 정보를 나누는 데 쓰인다
+func PublishToRedis(ctx context.Context, rm *RedisManager, prefix string, laptop *pb.LaptopInfo) error {
 	if err := rm.AllowRequest(); err != nil {
 		return err
 	}
@@ -142,7 +143,7 @@ func PublishToRedis(ctx context.Context, rm *RedisManager, laptop *pb.LaptopInfo
 		return err
 	}
 
-	channel := "laptop:" + laptop.GetId() + ":metrics"
+	channel := prefix + "laptop:" + laptop.GetId() + ":metrics"
 
 	err = rm.Client.Publish(ctx, channel, data).Err()
 	if err != nil {
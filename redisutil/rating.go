@@ -0,0 +1,346 @@
+package redisutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrRatingNotFound = errors.New("rating not found")
+
+// RatingStats는 laptop 하나의 평가 수, 점수 합, 점수별 평가 수이다
+type RatingStats struct {
+	Count        uint32
+	Sum          float64
+	Distribution map[float64]uint32
+}
+
+type UserRatingEntry struct {
+	LaptopID  string
+	Score     float64
+	UpdatedAt time.Time
+}
+
+// rating 함수의 prefix는 모든 key 앞에 붙으며 organization마다 평가를 나누는 데 쓰인다
+
+func AddRating(ctx context.Context, rm *RedisManager, prefix string, laptopID string, username string, score float64) (*RatingStats, error) {
+	return updateRating(ctx, rm, prefix, laptopID, username, &score)
+}
+
+// DeleteRating은 평가가 없으면 ErrRatingNotFound를 반환한다
+func DeleteRating(ctx context.Context, rm *RedisManager, prefix string, laptopID string, username string) (*RatingStats, error) {
+	return updateRating(ctx, rm, prefix, laptopID, username, nil)
+}
+
+// DeleteLaptopRatings는 laptop의 모든 평가를 지우고 leaderboard에서 뺀다
+func DeleteLaptopRatings(ctx context.Context, rm *RedisManager, prefix string, laptopID string) error {
+	if err := rm.AllowRequest(); err != nil {
+		return err
+	}
+
+	usersKey := ratingUsersKey(prefix, laptopID)
+	err := watch(ctx, rm, func(tx *redis.Tx) error {
+		users, err := tx.HGetAll(ctx, usersKey).Result()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for username := range users {
+				pipe.HDel(ctx, userRatingsKey(prefix, username), laptopID)
+			}
+			pipe.Del(ctx, usersKey, ratingStatsKey(prefix, laptopID), ratingDistributionKey(prefix, laptopID))
+			pipe.ZRem(ctx, ratingLeaderboardKey(prefix), laptopID)
+			return nil
+		})
+		return err
+	}, usersKey)
+	if errors.Is(err, ErrConcurrentUpdate) {
+		rm.connectionSuccess()
+		return fmt.Errorf("rating of laptop %s: %w", laptopID, err)
+	}
+	if err != nil {
+		rm.connectionFailure(err)
+		return err
+	}
+
+	rm.connectionSuccess()
+	return nil
+}
+
+// updateRating은 사용자의 점수를 score로 바꾸고 score가 nil이면 지운다
+// 통계는 WATCH한 key를 읽어 계산하며 그 사이 다른 요청이 key를 바꾸면 처음부터 다시 계산한다
+func updateRating(ctx context.Context, rm *RedisManager, prefix string, laptopID string, username string, score *float64) (*RatingStats, error) {
+	if err := rm.AllowRequest(); err != nil {
+		return nil, err
+	}
+
+	var stats *RatingStats
+	err := watch(ctx, rm, func(tx *redis.Tx) error {
+		var err error
+		stats, err = applyRating(ctx, tx, prefix, laptopID, username, score)
+		return err
+	}, ratingUsersKey(prefix, laptopID), ratingStatsKey(prefix, laptopID), ratingDistributionKey(prefix, laptopID))
+	if errors.Is(err, ErrConcurrentUpdate) {
+		rm.connectionSuccess()
+		return nil, fmt.Errorf("rating of laptop %s: %w", laptopID, err)
+	}
+	if errors.Is(err, ErrRatingNotFound) {
+		rm.connectionSuccess()
+		return nil, err
+	}
+	if err != nil {
+		rm.connectionFailure(err)
+		return nil, err
+	}
+
+	rm.connectionSuccess()
+	return stats, nil
+}
+
+func applyRating(ctx context.Context, tx *redis.Tx, prefix string, laptopID string, username string, score *float64) (*RatingStats, error) {
+	usersKey := ratingUsersKey(prefix, laptopID)
+	statsKey := ratingStatsKey(prefix, laptopID)
+	distributionKey := ratingDistributionKey(prefix, laptopID)
+
+	previous, err := tx.HGet(ctx, usersKey, username).Float64()
+	hasPrevious := err == nil
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	if !hasPrevious && score == nil {
+		return nil, ErrRatingNotFound
+	}
+
+	values, err := tx.HMGet(ctx, statsKey, "count", "sum").Result()
+	if err != nil {
+		return nil, err
+	}
+	distribution, err := tx.HGetAll(ctx, distributionKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := readRatingStats(values, distribution)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		stats = &RatingStats{Distribution: make(map[float64]uint32)}
+	}
+
+	if hasPrevious {
+		stats.Count--
+		stats.Sum -= previous
+		stats.Distribution[previous]--
+		if stats.Distribution[previous] == 0 {
+			delete(stats.Distribution, previous)
+		}
+	}
+	if score != nil {
+		stats.Count++
+		stats.Sum += *score
+		stats.Distribution[*score]++
+	}
+
+	_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if score != nil {
+			pipe.HSet(ctx, usersKey, username, formatScore(*score))
+			pipe.HSet(ctx, userRatingsKey(prefix, username), laptopID, strconv.FormatInt(time.Now().UnixNano(), 10))
+		} else {
+			pipe.HDel(ctx, usersKey, username)
+			pipe.HDel(ctx, userRatingsKey(prefix, username), laptopID)
+		}
+
+		pipe.Del(ctx, statsKey, distributionKey)
+		if stats.Count == 0 {
+			pipe.ZRem(ctx, ratingLeaderboardKey(prefix), laptopID)
+			return nil
+		}
+
+		pipe.HSet(ctx, statsKey, "count", stats.Count, "sum", formatScore(stats.Sum))
+		pairs := make([]any, 0, 2*len(stats.Distribution))
+		for value, count := range stats.Distribution {
+			pairs = append(pairs, formatScore(value), count)
+		}
+		pipe.HSet(ctx, distributionKey, pairs...)
+		pipe.ZAdd(ctx, ratingLeaderboardKey(prefix), redis.Z{Score: stats.Sum / float64(stats.Count), Member: laptopID})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.Count == 0 {
+		stats.Sum = 0
+	}
+	return stats, nil
+}
+
+// FindRating은 평가가 없는 laptop이면 nil을 반환한다
+func FindRating(ctx context.Context, rm *RedisManager, prefix string, laptopID string) (*RatingStats, error) {
+	ratings, err := FindRatings(ctx, rm, prefix, []string{laptopID})
+	if err != nil {
+		return nil, err
+	}
+	return ratings[0], nil
+}
+
+// FindRatings는 laptopIDs의 통계를 한 번의 pipeline으로 읽으며 평가가 없는 laptop은 nil이다
+func FindRatings(ctx context.Context, rm *RedisManager, prefix string, laptopIDs []string) ([]*RatingStats, error) {
+	if len(laptopIDs) == 0 {
+		return nil, nil
+	}
+	if err := rm.AllowRequest(); err != nil {
+		return nil, err
+	}
+
+	pipe := rm.Client.Pipeline()
+	stats := make([]*redis.SliceCmd, 0, len(laptopIDs))
+	distributions := make([]*redis.MapStringStringCmd, 0, len(laptopIDs))
+	for _, laptopID := range laptopIDs {
+		stats = append(stats, pipe.HMGet(ctx, ratingStatsKey(prefix, laptopID), "count", "sum"))
+		distributions = append(distributions, pipe.HGetAll(ctx, ratingDistributionKey(prefix, laptopID)))
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		rm.connectionFailure(err)
+		return nil, err
+	}
+	rm.connectionSuccess()
+
+	ratings := make([]*RatingStats, 0, len(laptopIDs))
+	for i := range laptopIDs {
+		rating, err := readRatingStats(stats[i].Val(), distributions[i].Val())
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+	return ratings, nil
+}
+
+func ListUserRatings(ctx context.Context, rm *RedisManager, prefix string, username string) ([]*UserRatingEntry, error) {
+	if err := rm.AllowRequest(); err != nil {
+		return nil, err
+	}
+
+	updated, err := rm.Client.HGetAll(ctx, userRatingsKey(prefix, username)).Result()
+	if err != nil {
+		rm.connectionFailure(err)
+		return nil, err
+	}
+
+	pipe := rm.Client.Pipeline()
+	scores := make(map[string]*redis.StringCmd, len(updated))
+	for laptopID := range updated {
+		scores[laptopID] = pipe.HGet(ctx, ratingUsersKey(prefix, laptopID), username)
+	}
+	if len(scores) > 0 {
+		_, err = pipe.Exec(ctx)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			rm.connectionFailure(err)
+			return nil, err
+		}
+	}
+	rm.connectionSuccess()
+
+	entries := []*UserRatingEntry{}
+	for laptopID, nano := range updated {
+		score, err := scores[laptopID].Float64()
+		if err != nil {
+			continue
+		}
+		updatedAt, err := strconv.ParseInt(nano, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rating time %q: %w", nano, err)
+		}
+
+		entries = append(entries, &UserRatingEntry{
+			LaptopID:  laptopID,
+			Score:     score,
+			UpdatedAt: time.Unix(0, updatedAt),
+		})
+	}
+
+	return entries, nil
+}
+
+// TopRatedLaptops는 평균 점수 내림차순으로 offset부터 count개의 laptop id를 반환한다
+func TopRatedLaptops(ctx context.Context, rm *RedisManager, prefix string, offset int64, count int64) ([]string, error) {
+	if err := rm.AllowRequest(); err != nil {
+		return nil, err
+	}
+
+	laptopIDs, err := rm.Client.ZRevRange(ctx, ratingLeaderboardKey(prefix), offset, offset+count-1).Result()
+	if err != nil {
+		rm.connectionFailure(err)
+		return nil, err
+	}
+
+	rm.connectionSuccess()
+	return laptopIDs, nil
+}
+
+// readRatingStats는 HMGET count, sum과 HGETALL 결과를 읽으며 평가가 없으면 nil을 반환한다
+func readRatingStats(values []any, distribution map[string]string) (*RatingStats, error) {
+	if values[0] == nil {
+		return nil, nil
+	}
+
+	stats := &RatingStats{Distribution: make(map[float64]uint32)}
+
+	count := fmt.Sprint(values[0])
+	n, err := strconv.ParseUint(count, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rating count %q: %w", count, err)
+	}
+	stats.Count = uint32(n)
+
+	sum := fmt.Sprint(values[1])
+	stats.Sum, err = strconv.ParseFloat(sum, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rating sum %q: %w", sum, err)
+	}
+
+	for value, count := range distribution {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rating score %q: %w", value, err)
+		}
+		n, err := strconv.ParseUint(count, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rating distribution %q: %w", count, err)
+		}
+		stats.Distribution[score] = uint32(n)
+	}
+
+	return stats, nil
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+func ratingLeaderboardKey(prefix string) string {
+	return prefix + "rating:leaderboard"
+}
+
+func ratingUsersKey(prefix string, laptopID string) string {
+	return prefix + "rating:laptop:" + laptopID + ":users"
+}
+
+func ratingStatsKey(prefix string, laptopID string) string {
+	return prefix + "rating:laptop:" + laptopID
+}
+
+func ratingDistributionKey(prefix string, laptopID string) string {
+	return prefix + "rating:laptop:" + laptopID + ":distribution"
+}
+
+func userRatingsKey(prefix string, username string) string {
+	return prefix + "rating:user:" + username
+}
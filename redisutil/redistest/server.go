@@ -0,0 +1,783 @@
+// Package redistest는 테스트에서 사용할 in-process redis 호환 서버를 제공한다
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errWrongType      = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+	errNotInteger     = errors.New("ERR value is not an integer or out of range")
+	errNotFloat       = errors.New("ERR value is not a valid float")
+	errSyntax         = errors.New("ERR syntax error")
+	errNestedMulti    = errors.New("ERR MULTI calls can not be nested")
+	errExecNoMulti    = errors.New("ERR EXEC without MULTI")
+	errWatchInMulti   = errors.New("ERR WATCH inside MULTI is not allowed")
+	errDiscardNoMulti = errors.New("ERR DISCARD without MULTI")
+)
+
+// Server는 RESP2로 string, hash, sorted set 명령과 MULTI/EXEC/WATCH transaction을 처리한다
+// key의 TTL은 FastForward로 옮긴 시계를 기준으로 만료된다
+type Server struct {
+	listener net.Listener
+	Addr     string
+
+	mutax    sync.Mutex
+	keys     map[string]*entry
+	versions map[string]uint64
+	offset   time.Duration
+	conns    map[net.Conn]bool
+	wg       sync.WaitGroup
+}
+
+type entry struct {
+	str      string
+	hash     map[string]string
+	zset     map[string]float64
+	expireAt time.Time
+}
+
+// conn은 연결마다 transaction 상태를 가진다
+type conn struct {
+	multi   bool
+	queued  [][]string
+	watched map[string]uint64
+}
+
+type nilArray struct{}
+
+type status string
+
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("redistest: failed to listen: %v", err))
+	}
+
+	server := &Server{
+		listener: listener,
+		Addr:     listener.Addr().String(),
+		keys:     make(map[string]*entry),
+		versions: make(map[string]uint64),
+		conns:    make(map[net.Conn]bool),
+	}
+
+	server.wg.Add(1)
+	go server.serve()
+
+	return server
+}
+
+func (server *Server) Close() {
+	server.listener.Close()
+
+	server.mutax.Lock()
+	for c := range server.conns {
+		c.Close()
+	}
+	server.mutax.Unlock()
+
+	server.wg.Wait()
+}
+
+// FastForward는 서버의 시계를 d만큼 옮겨서 TTL이 지난 key를 만료시킨다
+func (server *Server) FastForward(d time.Duration) {
+	server.mutax.Lock()
+	defer server.mutax.Unlock()
+
+	server.offset += d
+}
+
+// Exists는 만료되지 않은 key가 있는지 확인한다
+func (server *Server) Exists(key string) bool {
+	server.mutax.Lock()
+	defer server.mutax.Unlock()
+
+	return server.lookup(key) != nil
+}
+
+func (server *Server) serve() {
+	defer server.wg.Done()
+
+	for {
+		c, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		server.mutax.Lock()
+		server.conns[c] = true
+		server.mutax.Unlock()
+
+		server.wg.Add(1)
+		go server.handle(c)
+	}
+}
+
+func (server *Server) handle(c net.Conn) {
+	defer server.wg.Done()
+	defer func() {
+		server.mutax.Lock()
+		delete(server.conns, c)
+		server.mutax.Unlock()
+		c.Close()
+	}()
+
+	reader := bufio.NewReader(c)
+	writer := bufio.NewWriter(c)
+	state := &conn{}
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		server.mutax.Lock()
+		reply := server.dispatch(state, args)
+		server.mutax.Unlock()
+
+		writeReply(writer, reply)
+		// pipeline으로 온 명령은 모아서 보낸다
+		if reader.Buffered() == 0 {
+			if err := writer.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (server *Server) dispatch(state *conn, args []string) any {
+	if len(args) == 0 {
+		return errSyntax
+	}
+	name := strings.ToUpper(args[0])
+
+	switch name {
+	case "MULTI":
+		if state.multi {
+			return errNestedMulti
+		}
+		state.multi = true
+		state.queued = nil
+		return status("OK")
+
+	case "EXEC":
+		if !state.multi {
+			return errExecNoMulti
+		}
+		queued, watched := state.queued, state.watched
+		state.multi, state.queued, state.watched = false, nil, nil
+
+		for key, version := range watched {
+			server.lookup(key)
+			if server.versions[key] != version {
+				return nilArray{}
+			}
+		}
+
+		replies := make([]any, 0, len(queued))
+		for _, command := range queued {
+			replies = append(replies, server.execute(strings.ToUpper(command[0]), command[1:]))
+		}
+		return replies
+
+	case "DISCARD":
+		if !state.multi {
+			return errDiscardNoMulti
+		}
+		state.multi, state.queued, state.watched = false, nil, nil
+		return status("OK")
+
+	case "WATCH":
+		if state.multi {
+			return errWatchInMulti
+		}
+		if state.watched == nil {
+			state.watched = make(map[string]uint64)
+		}
+		for _, key := range args[1:] {
+			server.lookup(key)
+			state.watched[key] = server.versions[key]
+		}
+		return status("OK")
+
+	case "UNWATCH":
+		state.watched = nil
+		return status("OK")
+	}
+
+	if state.multi {
+		state.queued = append(state.queued, args)
+		return status("QUEUED")
+	}
+
+	return server.execute(name, args[1:])
+}
+
+func (server *Server) execute(name string, args []string) any {
+	switch name {
+	case "PING":
+		return status("PONG")
+	case "SELECT", "CLIENT":
+		return status("OK")
+	case "PUBLISH":
+		return int64(0)
+
+	case "GET":
+		if len(args) != 1 {
+			return arityError(name)
+		}
+		e := server.lookup(args[0])
+		if e == nil {
+			return nil
+		}
+		if e.hash != nil || e.zset != nil {
+			return errWrongType
+		}
+		return e.str
+
+	case "SET":
+		return server.set(args)
+
+	case "DEL":
+		removed := int64(0)
+		for _, key := range args {
+			if server.lookup(key) != nil {
+				server.remove(key)
+				removed++
+			}
+		}
+		return removed
+
+	case "EXISTS":
+		found := int64(0)
+		for _, key := range args {
+			if server.lookup(key) != nil {
+				found++
+			}
+		}
+		return found
+
+	case "INCR", "DECR":
+		if len(args) != 1 {
+			return arityError(name)
+		}
+		e := server.lookup(args[0])
+		value := int64(0)
+		if e != nil {
+			if e.hash != nil || e.zset != nil {
+				return errWrongType
+			}
+			var err error
+			value, err = strconv.ParseInt(e.str, 10, 64)
+			if err != nil {
+				return errNotInteger
+			}
+		} else {
+			e = &entry{}
+			server.keys[args[0]] = e
+		}
+		if name == "INCR" {
+			value++
+		} else {
+			value--
+		}
+		e.str = strconv.FormatInt(value, 10)
+		server.touch(args[0])
+		return value
+
+	case "EXPIRE", "PEXPIRE":
+		if len(args) < 2 {
+			return arityError(name)
+		}
+		n, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errNotInteger
+		}
+		e := server.lookup(args[0])
+		if e == nil {
+			return int64(0)
+		}
+		unit := time.Second
+		if name == "PEXPIRE" {
+			unit = time.Millisecond
+		}
+		e.expireAt = server.now().Add(time.Duration(n) * unit)
+		server.touch(args[0])
+		return int64(1)
+
+	case "TTL", "PTTL":
+		if len(args) != 1 {
+			return arityError(name)
+		}
+		e := server.lookup(args[0])
+		if e == nil {
+			return int64(-2)
+		}
+		if e.expireAt.IsZero() {
+			return int64(-1)
+		}
+		left := e.expireAt.Sub(server.now())
+		if name == "TTL" {
+			return int64((left + time.Second - 1) / time.Second)
+		}
+		return int64(left / time.Millisecond)
+
+	case "HGET":
+		if len(args) != 2 {
+			return arityError(name)
+		}
+		hash, err := server.hash(args[0], false)
+		if err != nil {
+			return err
+		}
+		value, ok := hash[args[1]]
+		if !ok {
+			return nil
+		}
+		return value
+
+	case "HMGET":
+		if len(args) < 2 {
+			return arityError(name)
+		}
+		hash, err := server.hash(args[0], false)
+		if err != nil {
+			return err
+		}
+		values := make([]any, 0, len(args)-1)
+		for _, field := range args[1:] {
+			value, ok := hash[field]
+			if !ok {
+				values = append(values, nil)
+				continue
+			}
+			values = append(values, value)
+		}
+		return values
+
+	case "HGETALL":
+		if len(args) != 1 {
+			return arityError(name)
+		}
+		hash, err := server.hash(args[0], false)
+		if err != nil {
+			return err
+		}
+		fields := make([]string, 0, len(hash))
+		for field := range hash {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		values := make([]any, 0, 2*len(fields))
+		for _, field := range fields {
+			values = append(values, field, hash[field])
+		}
+		return values
+
+	case "HSET":
+		if len(args) < 3 || len(args)%2 != 1 {
+			return arityError(name)
+		}
+		hash, err := server.hash(args[0], true)
+		if err != nil {
+			return err
+		}
+		added := int64(0)
+		for i := 1; i < len(args); i += 2 {
+			if _, ok := hash[args[i]]; !ok {
+				added++
+			}
+			hash[args[i]] = args[i+1]
+		}
+		server.touch(args[0])
+		return added
+
+	case "HDEL":
+		if len(args) < 2 {
+			return arityError(name)
+		}
+		hash, err := server.hash(args[0], false)
+		if err != nil {
+			return err
+		}
+		removed := int64(0)
+		for _, field := range args[1:] {
+			if _, ok := hash[field]; ok {
+				delete(hash, field)
+				removed++
+			}
+		}
+		if removed > 0 {
+			server.touch(args[0])
+			if len(hash) == 0 {
+				server.remove(args[0])
+			}
+		}
+		return removed
+
+	case "HINCRBY":
+		if len(args) != 3 {
+			return arityError(name)
+		}
+		delta, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return errNotInteger
+		}
+		hash, herr := server.hash(args[0], true)
+		if herr != nil {
+			return herr
+		}
+		value := int64(0)
+		if current, ok := hash[args[1]]; ok {
+			value, err = strconv.ParseInt(current, 10, 64)
+			if err != nil {
+				return errNotInteger
+			}
+		}
+		value += delta
+		hash[args[1]] = strconv.FormatInt(value, 10)
+		server.touch(args[0])
+		return value
+
+	case "ZADD":
+		if len(args) < 3 || len(args)%2 != 1 {
+			return arityError(name)
+		}
+		zset, err := server.zset(args[0], true)
+		if err != nil {
+			return err
+		}
+		added := int64(0)
+		for i := 1; i < len(args); i += 2 {
+			score, err := strconv.ParseFloat(args[i], 64)
+			if err != nil {
+				return errNotFloat
+			}
+			if _, ok := zset[args[i+1]]; !ok {
+				added++
+			}
+			zset[args[i+1]] = score
+		}
+		server.touch(args[0])
+		return added
+
+	case "ZREM":
+		if len(args) < 2 {
+			return arityError(name)
+		}
+		zset, err := server.zset(args[0], false)
+		if err != nil {
+			return err
+		}
+		removed := int64(0)
+		for _, member := range args[1:] {
+			if _, ok := zset[member]; ok {
+				delete(zset, member)
+				removed++
+			}
+		}
+		if removed > 0 {
+			server.touch(args[0])
+			if len(zset) == 0 {
+				server.remove(args[0])
+			}
+		}
+		return removed
+
+	case "ZSCORE":
+		if len(args) != 2 {
+			return arityError(name)
+		}
+		zset, err := server.zset(args[0], false)
+		if err != nil {
+			return err
+		}
+		score, ok := zset[args[1]]
+		if !ok {
+			return nil
+		}
+		return formatFloat(score)
+
+	case "ZREVRANGE":
+		if len(args) < 3 {
+			return arityError(name)
+		}
+		start, err1 := strconv.Atoi(args[1])
+		stop, err2 := strconv.Atoi(args[2])
+		if err1 != nil || err2 != nil {
+			return errNotInteger
+		}
+		withScores := len(args) > 3 && strings.EqualFold(args[3], "WITHSCORES")
+		zset, err := server.zset(args[0], false)
+		if err != nil {
+			return err
+		}
+		return revRange(zset, start, stop, withScores)
+
+	case "ZREMRANGEBYSCORE":
+		if len(args) != 3 {
+			return arityError(name)
+		}
+		lower, err1 := parseBound(args[1])
+		upper, err2 := parseBound(args[2])
+		if err1 != nil || err2 != nil {
+			return errNotFloat
+		}
+		zset, err := server.zset(args[0], false)
+		if err != nil {
+			return err
+		}
+		removed := int64(0)
+		for member, score := range zset {
+			if score >= lower && score <= upper {
+				delete(zset, member)
+				removed++
+			}
+		}
+		if removed > 0 {
+			server.touch(args[0])
+			if len(zset) == 0 {
+				server.remove(args[0])
+			}
+		}
+		return removed
+	}
+
+	return fmt.Errorf("ERR unknown command '%s'", strings.ToLower(name))
+}
+
+// set은 EX, PX, NX, XX 옵션을 지원한다
+func (server *Server) set(args []string) any {
+	if len(args) < 2 {
+		return arityError("SET")
+	}
+	key, value := args[0], args[1]
+
+	var ttl time.Duration
+	nx, xx := false, false
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				return errSyntax
+			}
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || n <= 0 {
+				return errors.New("ERR invalid expire time in 'set' command")
+			}
+			unit := time.Second
+			if strings.EqualFold(args[i], "PX") {
+				unit = time.Millisecond
+			}
+			ttl = time.Duration(n) * unit
+			i++
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		default:
+			return errSyntax
+		}
+	}
+
+	exists := server.lookup(key) != nil
+	if (nx && exists) || (xx && !exists) {
+		return nil
+	}
+
+	e := &entry{str: value}
+	if ttl > 0 {
+		e.expireAt = server.now().Add(ttl)
+	}
+	server.keys[key] = e
+	server.touch(key)
+	return status("OK")
+}
+
+func (server *Server) now() time.Time {
+	return time.Now().Add(server.offset)
+}
+
+// lookup은 만료된 key를 지우고 nil을 반환한다
+func (server *Server) lookup(key string) *entry {
+	e := server.keys[key]
+	if e == nil {
+		return nil
+	}
+	if !e.expireAt.IsZero() && !server.now().Before(e.expireAt) {
+		server.remove(key)
+		return nil
+	}
+	return e
+}
+
+func (server *Server) remove(key string) {
+	delete(server.keys, key)
+	server.touch(key)
+}
+
+// touch는 WATCH한 transaction이 취소되도록 key의 version을 올린다
+func (server *Server) touch(key string) {
+	server.versions[key]++
+}
+
+func (server *Server) hash(key string, create bool) (map[string]string, error) {
+	e := server.lookup(key)
+	if e == nil {
+		if !create {
+			return map[string]string{}, nil
+		}
+		e = &entry{hash: make(map[string]string)}
+		server.keys[key] = e
+	}
+	if e.hash == nil {
+		return nil, errWrongType
+	}
+	return e.hash, nil
+}
+
+func (server *Server) zset(key string, create bool) (map[string]float64, error) {
+	e := server.lookup(key)
+	if e == nil {
+		if !create {
+			return map[string]float64{}, nil
+		}
+		e = &entry{zset: make(map[string]float64)}
+		server.keys[key] = e
+	}
+	if e.zset == nil {
+		return nil, errWrongType
+	}
+	return e.zset, nil
+}
+
+// revRange는 redis처럼 점수가 같으면 member의 사전 역순으로 정렬한다
+func revRange(zset map[string]float64, start int, stop int, withScores bool) []any {
+	members := make([]string, 0, len(zset))
+	for member := range zset {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if zset[a] != zset[b] {
+			return zset[a] > zset[b]
+		}
+		return a > b
+	})
+
+	n := len(members)
+	if start < 0 {
+		start = max(n+start, 0)
+	}
+	if stop < 0 {
+		stop = n + stop
+	}
+	stop = min(stop, n-1)
+
+	values := []any{}
+	for i := start; i <= stop; i++ {
+		values = append(values, members[i])
+		if withScores {
+			values = append(values, formatFloat(zset[members[i]]))
+		}
+	}
+	return values
+}
+
+func parseBound(value string) (float64, error) {
+	switch value {
+	case "-inf":
+		return math.Inf(-1), nil
+	case "+inf", "inf":
+		return math.Inf(1), nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func arityError(name string) error {
+	return fmt.Errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(name))
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid array length %q", line)
+	}
+
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(line, "$") {
+			return nil, fmt.Errorf("expected bulk string, got %q", line)
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk length %q", line)
+		}
+
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args = append(args, string(data[:size]))
+	}
+
+	return args, nil
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func writeReply(writer *bufio.Writer, reply any) {
+	switch reply := reply.(type) {
+	case nil:
+		writer.WriteString("$-1\r\n")
+	case nilArray:
+		writer.WriteString("*-1\r\n")
+	case status:
+		fmt.Fprintf(writer, "+%s\r\n", reply)
+	case error:
+		fmt.Fprintf(writer, "-%s\r\n", reply.Error())
+	case int64:
+		fmt.Fprintf(writer, ":%d\r\n", reply)
+	case string:
+		fmt.Fprintf(writer, "$%d\r\n%s\r\n", len(reply), reply)
+	case []any:
+		fmt.Fprintf(writer, "*%d\r\n", len(reply))
+		for _, value := range reply {
+			writeReply(writer, value)
+		}
+	}
+}
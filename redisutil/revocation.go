@@ -0,0 +1,78 @@
+package redisutil
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 폐기 기록은 token이 만료되는 시각에 redis가 TTL로 지운다
+func RevokeToken(ctx context.Context, rm *RedisManager, tokenID string, expiresAt time.Time) error {
+	return setRevocation(ctx, rm, revokedTokenKey(tokenID), "1", expiresAt)
+}
+
+func RevokeUserTokens(ctx context.Context, rm *RedisManager, username string, issuedBefore time.Time, expiresAt time.Time) error {
+	return setRevocation(ctx, rm, revokedUserKey(username), strconv.FormatInt(issuedBefore.UnixNano(), 10), expiresAt)
+}
+
+// IsTokenRevoked는 tokenID가 폐기되었거나 issuedAt이 사용자 폐기 시각 이전이면 true를 반환한다
+func IsTokenRevoked(ctx context.Context, rm *RedisManager, tokenID string, username string, issuedAt time.Time) (bool, error) {
+	if err := rm.AllowRequest(); err != nil {
+		return false, err
+	}
+
+	pipe := rm.Client.Pipeline()
+	token := pipe.Exists(ctx, revokedTokenKey(tokenID))
+	user := pipe.Get(ctx, revokedUserKey(username))
+	_, err := pipe.Exec(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		rm.connectionFailure(err)
+		return false, err
+	}
+	rm.connectionSuccess()
+
+	if tokenID != "" && token.Val() > 0 {
+		return true, nil
+	}
+
+	issuedBefore, err := user.Int64()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return !issuedAt.After(time.Unix(0, issuedBefore)), nil
+}
+
+func setRevocation(ctx context.Context, rm *RedisManager, key string, value string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := rm.AllowRequest(); err != nil {
+		return err
+	}
+
+	err := rm.Client.Set(ctx, key, value, ttl).Err()
+	if err != nil {
+		rm.connectionFailure(err)
+		return err
+	}
+
+	rm.connectionSuccess()
+	return nil
+}
+
+func revokedTokenKey(tokenID string) string {
+	return "revoked:token:" + tokenID
+}
+
+func revokedUserKey(username string) string {
+	return "revoked:user:" + username
+}
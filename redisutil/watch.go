@@ -0,0 +1,36 @@
+package redisutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrConcurrentUpdate는 다른 요청이 계속 key를 바꿔서 ctx가 끝날 때까지 transaction을 마치지 못했다는 뜻이다
+var ErrConcurrentUpdate = errors.New("keys are updated concurrently")
+
+// maxWatchBackoff는 다른 요청과 부딪혀 transaction이 취소된 뒤 다시 시도하기 전에 기다리는 최대 시간이다
+const maxWatchBackoff = 20 * time.Millisecond
+
+// watch는 keys를 WATCH한 채로 fn을 실행한다
+// 그 사이 다른 요청이 keys를 바꿔 transaction이 취소되면 ctx가 끝날 때까지 fn을 처음부터 다시 실행한다
+func watch(ctx context.Context, rm *RedisManager, fn func(tx *redis.Tx) error, keys ...string) error {
+	for attempt := 0; ; attempt++ {
+		err := rm.Client.Watch(ctx, fn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+
+		// 같이 실패한 요청이 동시에 다시 부딪히지 않도록 기다리는 시간을 흩뜨린다
+		backoff := min(time.Millisecond<<min(attempt, 5), maxWatchBackoff)
+		select {
+		case <-time.After(rand.N(backoff)):
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrConcurrentUpdate, ctx.Err())
+		}
+	}
+}
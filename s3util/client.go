@@ -0,0 +1,262 @@
+package s3util
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinPartSize는 S3가 받는 multipart part의 최소 크기이며 마지막 part만 이보다 작을 수 있다
+const MinPartSize = 5 << 20
+
+var ErrNoSuchKey = errors.New("s3 object does not exist")
+
+// Client는 path-style 주소(endpoint/bucket/key)로 S3 호환 스토리지와 통신한다
+type Client struct {
+	endpoint   *url.URL
+	bucket     string
+	signer     *Signer
+	httpClient *http.Client
+}
+
+type Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+type CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []CompletedPart `xml:"Part"`
+}
+
+func NewClient(endpoint string, region string, bucket string, accessKey string, secretKey string) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint: %s", endpoint)
+	}
+
+	return &Client{
+		endpoint: u,
+		bucket:   bucket,
+		signer: &Signer{
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			Region:    region,
+		},
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("s3 error %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (c *Client) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	res, err := c.do(ctx, http.MethodPut, key, nil, header, data)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (c *Client) GetObject(ctx context.Context, key string) ([]byte, error) {
+	res, err := c.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return io.ReadAll(res.Body)
+}
+
+func (c *Client) DeleteObject(ctx context.Context, key string) error {
+	res, err := c.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// ListObjects는 prefix로 시작하는 모든 object key를 ListObjectsV2로 나열한다
+// 한 번에 받지 못한 key는 continuation token으로 이어서 받는다
+func (c *Client) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	token := ""
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"prefix":    {prefix},
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		res, err := c.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &listBucketResult{}
+		err = xml.NewDecoder(res.Body).Decode(result)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("can not decode list objects result: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (c *Client) CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error) {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	res, err := c.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, header, nil)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	result := &initiateMultipartUploadResult{}
+	err = xml.NewDecoder(res.Body).Decode(result)
+	if err != nil {
+		return "", fmt.Errorf("can not decode multipart upload result: %w", err)
+	}
+
+	return result.UploadID, nil
+}
+
+func (c *Client) UploadPart(ctx context.Context, key string, uploadID string, partNumber int, data []byte) (string, error) {
+	query := url.Values{
+		"partNumber": {strconv.Itoa(partNumber)},
+		"uploadId":   {uploadID},
+	}
+
+	res, err := c.do(ctx, http.MethodPut, key, query, nil, data)
+	if err != nil {
+		return "", err
+	}
+	res.Body.Close()
+
+	return res.Header.Get("ETag"), nil
+}
+
+func (c *Client) CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []CompletedPart) error {
+	body, err := xml.Marshal(&completeMultipartUpload{Parts: parts})
+	if err != nil {
+		return fmt.Errorf("can not encode multipart parts: %w", err)
+	}
+
+	res, err := c.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, nil, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// S3는 200 응답 본문에 에러를 담아 보낼 수 있다
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("can not read complete multipart result: %w", err)
+	}
+	if bytes.Contains(data, []byte("<Error>")) {
+		return decodeError(res.StatusCode, data)
+	}
+
+	return nil
+}
+
+func (c *Client) AbortMultipartUpload(ctx context.Context, key string, uploadID string) error {
+	res, err := c.do(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method string, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + c.bucket + "/" + key
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("can not create s3 request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	c.signer.Sign(req, PayloadHash(body), time.Now())
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+
+		data, _ := io.ReadAll(res.Body)
+		return nil, decodeError(res.StatusCode, data)
+	}
+
+	return res, nil
+}
+
+func decodeError(statusCode int, data []byte) error {
+	s3Err := &Error{StatusCode: statusCode}
+	if err := xml.Unmarshal(data, s3Err); err != nil {
+		s3Err.Message = string(data)
+	}
+
+	if s3Err.Code == "NoSuchKey" || (statusCode == http.StatusNotFound && s3Err.Code == "") {
+		return fmt.Errorf("%w: %v", ErrNoSuchKey, s3Err)
+	}
+
+	return s3Err
+}
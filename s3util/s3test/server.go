@@ -0,0 +1,255 @@
+// Package s3test는 테스트에서 사용할 in-process S3 호환 서버를 제공한다
+package s3test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/JeongWoo-Seo/pcBook/s3util"
+	"github.com/google/uuid"
+)
+
+// Server는 서명을 검증하고 object와 multipart upload를 메모리에 보관한다
+type Server struct {
+	*httptest.Server
+
+	mutax   sync.Mutex
+	signer  *s3util.Signer
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+
+	MultipartCompleted int
+	PartsCompleted     int
+
+	// MaxKeys는 ListObjectsV2 한 번에 돌려줄 key 수이며 0이면 1000이다
+	MaxKeys int
+}
+
+type completeMultipartUpload struct {
+	Parts []s3util.CompletedPart `xml:"Part"`
+}
+
+func NewServer(region string, accessKey string, secretKey string) *Server {
+	server := &Server{
+		signer: &s3util.Signer{
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			Region:    region,
+		},
+		objects: make(map[string][]byte),
+		uploads: make(map[string]map[int][]byte),
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+
+	return server
+}
+
+func (server *Server) Object(bucket string, key string) ([]byte, bool) {
+	server.mutax.Lock()
+	defer server.mutax.Unlock()
+
+	data, ok := server.objects[bucket+"/"+key]
+	return data, ok
+}
+
+func (server *Server) ObjectCount() int {
+	server.mutax.Lock()
+	defer server.mutax.Unlock()
+
+	return len(server.objects)
+}
+
+func (server *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	if err := server.verify(r, body); err != nil {
+		writeError(w, http.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+
+	server.mutax.Lock()
+	defer server.mutax.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		uploadID := uuid.New().String()
+		server.uploads[uploadID] = make(map[int][]byte)
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadID)
+
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		parts := server.uploads[query.Get("uploadId")]
+		if parts == nil {
+			writeError(w, http.StatusNotFound, "NoSuchUpload", "upload does not exist")
+			return
+		}
+		partNumber, err := strconv.Atoi(query.Get("partNumber"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidArgument", "invalid part number")
+			return
+		}
+		parts[partNumber] = body
+		w.Header().Set("ETag", etag(body))
+
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		parts := server.uploads[query.Get("uploadId")]
+		if parts == nil {
+			writeError(w, http.StatusNotFound, "NoSuchUpload", "upload does not exist")
+			return
+		}
+
+		complete := &completeMultipartUpload{}
+		if err := xml.Unmarshal(body, complete); err != nil {
+			writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+			return
+		}
+		sort.Slice(complete.Parts, func(i, j int) bool {
+			return complete.Parts[i].PartNumber < complete.Parts[j].PartNumber
+		})
+
+		data := []byte{}
+		for i, part := range complete.Parts {
+			partData, ok := parts[part.PartNumber]
+			if !ok || etag(partData) != part.ETag {
+				writeError(w, http.StatusBadRequest, "InvalidPart", "part does not match")
+				return
+			}
+			// 실제 S3처럼 마지막 part가 아니면 최소 크기를 요구한다
+			if i < len(complete.Parts)-1 && len(partData) < s3util.MinPartSize {
+				writeError(w, http.StatusBadRequest, "EntityTooSmall", "part is smaller than the minimum allowed size")
+				return
+			}
+			data = append(data, partData...)
+		}
+
+		server.objects[name] = data
+		delete(server.uploads, query.Get("uploadId"))
+		server.MultipartCompleted++
+		server.PartsCompleted += len(complete.Parts)
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(server.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPut:
+		server.objects[name] = body
+		w.Header().Set("ETag", etag(body))
+
+	case r.Method == http.MethodGet && query.Get("list-type") == "2":
+		server.listObjects(w, strings.TrimSuffix(name, "/"), query.Get("prefix"), query.Get("continuation-token"))
+
+	case r.Method == http.MethodGet:
+		data, ok := server.objects[name]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey", "object does not exist")
+			return
+		}
+		w.Write(data)
+
+	case r.Method == http.MethodDelete:
+		delete(server.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method)
+	}
+}
+
+// listObjects는 continuation token을 마지막으로 보낸 key로 사용한다
+func (server *Server) listObjects(w http.ResponseWriter, bucket string, prefix string, token string) {
+	maxKeys := server.MaxKeys
+	if maxKeys <= 0 {
+		maxKeys = 1000
+	}
+
+	keys := []string{}
+	for name := range server.objects {
+		key, ok := strings.CutPrefix(name, bucket+"/")
+		if ok && strings.HasPrefix(key, prefix) && key > token {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	truncated := len(keys) > maxKeys
+	if truncated {
+		keys = keys[:maxKeys]
+	}
+
+	fmt.Fprint(w, "<ListBucketResult>")
+	for _, key := range keys {
+		fmt.Fprint(w, "<Contents><Key>")
+		xml.EscapeText(w, []byte(key))
+		fmt.Fprint(w, "</Key></Contents>")
+	}
+	fmt.Fprintf(w, "<IsTruncated>%t</IsTruncated>", truncated)
+	if truncated {
+		fmt.Fprint(w, "<NextContinuationToken>")
+		xml.EscapeText(w, []byte(keys[len(keys)-1]))
+		fmt.Fprint(w, "</NextContinuationToken>")
+	}
+	fmt.Fprint(w, "</ListBucketResult>")
+}
+
+func (server *Server) verify(r *http.Request, body []byte) error {
+	auth := r.Header.Get("Authorization")
+	credential := between(auth, "Credential=", ",")
+	signedHeaders := between(auth, "SignedHeaders=", ",")
+	signature := auth[strings.LastIndex(auth, "Signature=")+len("Signature="):]
+
+	if !strings.HasPrefix(credential, server.signer.AccessKey+"/") {
+		return fmt.Errorf("unknown access key")
+	}
+
+	payloadHash := r.Header.Get(s3util.HeaderContentSHA256)
+	if payloadHash != s3util.PayloadHash(body) {
+		return fmt.Errorf("payload hash does not match")
+	}
+
+	expected := server.signer.Signature(r, strings.Split(signedHeaders, ";"), payloadHash, r.Header.Get(s3util.HeaderDate))
+	if signature != expected {
+		return fmt.Errorf("signature does not match")
+	}
+
+	return nil
+}
+
+func between(value string, prefix string, suffix string) string {
+	start := strings.Index(value, prefix)
+	if start < 0 {
+		return ""
+	}
+	value = value[start+len(prefix):]
+
+	end := strings.Index(value, suffix)
+	if end < 0 {
+		return value
+	}
+	return value[:end]
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code string, message string) {
+	w.WriteHeader(statusCode)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, message)
+}
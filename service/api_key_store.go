@@ -0,0 +1,171 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIKeyRole은 api key로 인증한 호출자의 role이다
+// policy에서 device role에 준 permission 안에서만 key의 scope가 의미를 가진다
+const APIKeyRole = "device"
+
+var ErrAPIKeyInvalid = errors.New("api key is invalid or revoked")
+
+// APIKeyStore는 api key의 hash만 저장한다
+type APIKeyStore interface {
+	Create(key *APIKey) error
+	Find(id string) (*APIKey, error)
+	List() ([]*APIKey, error)
+	Revoke(id string) (*APIKey, error)
+}
+
+// APIKey는 Methods에 있는 rpc만 호출할 수 있고
+// LaptopIDs가 비어 있지 않으면 그 laptop만 다룰 수 있다
+// OrgID는 key를 만든 관리자의 organization이다
+type APIKey struct {
+	ID        string
+	Hash      string
+	Name      string
+	Methods   []string
+	LaptopIDs []string
+	CreatedBy string
+	OrgID     string
+	Revoked   bool
+	CreatedAt time.Time
+	RevokedAt time.Time
+}
+
+type InmemoryAPIKeyStore struct {
+	mutax sync.RWMutex
+	keys  map[string]*APIKey
+}
+
+func NewInMemoryAPIKeyStore() *InmemoryAPIKeyStore {
+	return &InmemoryAPIKeyStore{
+		keys: make(map[string]*APIKey),
+	}
+}
+
+// NewAPIKey는 client에 보낼 "<id>.<secret>" 형식의 key와 store에 저장할 정보를 만든다
+func NewAPIKey(name string, methods []string, laptopIDs []string, createdBy string) (string, *APIKey, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, fmt.Errorf("can not generate api key id: %w", err)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("can not generate api key: %w", err)
+	}
+
+	key := &APIKey{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Methods:   append([]string(nil), methods...),
+		LaptopIDs: append([]string(nil), laptopIDs...),
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+	value := key.ID + "." + base64.RawURLEncoding.EncodeToString(secret)
+	key.Hash = HashAPIKey(value)
+	return value, key, nil
+}
+
+func HashAPIKey(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseAPIKeyID는 key에서 id 부분을 꺼낸다
+func ParseAPIKeyID(value string) (string, error) {
+	id, _, ok := strings.Cut(value, ".")
+	if !ok || id == "" {
+		return "", ErrAPIKeyInvalid
+	}
+	return id, nil
+}
+
+func (store *InmemoryAPIKeyStore) Create(key *APIKey) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	if store.keys[key.ID] != nil {
+		return ErrAlreadyExists
+	}
+
+	store.keys[key.ID] = key.Clone()
+	return nil
+}
+
+func (store *InmemoryAPIKeyStore) Find(id string) (*APIKey, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	key := store.keys[id]
+	if key == nil {
+		return nil, nil
+	}
+	return key.Clone(), nil
+}
+
+// List는 생성된 순서로 key를 반환한다
+func (store *InmemoryAPIKeyStore) List() ([]*APIKey, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	keys := make([]*APIKey, 0, len(store.keys))
+	for _, key := range store.keys {
+		keys = append(keys, key.Clone())
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func (store *InmemoryAPIKeyStore) Revoke(id string) (*APIKey, error) {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	key := store.keys[id]
+	if key == nil {
+		return nil, ErrNotFound
+	}
+	if !key.Revoked {
+		key.Revoked = true
+		key.RevokedAt = time.Now()
+	}
+	return key.Clone(), nil
+}
+
+// Verify는 value가 폐기되지 않은 이 key와 일치하는지 확인한다
+func (key *APIKey) Verify(value string) bool {
+	if key.Revoked {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(value)), []byte(key.Hash)) == 1
+}
+
+func (key *APIKey) AllowsMethod(method string) bool {
+	for _, other := range key.Methods {
+		if other == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (key *APIKey) Clone() *APIKey {
+	other := *key
+	other.Methods = append([]string(nil), key.Methods...)
+	other.LaptopIDs = append([]string(nil), key.LaptopIDs...)
+	return &other
+}
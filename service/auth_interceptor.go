@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -11,13 +15,108 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// userStore가 있으면 token이 유효해도 삭제되거나 비활성화된 사용자를 거부하고
+// token 발급 이후 바뀐 role과 organization을 적용한다
+// revocations가 있으면 logout 등으로 폐기된 token을 거부한다
+// apiKeys가 있으면 "ApiKey <key>" header로 device agent를 인증한다
+// SetCertIdentities로 certIdentities를 지정하면 rpc마다 mTLS client 인증서로도 인증한다
+// SetOrgStore로 orgStore를 지정하면 삭제된 organization의 api key와 client 인증서를 거부한다
+// policy는 서버 실행 중에 SetPolicy로 교체할 수 있다
 type AuthInterceptor struct {
-	tokenManager   *PasetoManager
-	accessibleRole map[string][]string
+	tokenManager *PasetoManager
+	userStore    UserStore
+	revocations  RevocationStore
+	apiKeys      APIKeyStore
+	policy       atomic.Pointer[Policy]
+
+	certIdentities atomic.Pointer[CertIdentities]
+	orgStore       OrgStore
+
+	mutax   sync.Mutex
+	methods []string
+}
+
+func NewAuthInterceptor(tokenManager *PasetoManager, userStore UserStore, revocations RevocationStore, apiKeys APIKeyStore, policy *Policy) *AuthInterceptor {
+	interceptor := &AuthInterceptor{
+		tokenManager: tokenManager,
+		userStore:    userStore,
+		revocations:  revocations,
+		apiKeys:      apiKeys,
+	}
+	interceptor.policy.Store(policy)
+	return interceptor
+}
+
+// CheckCoverage는 server에 등록된 모든 rpc에 policy rule이 있는지 확인하고
+// 이후 SetPolicy에서도 같은 method로 검사하도록 기억해 둔다
+func (i *AuthInterceptor) CheckCoverage(services map[string]grpc.ServiceInfo) error {
+	methods := []string{}
+	for name, info := range services {
+		for _, method := range info.Methods {
+			methods = append(methods, "/"+name+"/"+method.Name)
+		}
+	}
+	sort.Strings(methods)
+
+	i.mutax.Lock()
+	defer i.mutax.Unlock()
+
+	if err := checkPolicyCoverage(i.policy.Load(), methods); err != nil {
+		return err
+	}
+	i.methods = methods
+	return nil
+}
+
+// Policy는 현재 적용 중인 policy를 반환한다
+func (i *AuthInterceptor) Policy() *Policy {
+	return i.policy.Load()
+}
+
+// SetPolicy는 새 policy가 등록된 rpc를 모두 다루는 경우에만 교체한다
+func (i *AuthInterceptor) SetPolicy(policy *Policy) error {
+	i.mutax.Lock()
+	defer i.mutax.Unlock()
+
+	if err := checkPolicyCoverage(policy, i.methods); err != nil {
+		return err
+	}
+	i.policy.Store(policy)
+	return nil
+}
+
+// SetCertIdentities는 client 인증서 mapping을 교체하며 nil이면 token으로만 인증한다
+func (i *AuthInterceptor) SetCertIdentities(identities *CertIdentities) {
+	i.certIdentities.Store(identities)
+}
+
+// SetOrgStore는 서버가 요청을 받기 전에 호출해야 한다
+func (i *AuthInterceptor) SetOrgStore(orgStore OrgStore) {
+	i.orgStore = orgStore
+}
+
+// checkOrg는 token이 아닌 방법으로 인증한 호출자의 organization이 남아 있는지 확인한다
+// 사용자는 organization을 삭제하기 전에 모두 옮겨지므로 token은 확인하지 않는다
+func (i *AuthInterceptor) checkOrg(orgID string) error {
+	if i.orgStore == nil {
+		return nil
+	}
+
+	org, err := i.orgStore.Find(orgOrDefault(orgID))
+	if err != nil {
+		return status.Errorf(codes.Internal, "can not find organization: %v", err)
+	}
+	if org == nil {
+		return status.Errorf(codes.Unauthenticated, "organization %s no longer exists", orgID)
+	}
+	return nil
 }
 
-func NewAuthInterceptor(tokenManager *PasetoManager, accessibleRole map[string][]string) *AuthInterceptor {
-	return &AuthInterceptor{tokenManager: tokenManager, accessibleRole: accessibleRole}
+func checkPolicyCoverage(policy *Policy, methods []string) error {
+	if uncovered := policy.Uncovered(methods); len(uncovered) > 0 {
+		return fmt.Errorf("policy has no rule for: %s", strings.Join(uncovered, ", "))
+	}
+	return nil
 }
 
 func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
@@ -27,12 +126,16 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (any, error) {
-		log.Println("--> unary interceptor: ", info.FullMethod)
-		err := i.Authorize(ctx, info.FullMethod)
+		principal, err := i.Authorize(ctx, info.FullMethod)
+		log.Printf("--> unary interceptor: %s, principal: %s", info.FullMethod, principal)
 		if err != nil {
+			audit(nil, info.FullMethod, err)
 			return nil, err
 		}
-		return handler(ctx, req)
+
+		res, err := handler(contextWithPrincipal(ctx, principal), req)
+		audit(principal, info.FullMethod, err)
+		return res, err
 	}
 }
 
@@ -43,51 +146,218 @@ func (i *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
 		info *grpc.StreamServerInfo,
 		handler grpc.StreamHandler,
 	) error {
-		err := i.Authorize(ss.Context(), info.FullMethod)
+		principal, err := i.Authorize(ss.Context(), info.FullMethod)
+		log.Printf("--> stream interceptor: %s, principal: %s", info.FullMethod, principal)
 		if err != nil {
+			audit(nil, info.FullMethod, err)
 			return err
 		}
-		return handler(srv, ss)
+
+		err = handler(srv, &authServerStream{ServerStream: ss, ctx: contextWithPrincipal(ss.Context(), principal)})
+		audit(principal, info.FullMethod, err)
+		return err
 	}
 }
 
-func (i *AuthInterceptor) Authorize(ctx context.Context, method string) error {
-	accessibleRoles, ok := i.accessibleRole[method]
+// audit는 rpc를 호출한 결과를 남기며 인증에 실패했거나 인증하지 않은 호출도 남긴다
+func audit(principal *Principal, method string, err error) {
+	log.Print(auditRecord(principal, method, err))
+}
+
+// auditRecord는 principal이 nil이면 호출자를 "-"로 남긴다
+func auditRecord(principal *Principal, method string, err error) string {
+	if principal == nil {
+		principal = &Principal{Username: "-", Role: "-", AuthMethod: "-", TokenID: "-"}
+	}
+	return fmt.Sprintf("audit: user=%s role=%s auth=%s token=%s method=%s code=%s",
+		principal.Username, principal.Role, principal.AuthMethod, principal.TokenID, method, status.Code(err))
+}
+
+// Authorize는 공개 rpc이면 nil principal을 반환한다
+// 다만 유효한 authorization header가 있으면 호출자 organization의 catalog를 보도록 그 principal을 반환한다
+// policy에 rule이 없는 rpc는 누구도 호출할 수 없다
+// 인증서와 token을 함께 요구하는 rpc는 두 호출자 모두 permission이 있어야 한다
+// 2단계 인증이 필요한 role의 token은 인증서와 함께 보내더라도 2단계 인증을 마친 로그인에서 발급되어야 한다
+func (i *AuthInterceptor) Authorize(ctx context.Context, method string) (*Principal, error) {
+	policy := i.policy.Load()
+	permission, ok := policy.Permission(method)
 	if !ok {
-		return nil
+		return nil, status.Errorf(codes.PermissionDenied, "rpc is not allowed by the policy")
+	}
+	if permission == PublicPermission {
+		if !hasAuthorizationHeader(ctx) {
+			return nil, nil
+		}
+		principal, err := i.authorizeHeader(ctx, method)
+		if err != nil {
+			return nil, nil
+		}
+		return principal, nil
+	}
+
+	var principal *Principal
+	var err error
+	switch i.certIdentities.Load().Mode(method) {
+	case AuthModeCert:
+		principal, err = i.authorizeCert(ctx)
+	case AuthModeCertOrToken:
+		if hasAuthorizationHeader(ctx) {
+			principal, err = i.authorizeHeader(ctx, method)
+		} else {
+			principal, err = i.authorizeCert(ctx)
+		}
+	case AuthModeCertAndToken:
+		var certPrincipal *Principal
+		certPrincipal, err = i.authorizeCert(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !policy.HasPermission(certPrincipal.Role, permission) {
+			return nil, status.Errorf(codes.PermissionDenied, "client certificate has no permission to access rpc")
+		}
+		principal, err = i.authorizeHeader(ctx, method)
+		if err == nil {
+			principal.AuthMethod = AuthMethodCertAndToken
+		}
+	default:
+		principal, err = i.authorizeHeader(ctx, method)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !policy.HasPermission(principal.Role, permission) {
+		return nil, status.Errorf(codes.PermissionDenied, "no permission to access rpc")
 	}
+	if (principal.AuthMethod == AuthMethodToken || principal.AuthMethod == AuthMethodCertAndToken) && !principal.TwoFactor &&
+		policy.RequiresTwoFactor(principal.Role) && !twoFactorSetupMethods[method] {
+		return nil, status.Errorf(codes.PermissionDenied, "role %s requires two-factor authentication", principal.Role)
+	}
+	return principal, nil
+}
 
+// 2단계 인증이 필요한 role도 TOTP를 등록하거나 자신의 권한을 확인할 수 있도록 2단계 인증 없이 호출할 수 있는 rpc
+var twoFactorSetupMethods = map[string]bool{
+	"/pcbook.AuthService/EnrollTOTP":  true,
+	"/pcbook.AuthService/ConfirmTOTP": true,
+	"/pcbook.AuthService/Logout":      true,
+	"/pcbook.AuthService/WhoAmI":      true,
+}
+
+func hasAuthorizationHeader(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(md.Get(authorizationHeader)) > 0
+}
+
+// authorizeHeader는 authorization header의 bearer token이나 api key로 호출자를 찾는다
+func (i *AuthInterceptor) authorizeHeader(ctx context.Context, method string) (*Principal, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return status.Errorf(codes.Unauthenticated, "metadata is empty")
+		return nil, status.Errorf(codes.Unauthenticated, "metadata is empty")
 	}
 
 	value := md.Get(authorizationHeader)
 	if len(value) == 0 {
-		return status.Errorf(codes.Unauthenticated, "missing authorization header")
+		return nil, status.Errorf(codes.Unauthenticated, "missing authorization header")
 	}
 
 	authHeader := value[0]
 	field := strings.Fields(authHeader)
 	if len(field) < 2 {
-		return status.Errorf(codes.Unauthenticated, "invalid auth header format")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid auth header format")
 	}
 
-	authType := strings.ToLower(field[0])
-	if authType != authorizationBearer {
-		return status.Errorf(codes.Unauthenticated, "unsupported authorization type: %s", authType)
+	switch authType := strings.ToLower(field[0]); authType {
+	case authorizationBearer:
+		return i.authorizeToken(field[1])
+	case authorizationAPIKey:
+		return i.authorizeAPIKey(method, field[1])
+	default:
+		return nil, status.Errorf(codes.Unauthenticated, "unsupported authorization type: %s", authType)
 	}
+}
+
+// authorizeCert는 검증된 client 인증서를 CertIdentities에서 찾는다
+func (i *AuthInterceptor) authorizeCert(ctx context.Context) (*Principal, error) {
+	cert := peerCertificate(ctx)
+	if cert == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "missing verified client certificate")
+	}
+
+	identity := i.certIdentities.Load().Identify(cert)
+	if identity == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "client certificate %q is not mapped to an identity", cert.Subject.CommonName)
+	}
+	if err := i.checkOrg(identity.OrgID); err != nil {
+		return nil, err
+	}
+	return newCertPrincipal(identity, cert), nil
+}
 
-	accessToken := field[1]
+func (i *AuthInterceptor) authorizeToken(accessToken string) (*Principal, error) {
 	payload, err := i.tokenManager.VerifyToken(accessToken)
 	if err != nil {
-		return status.Errorf(codes.Unauthenticated, "invalid token")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
 
-	for _, role := range accessibleRoles {
-		if role == payload.Role {
-			return nil
+	// 폐기 여부를 확인할 수 없으면 token을 받아들이지 않는다
+	if i.revocations != nil {
+		revoked, err := i.revocations.IsRevoked(payload)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "can not check token revocation: %v", err)
+		}
+		if revoked {
+			return nil, status.Errorf(codes.Unauthenticated, "token has been revoked")
+		}
+	}
+
+	if i.userStore != nil {
+		user, err := i.userStore.Find(payload.Username)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "can not find user: %v", err)
 		}
+		if user == nil || user.Disabled {
+			return nil, status.Errorf(codes.Unauthenticated, "user is disabled or no longer exists")
+		}
+		payload.Role = user.Role
+		payload.OrgID = user.Org()
+	}
+	return newTokenPrincipal(payload), nil
+}
+
+// authorizeAPIKey는 key가 유효하고 method가 key의 scope에 있는지 확인한다
+func (i *AuthInterceptor) authorizeAPIKey(method string, value string) (*Principal, error) {
+	if i.apiKeys == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "api keys are not enabled")
 	}
-	return status.Errorf(codes.PermissionDenied, "no permission to access rpc")
+
+	id, err := ParseAPIKeyID(value)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+	}
+	key, err := i.apiKeys.Find(id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can not find api key: %v", err)
+	}
+	if key == nil || !key.Verify(value) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+	}
+
+	if !key.AllowsMethod(method) {
+		return nil, status.Errorf(codes.PermissionDenied, "api key is not allowed to call rpc")
+	}
+	if err := i.checkOrg(key.OrgID); err != nil {
+		return nil, err
+	}
+	return newAPIKeyPrincipal(key), nil
+}
+
+// authServerStream은 인증된 principal을 담은 context를 handler에 전달한다
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
 }
@@ -2,40 +2,595 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
 
 	"github.com/JeongWoo-Seo/pcBook/pb"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const defaultUserRole = "user"
+
 type AuthServer struct {
 	pb.UnimplementedAuthServiceServer
-	UserStore    UserStore
-	TokenManager *PasetoManager
+	UserStore            UserStore
+	TokenManager         *PasetoManager
+	RefreshTokens        RefreshTokenStore
+	Revocations          RevocationStore
+	RefreshTokenDuration time.Duration
+	PasswordPolicy       PasswordPolicy
+	LoginThrottle        *LoginThrottle
+	LoginChallenges      LoginChallengeStore
+
+	// Policy는 WhoAmI가 role의 permission을 계산할 때 현재 rbac policy를 얻는다
+	Policy func() *Policy
 }
 
-func NewAuthServer(userStore UserStore, tokenManager *PasetoManager) *AuthServer {
+func NewAuthServer(userStore UserStore, tokenManager *PasetoManager, refreshTokens RefreshTokenStore, revocations RevocationStore) *AuthServer {
 	return &AuthServer{
-		UserStore:    userStore,
-		TokenManager: tokenManager,
+		UserStore:            userStore,
+		TokenManager:         tokenManager,
+		RefreshTokens:        refreshTokens,
+		Revocations:          revocations,
+		RefreshTokenDuration: RefreshTokenDuration,
+		PasswordPolicy:       DefaultPasswordPolicy,
+		LoginThrottle:        NewLoginThrottle(NewInMemoryLoginAttemptStore()),
+		LoginChallenges:      NewInMemoryLoginChallengeStore(),
 	}
 }
 
+// dummyUser는 없는 사용자로 로그인할 때도 bcrypt 비교를 해서
+// 응답 시간으로 username이 있는지 알 수 없게 한다
+var dummyUser = sync.OnceValue(func() *User {
+	user, err := NewUser("", "dummy-password", "")
+	if err != nil {
+		panic(err)
+	}
+	return user
+})
+
 func (server *AuthServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
-	user, err := server.UserStore.Find(req.GetUsername())
+	username, err := NormalizeUsername(req.GetUsername())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect user info")
+	}
+
+	// 시도는 비밀번호를 확인하기 전에 실패로 세고 성공하면 되돌린다
+	attempt, retryAfter, err := server.LoginThrottle.Attempt(username, clientIP(ctx))
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Unavailable, "can not record login attempt: %v", err))
+	}
+	if retryAfter > 0 {
+		return nil, logErr(retryAfterError(retryAfter))
+	}
+
+	user, err := server.UserStore.Find(username)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "can no find user: %v", err)
 	}
 
+	if user == nil {
+		dummyUser().IsCorrectPassword(req.GetPassword())
+	}
 	if user == nil || !user.IsCorrectPassword(req.GetPassword()) {
 		return nil, status.Errorf(codes.InvalidArgument, "incorrect user info")
 	}
 
-	token, err := server.TokenManager.CreateToken(user)
+	if user.Disabled {
+		return nil, status.Errorf(codes.PermissionDenied, "user is disabled")
+	}
+
+	// 2단계 인증을 켠 사용자는 token 대신 VerifyTOTP에 보낼 challenge를 받는다
+	// VerifyTOTP를 마칠 때까지 username에 센 시도는 실패로 남겨서 로그인과 틀린 code를 번갈아 보내도 잠긴다
+	if user.TwoFactorEnabled() {
+		if err := attempt.ReleaseIP(); err != nil {
+			log.Printf("can not release login attempt: %v", err)
+		}
+		challengeToken, challenge, err := NewLoginChallenge(user.Username, LoginChallengeDuration)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create login challenge: %v", err)
+		}
+		err = server.LoginChallenges.Create(challenge)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to save login challenge: %v", err)
+		}
+		return &pb.LoginResponse{TwoFactorRequired: true, ChallengeToken: challengeToken}, nil
+	}
+
+	if err := attempt.Succeed(); err != nil {
+		log.Printf("can not reset login failures: %v", err)
+	}
+
+	token, refreshToken, err := server.issueTokens(user, false)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.LoginResponse{AccessToken: token, RefreshToken: refreshToken}
+	return res, nil
+}
+
+// VerifyTOTP는 Login이 반환한 challenge와 TOTP code나 recovery code로 로그인을 마친다
+// challenge는 한 번만 쓸 수 있으므로 code가 틀리면 다시 로그인해야 한다
+func (server *AuthServer) VerifyTOTP(ctx context.Context, req *pb.VerifyTOTPRequest) (*pb.VerifyTOTPResponse, error) {
+	challenge, err := server.LoginChallenges.Take(HashLoginChallenge(req.GetChallengeToken()))
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find login challenge: %v", err))
+	}
+	if challenge == nil {
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "login challenge is invalid or expired"))
+	}
+
+	attempt, retryAfter, err := server.LoginThrottle.Attempt(challenge.Username, clientIP(ctx))
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Unavailable, "can not record login attempt: %v", err))
+	}
+	if retryAfter > 0 {
+		return nil, logErr(retryAfterError(retryAfter))
+	}
+
+	user, err := server.UserStore.Find(challenge.Username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	if user == nil || user.Disabled {
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "user is disabled or no longer exists"))
+	}
+
+	if !user.VerifySecondFactor(req.GetCode(), time.Now()) {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "incorrect two-factor code"))
+	}
+	// 같은 code로 동시에 확인한 요청은 version 검사에 걸려 하나만 저장되고 나머지는 실패한다
+	if err := server.updateUser(user); err != nil {
+		return nil, err
+	}
+	if err := attempt.Succeed(); err != nil {
+		log.Printf("can not reset login failures: %v", err)
+	}
+
+	token, refreshToken, err := server.issueTokens(user, true)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("verified two-factor login of user: %s", user.Username)
+	return &pb.VerifyTOTPResponse{AccessToken: token, RefreshToken: refreshToken}, nil
+}
+
+// issueTokens는 새 로그인의 access token과 refresh token family를 만든다
+func (server *AuthServer) issueTokens(user *User, twoFactor bool) (string, string, error) {
+	token, err := server.createAccessToken(user, twoFactor)
+	if err != nil {
+		return "", "", err
+	}
+
+	familyID, err := uuid.NewRandom()
+	if err != nil {
+		return "", "", status.Errorf(codes.Internal, "failed to create token family: %v", err)
+	}
+
+	refreshToken, info, err := NewRefreshToken(familyID.String(), user.Username, server.RefreshTokenDuration)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create token: %v", err)
+		return "", "", status.Errorf(codes.Internal, "failed to create refresh token: %v", err)
 	}
+	info.TwoFactor = twoFactor
 
-	res := &pb.LoginResponse{AccessToken: token}
+	err = server.RefreshTokens.Create(info)
+	if err != nil {
+		return "", "", status.Errorf(codes.Internal, "failed to save refresh token: %v", err)
+	}
+
+	return token, refreshToken, nil
+}
+
+func (server *AuthServer) createAccessToken(user *User, twoFactor bool) (string, error) {
+	create := server.TokenManager.CreateToken
+	if twoFactor {
+		create = server.TokenManager.CreateTwoFactorToken
+	}
+
+	token, err := create(user)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to create token: %v", err)
+	}
+	return token, nil
+}
+
+// RefreshToken은 refresh token을 새 token으로 교체하고 새 access token을 발급한다
+// 이미 교체된 token이 다시 오면 같은 로그인에서 이어진 token을 모두 폐기한다
+func (server *AuthServer) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	refreshToken, next, err := NewRefreshToken("", "", server.RefreshTokenDuration)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "failed to create refresh token: %v", err))
+	}
+
+	previous, err := server.RefreshTokens.Rotate(HashRefreshToken(req.GetRefreshToken()), next)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrRefreshTokenReused):
+			log.Printf("refresh token reuse detected, token family is revoked")
+			return nil, logErr(status.Errorf(codes.Unauthenticated, "%v", err))
+		case errors.Is(err, ErrRefreshTokenInvalid):
+			return nil, logErr(status.Errorf(codes.Unauthenticated, "%v", err))
+		default:
+			return nil, logErr(status.Errorf(codes.Internal, "can not rotate refresh token: %v", err))
+		}
+	}
+
+	user, err := server.UserStore.Find(previous.Username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	if user == nil || user.Disabled {
+		if err := server.RefreshTokens.RevokeFamily(previous.FamilyID); err != nil {
+			log.Printf("can not revoke refresh token family: %v", err)
+		}
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "user is disabled or no longer exists"))
+	}
+
+	// 2단계 인증을 마친 로그인에서 이어진 refresh token이면 새 access token도 2단계 인증을 마친 것으로 본다
+	accessToken, err := server.createAccessToken(user, previous.TwoFactor)
+	if err != nil {
+		return nil, logErr(err)
+	}
+
+	return &pb.RefreshTokenResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// Logout은 요청에 사용한 access token을 만료 시각까지 폐기하고
+// refresh token을 함께 보내면 그 token으로 이어지는 family도 폐기한다
+func (server *AuthServer) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "logout requires an authenticated user"))
+	}
+
+	if req.GetRefreshToken() != "" {
+		token, err := server.RefreshTokens.Find(HashRefreshToken(req.GetRefreshToken()))
+		if err != nil {
+			return nil, logErr(status.Errorf(codes.Internal, "can not find refresh token: %v", err))
+		}
+		if token == nil || token.Username != principal.Username {
+			return nil, logErr(status.Errorf(codes.InvalidArgument, "%v", ErrRefreshTokenInvalid))
+		}
+
+		err = server.RefreshTokens.RevokeFamily(token.FamilyID)
+		if err != nil {
+			return nil, logErr(status.Errorf(codes.Internal, "can not revoke refresh token: %v", err))
+		}
+	}
+
+	err := server.Revocations.RevokeToken(principal.TokenID, principal.ExpiresAt)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not revoke access token: %v", err))
+	}
+
+	log.Printf("logged out user: %s", principal.Username)
+	return &pb.LogoutResponse{}, nil
+}
+
+// GetVerificationKeys는 다른 서비스가 v2.public token을 검증할 수 있게 공개키를 반환한다
+// 이전 키로 발급된 token도 검증할 수 있도록 만료되지 않은 키를 모두 보낸다
+func (server *AuthServer) GetVerificationKeys(ctx context.Context, req *pb.GetVerificationKeysRequest) (*pb.GetVerificationKeysResponse, error) {
+	keys := server.TokenManager.VerificationKeys()
+	if keys == nil {
+		return nil, logErr(status.Errorf(codes.FailedPrecondition, "tokens are not signed with public keys"))
+	}
+
+	res := &pb.GetVerificationKeysResponse{Keys: make([]*pb.VerificationKey, 0, len(keys))}
+	for _, key := range keys {
+		res.Keys = append(res.Keys, toPbVerificationKey(key))
+	}
 	return res, nil
 }
+
+// Register로 만든 사용자는 항상 user role을 가진다
+func (server *AuthServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	username, err := NormalizeUsername(req.GetUsername())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+
+	if err := server.PasswordPolicy.Check(username, req.GetPassword()); err != nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+
+	user, err := NewUser(username, req.GetPassword(), defaultUserRole)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not create user: %v", err))
+	}
+
+	err = server.UserStore.Save(user)
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrAlreadyExists) {
+			code = codes.AlreadyExists
+		}
+		return nil, logErr(status.Errorf(code, "can not save user: %v", err))
+	}
+
+	log.Printf("registered user: %s", username)
+	return &pb.RegisterResponse{Username: username}, nil
+}
+
+func (server *AuthServer) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "change password requires an authenticated user"))
+	}
+
+	user, err := server.UserStore.Find(principal.Username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	if user == nil || !user.IsCorrectPassword(req.GetOldPassword()) {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "incorrect user info"))
+	}
+
+	err = server.setPassword(user, req.GetNewPassword())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("changed password of user: %s", user.Username)
+	return &pb.ChangePasswordResponse{}, nil
+}
+
+// ResetPassword는 이전 비밀번호 없이 관리자가 비밀번호를 바꾼다
+func (server *AuthServer) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	username, err := NormalizeUsername(req.GetUsername())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+
+	user, err := server.UserStore.Find(username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	if user == nil || !PrincipalFromContext(ctx).CanAccessOrg(user.Org()) {
+		return nil, logErr(status.Errorf(codes.NotFound, "user %s is not found", username))
+	}
+
+	err = server.setPassword(user, req.GetNewPassword())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("reset password of user: %s", username)
+	return &pb.ResetPasswordResponse{}, nil
+}
+
+func (server *AuthServer) setPassword(user *User, password string) error {
+	if err := server.PasswordPolicy.Check(user.Username, password); err != nil {
+		return logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+
+	if err := user.SetPassword(password); err != nil {
+		return logErr(status.Errorf(codes.Internal, "can not set password: %v", err))
+	}
+
+	return server.updateUser(user)
+}
+
+func (server *AuthServer) updateUser(user *User) error {
+	err := server.UserStore.Update(user)
+	if err != nil {
+		return logErr(userStoreError(err, "can not save user"))
+	}
+
+	return nil
+}
+
+// EnrollTOTP는 새 secret을 만들어 두고 ConfirmTOTP로 code를 확인하기 전까지는 2단계 인증을 켜지 않는다
+func (server *AuthServer) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	user, err := server.principalUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, logErr(status.Errorf(codes.FailedPrecondition, "two-factor authentication is already enabled"))
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "%v", err))
+	}
+	user.PendingTOTPSecret = secret
+	if err := server.updateUser(user); err != nil {
+		return nil, err
+	}
+
+	return &pb.EnrollTOTPResponse{
+		Secret:          secret,
+		ProvisioningUri: TOTPProvisioningURI(secret, user.Username),
+	}, nil
+}
+
+// ConfirmTOTP는 등록 중인 secret의 code를 확인해 2단계 인증을 켜고 recovery code를 발급한다
+func (server *AuthServer) ConfirmTOTP(ctx context.Context, req *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	user, err := server.principalUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.PendingTOTPSecret == "" {
+		return nil, logErr(status.Errorf(codes.FailedPrecondition, "two-factor enrollment is not started"))
+	}
+
+	step, ok := verifyTOTP(user.PendingTOTPSecret, req.GetCode(), time.Now(), 0)
+	if !ok {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "incorrect two-factor code"))
+	}
+
+	recoveryCodes, hashes, err := NewRecoveryCodes()
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "%v", err))
+	}
+
+	user.TOTPSecret = user.PendingTOTPSecret
+	user.PendingTOTPSecret = ""
+	user.TOTPLastStep = step
+	user.RecoveryCodes = hashes
+	if err := server.updateUser(user); err != nil {
+		return nil, err
+	}
+
+	log.Printf("enabled two-factor authentication of user: %s", user.Username)
+	return &pb.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTOTP는 비밀번호와 2단계 인증 code를 모두 확인한 뒤 2단계 인증을 끈다
+func (server *AuthServer) DisableTOTP(ctx context.Context, req *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error) {
+	user, err := server.principalUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled() {
+		return nil, logErr(status.Errorf(codes.FailedPrecondition, "two-factor authentication is not enabled"))
+	}
+	if !user.IsCorrectPassword(req.GetPassword()) || !user.VerifySecondFactor(req.GetCode(), time.Now()) {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "incorrect user info"))
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.RecoveryCodes = nil
+	if err := server.updateUser(user); err != nil {
+		return nil, err
+	}
+
+	log.Printf("disabled two-factor authentication of user: %s", user.Username)
+	return &pb.DisableTOTPResponse{}, nil
+}
+
+// principalUser는 요청한 사용자를 찾는다
+func (server *AuthServer) principalUser(ctx context.Context) (*User, error) {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "rpc requires an authenticated user"))
+	}
+
+	user, err := server.UserStore.Find(principal.Username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	if user == nil {
+		return nil, logErr(status.Errorf(codes.NotFound, "user %s is not found", principal.Username))
+	}
+	return user, nil
+}
+
+// WhoAmI는 interceptor가 인증한 호출자와 현재 policy에서 그 role이 가진 permission을 반환한다
+func (server *AuthServer) WhoAmI(ctx context.Context, req *pb.WhoAmIRequest) (*pb.WhoAmIResponse, error) {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "rpc requires an authenticated user"))
+	}
+
+	res := &pb.WhoAmIResponse{
+		Username:   principal.Username,
+		Role:       principal.Role,
+		AuthMethod: principal.AuthMethod,
+		TokenId:    principal.TokenID,
+		TwoFactor:  principal.TwoFactor,
+		LaptopIds:  principal.LaptopIDs,
+		OrgId:      principal.Org(),
+	}
+	if !principal.ExpiresAt.IsZero() {
+		res.ExpiresAt = timestamppb.New(principal.ExpiresAt)
+	}
+	if server.Policy != nil {
+		policy := server.Policy()
+		res.Permissions = policy.Permissions(principal.Role)
+		res.TwoFactorRequired = policy.RequiresTwoFactor(principal.Role)
+	}
+	return res, nil
+}
+
+// IntrospectToken은 access token이 지금 받아들여지는지와 그렇지 않은 이유를 알려준다
+// interceptor와 같은 순서로 키와 서명, 만료, 폐기, 사용자 상태를 확인한다
+// super-admin이 아니면 자기 organization의 token만 볼 수 있다
+func (server *AuthServer) IntrospectToken(ctx context.Context, req *pb.IntrospectTokenRequest) (*pb.IntrospectTokenResponse, error) {
+	payload, err := server.TokenManager.InspectToken(req.GetToken())
+	if err != nil {
+		return &pb.IntrospectTokenResponse{
+			State:  pb.IntrospectTokenResponse_INVALID,
+			Reason: err.Error(),
+		}, nil
+	}
+
+	if !PrincipalFromContext(ctx).CanAccessOrg(payload.OrgID) {
+		return nil, logErr(status.Errorf(codes.PermissionDenied, "token belongs to another organization"))
+	}
+
+	res := &pb.IntrospectTokenResponse{
+		Username:  payload.Username,
+		Role:      payload.Role,
+		TokenId:   payload.Jti,
+		TwoFactor: payload.TwoFactor,
+		OrgId:     payload.OrgID,
+	}
+	if !payload.IssuedAt.IsZero() {
+		res.IssuedAt = timestamppb.New(payload.IssuedAt)
+	}
+	if !payload.Expiration.IsZero() {
+		res.ExpiresAt = timestamppb.New(payload.Expiration)
+	}
+
+	if err := payload.ValidAt(time.Now()); err != nil {
+		res.State = pb.IntrospectTokenResponse_INVALID
+		if errors.Is(err, ErrTokenExpired) {
+			res.State = pb.IntrospectTokenResponse_EXPIRED
+		}
+		res.Reason = err.Error()
+		return res, nil
+	}
+
+	revoked, err := server.Revocations.IsRevoked(payload)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Unavailable, "can not check token revocation: %v", err))
+	}
+	if revoked {
+		res.State = pb.IntrospectTokenResponse_REVOKED
+		res.Reason = "token has been revoked by logout or session revocation"
+		return res, nil
+	}
+
+	user, err := server.UserStore.Find(payload.Username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	switch {
+	case user == nil:
+		res.State = pb.IntrospectTokenResponse_USER_DISABLED
+		res.Reason = "user no longer exists"
+	case user.Disabled:
+		res.State = pb.IntrospectTokenResponse_USER_DISABLED
+		res.Reason = "user is disabled"
+	default:
+		res.State = pb.IntrospectTokenResponse_ACTIVE
+		res.Active = true
+		if user.Role != payload.Role {
+			res.Reason = fmt.Sprintf("role changed to %s after the token was issued, %s is used for authorization", user.Role, user.Role)
+		}
+	}
+	return res, nil
+}
+
+// retryAfterError는 client가 다시 시도할 수 있는 시간을 RetryInfo detail로 알려준다
+func retryAfterError(retryAfter time.Duration) error {
+	retryAfter = retryAfter.Round(time.Second) + time.Second
+	st := status.Newf(codes.ResourceExhausted, "too many failed login attempts, retry after %s", retryAfter)
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
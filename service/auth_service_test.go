@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	t.Parallel()
+
+	username, err := NormalizeUsername("  Alice.Kim ")
+	require.NoError(t, err)
+	require.Equal(t, "alice.kim", username)
+
+	for _, invalid := range []string{"", "ab", "-alice", "alice kim", "알리스", "a23456789012345678901234567890123"} {
+		_, err := NormalizeUsername(invalid)
+		require.ErrorIs(t, err, ErrInvalidUsername, invalid)
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# common passwords\npassword1\r\n\nletmein123\n"), 0o600))
+
+	breached, err := LoadBreachedPasswords(path)
+	require.NoError(t, err)
+	require.Len(t, breached, 2)
+
+	policy := PasswordPolicy{MinLength: 8, Breached: breached}
+	require.NoError(t, policy.Check("alice", "correct horse"))
+
+	for _, weak := range []string{"short", "password1", "letmein123", "Alice123", string(make([]byte, 73))} {
+		require.ErrorIs(t, policy.Check("alice123", weak), ErrWeakPassword, weak)
+	}
+}
+
+func TestAuthServerPasswordManagement(t *testing.T) {
+	t.Parallel()
+
+	userStore := NewInMemoryUserStore()
+	server := NewAuthServer(userStore, NewPasetoManager(TokenKey, TokenDuration), NewInMemoryRefreshTokenStore(), NewInMemoryRevocationStore())
+
+	res, err := server.Register(context.Background(), &pb.RegisterRequest{Username: " Alice ", Password: "first-password"})
+	require.NoError(t, err)
+	require.Equal(t, "alice", res.GetUsername())
+
+	_, err = server.Register(context.Background(), &pb.RegisterRequest{Username: "ALICE", Password: "other-password"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = server.Register(context.Background(), &pb.RegisterRequest{Username: "bob", Password: "short"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.Login(context.Background(), &pb.LoginRequest{Username: "Alice", Password: "first-password"})
+	require.NoError(t, err)
+
+	aliceCtx := contextWithPrincipal(context.Background(), &Principal{Username: "alice", Role: defaultUserRole})
+
+	_, err = server.ChangePassword(aliceCtx, &pb.ChangePasswordRequest{OldPassword: "wrong-password", NewPassword: "second-password"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.ChangePassword(context.Background(), &pb.ChangePasswordRequest{OldPassword: "first-password", NewPassword: "second-password"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = server.ChangePassword(aliceCtx, &pb.ChangePasswordRequest{OldPassword: "first-password", NewPassword: "second-password"})
+	require.NoError(t, err)
+
+	_, err = server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "first-password"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.ResetPassword(context.Background(), &pb.ResetPasswordRequest{Username: "alice", NewPassword: "third-password"})
+	require.NoError(t, err)
+
+	_, err = server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "third-password"})
+	require.NoError(t, err)
+
+	_, err = server.ResetPassword(context.Background(), &pb.ResetPasswordRequest{Username: "nobody", NewPassword: "third-password"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAuthServerRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	userStore := NewInMemoryUserStore()
+	user, err := NewUser("alice", "secret-password", defaultUserRole)
+	require.NoError(t, err)
+	require.NoError(t, userStore.Save(user))
+
+	tokenManager := NewPasetoManager(TokenKey, TokenDuration)
+	server := NewAuthServer(userStore, tokenManager, NewInMemoryRefreshTokenStore(), NewInMemoryRevocationStore())
+
+	login, err := server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.NoError(t, err)
+	require.NotEmpty(t, login.GetRefreshToken())
+
+	refreshed, err := server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: login.GetRefreshToken()})
+	require.NoError(t, err)
+	require.NotEqual(t, login.GetRefreshToken(), refreshed.GetRefreshToken())
+
+	payload, err := tokenManager.VerifyToken(refreshed.GetAccessToken())
+	require.NoError(t, err)
+	require.Equal(t, "alice", payload.Username)
+
+	// 교체 전 token을 재사용하면 새 token까지 모두 폐기된다
+	_, err = server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: login.GetRefreshToken()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: refreshed.GetRefreshToken()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// 비활성화된 사용자는 refresh token으로 access token을 받을 수 없다
+	login, err = server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.NoError(t, err)
+
+	user.Disabled = true
+	require.NoError(t, userStore.Update(user))
+
+	_, err = server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: login.GetRefreshToken()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthServerLoginLockout(t *testing.T) {
+	t.Parallel()
+
+	userStore := NewInMemoryUserStore()
+	user, err := NewUser("alice", "secret-password", defaultUserRole)
+	require.NoError(t, err)
+	require.NoError(t, userStore.Save(user))
+
+	server := NewAuthServer(userStore, NewPasetoManager(TokenKey, TokenDuration), NewInMemoryRefreshTokenStore(), NewInMemoryRevocationStore())
+	server.LoginThrottle.User = BackoffPolicy{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+
+	for i := 0; i < 3; i++ {
+		_, err = server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "wrong-password"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	// 잠긴 동안에는 올바른 비밀번호도 거부하고 다시 시도할 시간을 알려준다
+	_, err = server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	st := status.Convert(err)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	require.Greater(t, retryInfo.GetRetryDelay().AsDuration(), 59*time.Second)
+
+	require.NoError(t, server.LoginThrottle.Unlock("alice"))
+	_, err = server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.NoError(t, err)
+
+	// 없는 사용자도 같은 방식으로 잠긴다
+	for i := 0; i < 3; i++ {
+		_, err = server.Login(context.Background(), &pb.LoginRequest{Username: "nobody", Password: "wrong-password"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+	_, err = server.Login(context.Background(), &pb.LoginRequest{Username: "nobody", Password: "wrong-password"})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestAuthServerTwoFactorLogin(t *testing.T) {
+	t.Parallel()
+
+	userStore := NewInMemoryUserStore()
+	user, err := NewUser("alice", "secret-password", defaultUserRole)
+	require.NoError(t, err)
+	require.NoError(t, userStore.Save(user))
+
+	tokenManager := NewPasetoManager(TokenKey, TokenDuration)
+	server := NewAuthServer(userStore, tokenManager, NewInMemoryRefreshTokenStore(), NewInMemoryRevocationStore())
+	aliceCtx := contextWithPrincipal(context.Background(), &Principal{Username: "alice", Role: defaultUserRole})
+
+	_, err = server.ConfirmTOTP(aliceCtx, &pb.ConfirmTOTPRequest{Code: "000000"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	enroll, err := server.EnrollTOTP(aliceCtx, &pb.EnrollTOTPRequest{})
+	require.NoError(t, err)
+	require.Contains(t, enroll.GetProvisioningUri(), enroll.GetSecret())
+
+	// 확인하기 전에는 2단계 인증 없이 로그인한다
+	login, err := server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.NoError(t, err)
+	require.False(t, login.GetTwoFactorRequired())
+
+	code, err := TOTPCode(enroll.GetSecret(), time.Now())
+	require.NoError(t, err)
+	confirm, err := server.ConfirmTOTP(aliceCtx, &pb.ConfirmTOTPRequest{Code: code})
+	require.NoError(t, err)
+	require.Len(t, confirm.GetRecoveryCodes(), recoveryCodeCount)
+
+	login, err = server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.NoError(t, err)
+	require.True(t, login.GetTwoFactorRequired())
+	require.Empty(t, login.GetAccessToken())
+	require.Empty(t, login.GetRefreshToken())
+
+	// 틀린 code를 보내면 challenge를 다시 쓸 수 없다
+	_, err = server.VerifyTOTP(context.Background(), &pb.VerifyTOTPRequest{ChallengeToken: login.GetChallengeToken(), Code: "not-a-code"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.VerifyTOTP(context.Background(), &pb.VerifyTOTPRequest{ChallengeToken: login.GetChallengeToken(), Code: confirm.GetRecoveryCodes()[0]})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	login, err = server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.NoError(t, err)
+	verified, err := server.VerifyTOTP(context.Background(), &pb.VerifyTOTPRequest{ChallengeToken: login.GetChallengeToken(), Code: confirm.GetRecoveryCodes()[0]})
+	require.NoError(t, err)
+
+	payload, err := tokenManager.VerifyToken(verified.GetAccessToken())
+	require.NoError(t, err)
+	require.True(t, payload.TwoFactor)
+
+	// refresh token으로 받은 access token도 2단계 인증을 마친 것으로 본다
+	refreshed, err := server.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: verified.GetRefreshToken()})
+	require.NoError(t, err)
+	payload, err = tokenManager.VerifyToken(refreshed.GetAccessToken())
+	require.NoError(t, err)
+	require.True(t, payload.TwoFactor)
+
+	_, err = server.DisableTOTP(aliceCtx, &pb.DisableTOTPRequest{Password: "secret-password", Code: "000000"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = server.DisableTOTP(aliceCtx, &pb.DisableTOTPRequest{Password: "secret-password", Code: confirm.GetRecoveryCodes()[1]})
+	require.NoError(t, err)
+
+	login, err = server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.NoError(t, err)
+	require.False(t, login.GetTwoFactorRequired())
+}
+
+func TestAuthServerTwoFactorLockout(t *testing.T) {
+	t.Parallel()
+
+	server, _, _ := newTwoFactorAuthServer(t)
+	server.LoginThrottle.User = BackoffPolicy{FreeAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+
+	// 비밀번호가 맞아도 2단계 인증을 마치기 전에는 실패 기록이 지워지지 않는다
+	for i := 0; i < 2; i++ {
+		login, err := server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+		require.NoError(t, err)
+		require.True(t, login.GetTwoFactorRequired())
+		_, err = server.VerifyTOTP(context.Background(), &pb.VerifyTOTPRequest{ChallengeToken: login.GetChallengeToken(), Code: "not-a-code"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	_, err := server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestAuthServerTwoFactorCodeReuse(t *testing.T) {
+	t.Parallel()
+
+	server, confirm, secret := newTwoFactorAuthServer(t)
+	server.LoginThrottle.User = BackoffPolicy{FreeAttempts: 100, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+
+	// 등록할 때 쓴 step 다음 code이다
+	totpCode, err := TOTPCode(secret, time.Now().Add(totpPeriod))
+	require.NoError(t, err)
+
+	// 같은 code를 동시에 보내도 한 번만 로그인한다
+	for _, code := range []string{confirm.GetRecoveryCodes()[0], totpCode} {
+		challenges := []string{}
+		for i := 0; i < 5; i++ {
+			login, err := server.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+			require.NoError(t, err)
+			challenges = append(challenges, login.GetChallengeToken())
+		}
+
+		var wg sync.WaitGroup
+		var verified atomic.Int32
+		for _, challenge := range challenges {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := server.VerifyTOTP(context.Background(), &pb.VerifyTOTPRequest{ChallengeToken: challenge, Code: code})
+				if err == nil {
+					verified.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, int32(1), verified.Load())
+	}
+}
+
+// newTwoFactorAuthServer는 2단계 인증을 켠 alice를 저장한 server와 alice의 recovery code, TOTP secret을 반환한다
+func newTwoFactorAuthServer(t *testing.T) (*AuthServer, *pb.ConfirmTOTPResponse, string) {
+	userStore := NewInMemoryUserStore()
+	user, err := NewUser("alice", "secret-password", defaultUserRole)
+	require.NoError(t, err)
+	require.NoError(t, userStore.Save(user))
+
+	server := NewAuthServer(userStore, NewPasetoManager(TokenKey, TokenDuration), NewInMemoryRefreshTokenStore(), NewInMemoryRevocationStore())
+	aliceCtx := contextWithPrincipal(context.Background(), &Principal{Username: "alice", Role: defaultUserRole})
+
+	enroll, err := server.EnrollTOTP(aliceCtx, &pb.EnrollTOTPRequest{})
+	require.NoError(t, err)
+	code, err := TOTPCode(enroll.GetSecret(), time.Now())
+	require.NoError(t, err)
+	confirm, err := server.ConfirmTOTP(aliceCtx, &pb.ConfirmTOTPRequest{Code: code})
+	require.NoError(t, err)
+
+	return server, confirm, enroll.GetSecret()
+}
+
+func TestAuthServerWhoAmI(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParsePolicy([]byte(`{
+		"roles": {
+			"user": {"permissions": ["laptop.read", "account.read"]},
+			"admin": {"inherits": ["user"], "permissions": ["user.admin"], "require_two_factor": true}
+		},
+		"rules": {"/pcbook.AuthService/WhoAmI": "account.read"}
+	}`))
+	require.NoError(t, err)
+
+	server := NewAuthServer(NewInMemoryUserStore(), NewPasetoManager(TokenKey, TokenDuration), NewInMemoryRefreshTokenStore(), NewInMemoryRevocationStore())
+	server.Policy = func() *Policy { return policy }
+
+	_, err = server.WhoAmI(context.Background(), &pb.WhoAmIRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	expiresAt := time.Now().Add(time.Minute)
+	ctx := contextWithPrincipal(context.Background(), &Principal{Username: "root", Role: "admin", TokenID: "jti", AuthMethod: AuthMethodToken, ExpiresAt: expiresAt})
+	res, err := server.WhoAmI(ctx, &pb.WhoAmIRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "root", res.GetUsername())
+	require.Equal(t, AuthMethodToken, res.GetAuthMethod())
+	require.Equal(t, []string{"account.read", "laptop.read", "user.admin"}, res.GetPermissions())
+	require.True(t, res.GetTwoFactorRequired())
+	require.False(t, res.GetTwoFactor())
+	require.True(t, expiresAt.Equal(res.GetExpiresAt().AsTime()))
+}
+
+func TestAuthServerIntrospectToken(t *testing.T) {
+	t.Parallel()
+
+	userStore := NewInMemoryUserStore()
+	user, err := NewUser("alice", "secret-password", defaultUserRole)
+	require.NoError(t, err)
+	require.NoError(t, userStore.Save(user))
+
+	tokenManager := NewPasetoManager(TokenKey, TokenDuration)
+	revocations := NewInMemoryRevocationStore()
+	server := NewAuthServer(userStore, tokenManager, NewInMemoryRefreshTokenStore(), revocations)
+	introspect := func(token string) *pb.IntrospectTokenResponse {
+		res, err := server.IntrospectToken(context.Background(), &pb.IntrospectTokenRequest{Token: token})
+		require.NoError(t, err)
+		return res
+	}
+
+	token, err := tokenManager.CreateToken(user)
+	require.NoError(t, err)
+	res := introspect(token)
+	require.Equal(t, pb.IntrospectTokenResponse_ACTIVE, res.GetState())
+	require.True(t, res.GetActive())
+	require.Equal(t, "alice", res.GetUsername())
+
+	res = introspect(token[:len(token)-4] + "AAAA")
+	require.Equal(t, pb.IntrospectTokenResponse_INVALID, res.GetState())
+	require.False(t, res.GetActive())
+	require.NotEmpty(t, res.GetReason())
+
+	expired, err := NewPasetoManager(TokenKey, -time.Minute).CreateToken(user)
+	require.NoError(t, err)
+	res = introspect(expired)
+	require.Equal(t, pb.IntrospectTokenResponse_EXPIRED, res.GetState())
+	require.Equal(t, "alice", res.GetUsername())
+
+	user.Disabled = true
+	require.NoError(t, userStore.Update(user))
+	require.Equal(t, pb.IntrospectTokenResponse_USER_DISABLED, introspect(token).GetState())
+
+	payload, err := tokenManager.VerifyToken(token)
+	require.NoError(t, err)
+	require.NoError(t, revocations.RevokeToken(payload.Jti, payload.Expiration))
+	require.Equal(t, pb.IntrospectTokenResponse_REVOKED, introspect(token).GetState())
+}
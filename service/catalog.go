@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Catalog은 한 organization의 laptop, 이미지, 평가, review를 저장한다
+type Catalog struct {
+	LaptopStore LaptopStore
+	ImageStore  ImageStore
+	RatingStore RatingStore
+	ReviewStore ReviewStore
+	ImageGC     *ImageGarbageCollector
+
+	imageLocks laptopLocks
+}
+
+func NewCatalog(laptopStore LaptopStore, imageStore ImageStore, ratingStore RatingStore, reviewStore ReviewStore) *Catalog {
+	return &Catalog{
+		LaptopStore: laptopStore,
+		ImageStore:  imageStore,
+		RatingStore: ratingStore,
+		ReviewStore: reviewStore,
+		ImageGC:     NewImageGarbageCollector(laptopStore, imageStore),
+	}
+}
+
+// deleteLaptopData는 삭제된 laptop의 이미지, 평가, review를 지운다
+// 삭제와 동시에 저장된 이미지는 image garbage collector가 지운다
+func (catalog *Catalog) deleteLaptopData(laptopID string) error {
+	if catalog.ImageStore != nil {
+		unlock := catalog.imageLocks.lock(laptopID)
+		defer unlock()
+
+		images, err := catalog.ImageStore.List(laptopID)
+		if err != nil {
+			return fmt.Errorf("can not list images: %w", err)
+		}
+		for _, image := range images {
+			err = catalog.ImageStore.Delete(image.ID)
+			if err != nil {
+				return fmt.Errorf("can not delete image %s: %w", image.ID, err)
+			}
+		}
+	}
+
+	if catalog.RatingStore != nil {
+		err := catalog.RatingStore.DeleteLaptop(laptopID)
+		if err != nil {
+			return fmt.Errorf("can not delete ratings: %w", err)
+		}
+	}
+
+	if catalog.ReviewStore != nil {
+		err := catalog.ReviewStore.DeleteLaptop(laptopID)
+		if err != nil {
+			return fmt.Errorf("can not delete reviews: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CatalogFactory는 organization의 catalog를 처음 사용할 때 만든다
+// ctx는 catalog가 Remove될 때 취소되므로 catalog의 background 작업을 멈추는 데 쓴다
+type CatalogFactory func(ctx context.Context, orgID string) (*Catalog, error)
+
+// Catalogs는 organization마다 따로 만든 catalog를 보관한다
+// handler는 호출자 organization의 catalog만 받으므로 다른 organization의 데이터를 읽을 방법이 없다
+// factory가 nil이면 Set으로 등록한 organization만 사용할 수 있다
+// generations는 Remove할 때마다 늘어나며 만드는 동안 삭제된 organization의 catalog를 버리는 데 쓴다
+type Catalogs struct {
+	mutax       sync.Mutex
+	catalogs    map[string]*Catalog
+	cancels     map[string]context.CancelFunc
+	building    map[string]chan struct{}
+	generations map[string]uint64
+	factory     CatalogFactory
+}
+
+func NewCatalogs(factory CatalogFactory) *Catalogs {
+	return &Catalogs{
+		catalogs:    make(map[string]*Catalog),
+		cancels:     make(map[string]context.CancelFunc),
+		building:    make(map[string]chan struct{}),
+		generations: make(map[string]uint64),
+		factory:     factory,
+	}
+}
+
+// Get은 catalog가 없으면 factory로 만든다
+// factory는 lock 밖에서 실행되므로 다른 organization의 요청을 막지 않으며
+// 같은 organization을 만드는 중이면 다른 호출은 그 factory가 끝날 때까지 기다린다
+// factory가 끝나기 전에 organization이 Remove되면 만든 catalog를 버리고 ctx를 취소한다
+func (catalogs *Catalogs) Get(orgID string) (*Catalog, error) {
+	orgID = orgOrDefault(orgID)
+
+	catalogs.mutax.Lock()
+	for catalogs.catalogs[orgID] == nil && catalogs.building[orgID] != nil {
+		building := catalogs.building[orgID]
+		catalogs.mutax.Unlock()
+		<-building
+		catalogs.mutax.Lock()
+	}
+
+	catalog := catalogs.catalogs[orgID]
+	if catalog != nil {
+		catalogs.mutax.Unlock()
+		return catalog, nil
+	}
+	if catalogs.factory == nil {
+		catalogs.mutax.Unlock()
+		return nil, fmt.Errorf("organization %s has no catalog", orgID)
+	}
+	building := make(chan struct{})
+	catalogs.building[orgID] = building
+	generation := catalogs.generations[orgID]
+	catalogs.mutax.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	catalog, err := catalogs.factory(ctx, orgID)
+
+	catalogs.mutax.Lock()
+	defer catalogs.mutax.Unlock()
+
+	delete(catalogs.building, orgID)
+	close(building)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("can not create catalog of organization %s: %w", orgID, err)
+	}
+	if catalogs.generations[orgID] != generation {
+		cancel()
+		return nil, fmt.Errorf("organization %s was removed", orgID)
+	}
+	catalogs.catalogs[orgID] = catalog
+	catalogs.cancels[orgID] = cancel
+	return catalog, nil
+}
+
+func (catalogs *Catalogs) Set(orgID string, catalog *Catalog) {
+	catalogs.mutax.Lock()
+	defer catalogs.mutax.Unlock()
+
+	catalogs.catalogs[orgID] = catalog
+}
+
+// Remove는 삭제된 organization의 catalog를 버리고 factory에 넘긴 ctx를 취소한다
+// 지금 만들고 있는 catalog는 Get이 factory가 끝난 뒤에 버린다
+func (catalogs *Catalogs) Remove(orgID string) {
+	catalogs.mutax.Lock()
+	defer catalogs.mutax.Unlock()
+
+	if cancel := catalogs.cancels[orgID]; cancel != nil {
+		cancel()
+	}
+	delete(catalogs.catalogs, orgID)
+	delete(catalogs.cancels, orgID)
+	catalogs.generations[orgID]++
+}
+
+// laptopLocks는 laptop마다 이미지 저장을 직렬화해서 제한 확인과 저장 사이에 다른 저장이 끼어들지 못하게 한다
+type laptopLocks struct {
+	mutax sync.Mutex
+	locks map[string]*laptopLock
+}
+
+type laptopLock struct {
+	mutax   sync.Mutex
+	waiters int
+}
+
+// lock은 laptopID의 lock을 잡고 푸는 함수를 반환한다
+// 기다리는 호출자가 없어지면 lock을 map에서 지운다
+func (locks *laptopLocks) lock(laptopID string) func() {
+	locks.mutax.Lock()
+	if locks.locks == nil {
+		locks.locks = make(map[string]*laptopLock)
+	}
+	lock := locks.locks[laptopID]
+	if lock == nil {
+		lock = &laptopLock{}
+		locks.locks[laptopID] = lock
+	}
+	lock.waiters++
+	locks.mutax.Unlock()
+
+	lock.mutax.Lock()
+	return func() {
+		lock.mutax.Unlock()
+
+		locks.mutax.Lock()
+		defer locks.mutax.Unlock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(locks.locks, laptopID)
+		}
+	}
+}
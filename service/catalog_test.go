@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogsGet(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	var built atomic.Int32
+	catalogs := NewCatalogs(func(ctx context.Context, orgID string) (*Catalog, error) {
+		built.Add(1)
+		if orgID == "slow" {
+			<-release
+		}
+		return NewCatalog(NewInMemoryLaptopStore(), nil, NewInMemoryRatingStore(), NewInMemoryReviewStore()), nil
+	})
+
+	// 한 organization의 catalog를 만드는 동안에도 다른 organization은 기다리지 않는다
+	var wg sync.WaitGroup
+	slow := make([]*Catalog, 5)
+	for i := range slow {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			catalog, err := catalogs.Get("slow")
+			require.NoError(t, err)
+			slow[i] = catalog
+		}()
+	}
+
+	fast, err := catalogs.Get("fast")
+	require.NoError(t, err)
+	require.NotNil(t, fast)
+
+	// 같은 organization은 한 번만 만든다
+	close(release)
+	wg.Wait()
+	for _, catalog := range slow {
+		require.Same(t, slow[0], catalog)
+	}
+	require.Equal(t, int32(2), built.Load())
+}
+
+func TestCatalogsRemoveWhileBuilding(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan context.Context, 1)
+	release := make(chan struct{})
+	catalogs := NewCatalogs(func(ctx context.Context, orgID string) (*Catalog, error) {
+		started <- ctx
+		<-release
+		return NewCatalog(NewInMemoryLaptopStore(), nil, NewInMemoryRatingStore(), NewInMemoryReviewStore()), nil
+	})
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := catalogs.Get("acme")
+		errs <- err
+	}()
+	ctx := <-started
+
+	// 만드는 동안 삭제된 organization의 catalog는 저장하지 않고 background 작업도 멈춘다
+	catalogs.Remove("acme")
+	close(release)
+	require.Error(t, <-errs)
+	require.Error(t, ctx.Err())
+
+	catalogs.mutax.Lock()
+	defer catalogs.mutax.Unlock()
+	require.Nil(t, catalogs.catalogs["acme"])
+	require.Nil(t, catalogs.cancels["acme"])
+}
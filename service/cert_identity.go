@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	AuthMethodCert         = "cert"
+	AuthMethodCertAndToken = "cert+token"
+)
+
+// rpc마다 허용하는 인증 방식이다
+// token은 authorization header(bearer token이나 api key)를 뜻한다
+const (
+	AuthModeToken        = "token"
+	AuthModeCert         = "cert"
+	AuthModeCertOrToken  = "cert_or_token"
+	AuthModeCertAndToken = "cert_and_token"
+)
+
+var authModes = map[string]bool{
+	AuthModeToken:        true,
+	AuthModeCert:         true,
+	AuthModeCertOrToken:  true,
+	AuthModeCertAndToken: true,
+}
+
+// CertIdentity는 client 인증서가 나타내는 호출자이다
+// OrgID가 없으면 default organization에 속한다
+type CertIdentity struct {
+	Username  string   `json:"username"`
+	Role      string   `json:"role"`
+	OrgID     string   `json:"org_id"`
+	LaptopIDs []string `json:"laptop_ids"`
+}
+
+// CertIdentities는 검증된 client 인증서의 SAN URI나 subject CN을 호출자에 연결하고
+// rpc마다 인증서와 token 중 어떤 인증을 요구할지 정한다
+// methods에 rule이 없는 rpc는 token으로만 인증한다
+//
+//	{
+//	  "identities": {
+//	    "uri:spiffe://pcbook/indexer": {"username": "indexer", "role": "admin"},
+//	    "cn:laptop-agent": {"username": "laptop-agent", "role": "device"}
+//	  },
+//	  "methods": {
+//	    "/pcbook.LaptopService/SendLaptopInfo": "cert_or_token",
+//	    "/pcbook.UserAdminService/*": "cert_and_token"
+//	  }
+//	}
+type CertIdentities struct {
+	identities map[string]*CertIdentity
+	modes      *methodRules
+}
+
+type certIdentitiesJSON struct {
+	Identities map[string]*CertIdentity `json:"identities"`
+	Methods    map[string]string        `json:"methods"`
+}
+
+func LoadCertIdentities(path string) (*CertIdentities, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not read cert identity file: %w", err)
+	}
+	return ParseCertIdentities(data)
+}
+
+func ParseCertIdentities(data []byte) (*CertIdentities, error) {
+	var file certIdentitiesJSON
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("can not parse cert identities: %w", err)
+	}
+
+	identities := &CertIdentities{
+		identities: make(map[string]*CertIdentity, len(file.Identities)),
+		modes:      newMethodRules(),
+	}
+
+	for name, identity := range file.Identities {
+		if !strings.HasPrefix(name, "cn:") && !strings.HasPrefix(name, "uri:") {
+			return nil, fmt.Errorf("identity %s must start with cn: or uri:", name)
+		}
+		if identity == nil || identity.Username == "" || identity.Role == "" {
+			return nil, fmt.Errorf("identity %s needs a username and a role", name)
+		}
+		if identity.OrgID != "" {
+			orgID, err := NormalizeOrgID(identity.OrgID)
+			if err != nil {
+				return nil, fmt.Errorf("identity %s: %w", name, err)
+			}
+			identity.OrgID = orgID
+		}
+		identities.identities[name] = identity
+	}
+
+	for method, mode := range file.Methods {
+		if !authModes[mode] {
+			return nil, fmt.Errorf("unknown auth mode %s of rule %s", mode, method)
+		}
+		if err := identities.modes.add(method, mode); err != nil {
+			return nil, err
+		}
+	}
+	identities.modes.sort()
+
+	return identities, nil
+}
+
+// Mode는 method에 허용된 인증 방식을 반환한다
+func (identities *CertIdentities) Mode(method string) string {
+	if identities == nil {
+		return AuthModeToken
+	}
+	if mode, ok := identities.modes.lookup(method); ok {
+		return mode
+	}
+	return AuthModeToken
+}
+
+// Identify는 SAN URI를 subject CN보다 먼저 찾는다
+func (identities *CertIdentities) Identify(cert *x509.Certificate) *CertIdentity {
+	if identities == nil {
+		return nil
+	}
+	for _, uri := range cert.URIs {
+		if identity := identities.identities["uri:"+uri.String()]; identity != nil {
+			return identity
+		}
+	}
+	if cert.Subject.CommonName != "" {
+		return identities.identities["cn:"+cert.Subject.CommonName]
+	}
+	return nil
+}
+
+// peerCertificate는 tls handshake에서 검증된 client 인증서를 반환한다
+// 검증되지 않은 인증서는 사용하지 않는다
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return tlsInfo.State.VerifiedChains[0][0]
+}
+
+func newCertPrincipal(identity *CertIdentity, cert *x509.Certificate) *Principal {
+	return &Principal{
+		Username:   identity.Username,
+		Role:       identity.Role,
+		OrgID:      identity.OrgID,
+		TokenID:    cert.SerialNumber.Text(16),
+		AuthMethod: AuthMethodCert,
+		ExpiresAt:  cert.NotAfter,
+		LaptopIDs:  identity.LaptopIDs,
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseCertIdentities(t *testing.T) {
+	t.Parallel()
+
+	_, err := LoadCertIdentities("../cert_identities.json")
+	require.NoError(t, err)
+
+	_, err = ParseCertIdentities([]byte(`{"identities": {"tester": {"username": "a", "role": "user"}}}`))
+	require.Error(t, err)
+
+	_, err = ParseCertIdentities([]byte(`{"identities": {"cn:tester": {"username": "a"}}}`))
+	require.Error(t, err)
+
+	_, err = ParseCertIdentities([]byte(`{"methods": {"/pcbook.LaptopService/SendLaptopInfo": "password"}}`))
+	require.Error(t, err)
+
+	identities, err := ParseCertIdentities([]byte(`{
+		"identities": {
+			"cn:tester": {"username": "tester", "role": "user"},
+			"uri:spiffe://pcbook/agent": {"username": "agent", "role": "device"}
+		},
+		"methods": {
+			"/pcbook.LaptopService/*": "cert_or_token",
+			"/pcbook.LaptopService/CreateLaptop": "cert_and_token"
+		}
+	}`))
+	require.NoError(t, err)
+
+	require.Equal(t, AuthModeCertAndToken, identities.Mode("/pcbook.LaptopService/CreateLaptop"))
+	require.Equal(t, AuthModeCertOrToken, identities.Mode("/pcbook.LaptopService/SearchLaptop"))
+	require.Equal(t, AuthModeToken, identities.Mode("/pcbook.AuthService/Logout"))
+
+	// SAN URI가 CN보다 우선한다
+	cert := newTestClientCert("tester", "spiffe://pcbook/agent")
+	require.Equal(t, "agent", identities.Identify(cert).Username)
+	require.Equal(t, "tester", identities.Identify(newTestClientCert("tester", "")).Username)
+	require.Nil(t, identities.Identify(newTestClientCert("unknown", "")))
+}
+
+func TestAuthorizeCert(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParsePolicy([]byte(`{
+		"roles": {
+			"user": {"permissions": ["laptop.read", "laptop.write"]},
+			"admin": {"inherits": ["user"], "require_two_factor": true},
+			"device": {"permissions": ["laptop.read"]}
+		},
+		"rules": {
+			"/pcbook.LaptopService/SearchLaptop": "laptop.read",
+			"/pcbook.LaptopService/CreateLaptop": "laptop.write",
+			"/pcbook.LaptopService/DeleteLaptop": "laptop.write",
+			"/pcbook.AuthService/Logout": "laptop.read"
+		}
+	}`))
+	require.NoError(t, err)
+	identities, err := ParseCertIdentities([]byte(`{
+		"identities": {
+			"cn:agent": {"username": "agent", "role": "device"},
+			"cn:tester": {"username": "tester", "role": "user"}
+		},
+		"methods": {
+			"/pcbook.LaptopService/SearchLaptop": "cert_or_token",
+			"/pcbook.LaptopService/CreateLaptop": "cert_and_token",
+			"/pcbook.LaptopService/DeleteLaptop": "cert"
+		}
+	}`))
+	require.NoError(t, err)
+
+	tokenManager := NewPasetoManager(TokenKey, TokenDuration)
+	interceptor := NewAuthInterceptor(tokenManager, nil, nil, nil, policy)
+	interceptor.SetCertIdentities(identities)
+
+	token, err := tokenManager.CreateToken(&User{Username: "alice", Role: "user"})
+	require.NoError(t, err)
+
+	certContext := func(ctx context.Context, commonName string) context.Context {
+		return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{newTestClientCert(commonName, "")}},
+		}}})
+	}
+	tokenContext := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, "Bearer "+token))
+
+	principal, err := interceptor.Authorize(certContext(context.Background(), "agent"), "/pcbook.LaptopService/SearchLaptop")
+	require.NoError(t, err)
+	require.Equal(t, "agent", principal.Username)
+	require.Equal(t, AuthMethodCert, principal.AuthMethod)
+
+	principal, err = interceptor.Authorize(certContext(tokenContext, "agent"), "/pcbook.LaptopService/SearchLaptop")
+	require.NoError(t, err)
+	require.Equal(t, "alice", principal.Username)
+
+	_, err = interceptor.Authorize(certContext(context.Background(), "unknown"), "/pcbook.LaptopService/SearchLaptop")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// 인증서만 요구하는 rpc는 token을 받지 않는다
+	_, err = interceptor.Authorize(tokenContext, "/pcbook.LaptopService/DeleteLaptop")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// 인증서와 token을 함께 요구하면 둘 다 permission이 있어야 한다
+	_, err = interceptor.Authorize(tokenContext, "/pcbook.LaptopService/CreateLaptop")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = interceptor.Authorize(certContext(tokenContext, "agent"), "/pcbook.LaptopService/CreateLaptop")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	principal, err = interceptor.Authorize(certContext(tokenContext, "tester"), "/pcbook.LaptopService/CreateLaptop")
+	require.NoError(t, err)
+	require.Equal(t, "alice", principal.Username)
+	require.Equal(t, AuthMethodCertAndToken, principal.AuthMethod)
+	require.False(t, principal.TwoFactor)
+
+	// 인증서는 2단계 인증을 대신하지 않는다
+	adminToken, err := tokenManager.CreateToken(&User{Username: "root", Role: "admin"})
+	require.NoError(t, err)
+	adminContext := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, "Bearer "+adminToken))
+	_, err = interceptor.Authorize(certContext(adminContext, "tester"), "/pcbook.LaptopService/CreateLaptop")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	adminToken, err = tokenManager.CreateTwoFactorToken(&User{Username: "root", Role: "admin"})
+	require.NoError(t, err)
+	adminContext = metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, "Bearer "+adminToken))
+	principal, err = interceptor.Authorize(certContext(adminContext, "tester"), "/pcbook.LaptopService/CreateLaptop")
+	require.NoError(t, err)
+	require.True(t, principal.TwoFactor)
+
+	// mapping이 없는 rpc는 token으로만 인증한다
+	_, err = interceptor.Authorize(certContext(context.Background(), "tester"), "/pcbook.AuthService/Logout")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func newTestClientCert(commonName string, uri string) *x509.Certificate {
+	cert := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if uri != "" {
+		parsed, _ := url.Parse(uri)
+		cert.URIs = []*url.URL{parsed}
+	}
+	return cert
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// BlobLister는 저장소에 실제로 존재하는 blob을 나열할 수 있는 ImageStore가 구현한다
+type BlobLister interface {
+	ListBlobs() ([]string, error)
+	RemoveBlob(path string) error
+}
+
+type ImageGarbageCollector struct {
+	laptopStore LaptopStore
+	imageStore  ImageStore
+}
+
+type ImageGCReport struct {
+	DryRun       bool
+	OrphanFiles  []string     // index에 없는 파일
+	MissingFiles []*ImageInfo // 파일이 없는 index
+	OrphanImages []*ImageInfo // laptop이 삭제된 이미지
+}
+
+func NewImageGarbageCollector(laptopStore LaptopStore, imageStore ImageStore) *ImageGarbageCollector {
+	return &ImageGarbageCollector{
+		laptopStore: laptopStore,
+		imageStore:  imageStore,
+	}
+}
+
+// Collect는 정리 대상을 찾고 dryRun이 아니면 삭제한다
+func (gc *ImageGarbageCollector) Collect(dryRun bool) (*ImageGCReport, error) {
+	report := &ImageGCReport{DryRun: dryRun}
+
+	// 저장 중인 파일을 orphan으로 판단하지 않도록 파일 목록을 index보다 먼저 읽는다
+	var blobs []string
+	lister, canListBlobs := gc.imageStore.(BlobLister)
+	if canListBlobs {
+		var err error
+		blobs, err = lister.ListBlobs()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	images, err := gc.imageStore.ListAll()
+	if err != nil {
+		return nil, fmt.Errorf("can not list images: %w", err)
+	}
+
+	indexed := make(map[string]bool)
+	for _, image := range images {
+		indexed[image.Path] = true
+
+		laptop, err := gc.laptopStore.Find(image.LaptopID)
+		if err != nil {
+			return nil, fmt.Errorf("can not find laptop: %w", err)
+		}
+		if laptop == nil {
+			report.OrphanImages = append(report.OrphanImages, image)
+		}
+	}
+
+	if canListBlobs {
+		stored := make(map[string]bool)
+		for _, path := range blobs {
+			stored[path] = true
+			if !indexed[path] {
+				report.OrphanFiles = append(report.OrphanFiles, path)
+			}
+		}
+
+		for _, image := range images {
+			if !stored[image.Path] {
+				report.MissingFiles = append(report.MissingFiles, image)
+			}
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	removed := make(map[string]bool)
+	for _, image := range append(report.OrphanImages, report.MissingFiles...) {
+		if removed[image.ID] {
+			continue
+		}
+		if err := gc.imageStore.Delete(image.ID); err != nil {
+			return report, fmt.Errorf("can not delete image %s: %w", image.ID, err)
+		}
+		removed[image.ID] = true
+	}
+
+	for _, path := range report.OrphanFiles {
+		if err := lister.RemoveBlob(path); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+func (gc *ImageGarbageCollector) Start(ctx context.Context, tick time.Duration, dryRun bool) {
+	ticker := time.NewTicker(tick)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				report, err := gc.Collect(dryRun)
+				if err != nil {
+					log.Println("image gc error:", err)
+					continue
+				}
+				log.Printf(
+					"image gc (dry run = %t): %d orphan files, %d missing files, %d orphan images",
+					report.DryRun, len(report.OrphanFiles), len(report.MissingFiles), len(report.OrphanImages),
+				)
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
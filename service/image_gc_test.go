@@ -0,0 +1,69 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JeongWoo-Seo/pcBook/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageGarbageCollector(t *testing.T) {
+	t.Parallel()
+
+	imageFolder := t.TempDir()
+	imageStore := NewDiskImageStore(imageFolder)
+	laptopStore := NewInMemoryLaptopStore()
+
+	laptop := util.NewLaptop()
+	require.NoError(t, laptopStore.Save(laptop))
+
+	saveImage := func(laptopID string, data string) string {
+		id, err := imageStore.Save(&ImageInfo{LaptopID: laptopID, Type: ".png", Digest: digestOf([]byte(data))}, *bytes.NewBufferString(data))
+		require.NoError(t, err)
+		return id
+	}
+
+	keptID := saveImage(laptop.Id, "kept")
+	missingID := saveImage(laptop.Id, "missing")
+	orphanID := saveImage("deleted-laptop", "orphan")
+
+	missing, err := imageStore.Find(missingID)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(missing.Path))
+
+	orphanFile := filepath.Join(imageFolder, strings.Repeat("a", 64)+".png")
+	require.NoError(t, os.WriteFile(orphanFile, []byte("half written"), 0644))
+	fixture := filepath.Join(imageFolder, "laptop.png")
+	require.NoError(t, os.WriteFile(fixture, []byte("not a blob"), 0644))
+
+	gc := NewImageGarbageCollector(laptopStore, imageStore)
+
+	report, err := gc.Collect(true)
+	require.NoError(t, err)
+	require.Len(t, report.OrphanFiles, 1)
+	require.Len(t, report.MissingFiles, 1)
+	require.Equal(t, missingID, report.MissingFiles[0].ID)
+	require.Len(t, report.OrphanImages, 1)
+	require.Equal(t, orphanID, report.OrphanImages[0].ID)
+	require.FileExists(t, orphanFile)
+
+	_, err = gc.Collect(false)
+	require.NoError(t, err)
+	require.NoFileExists(t, orphanFile)
+	require.FileExists(t, fixture)
+
+	images, err := imageStore.ListAll()
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	require.Equal(t, keptID, images[0].ID)
+
+	report, err = gc.Collect(true)
+	require.NoError(t, err)
+	require.Empty(t, report.OrphanFiles)
+	require.Empty(t, report.MissingFiles)
+	require.Empty(t, report.OrphanImages)
+}
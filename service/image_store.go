@@ -2,62 +2,355 @@ package service
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidImageOrder = errors.New("image order must contain every image of the laptop exactly once")
+
+	blobNamePattern = regexp.MustCompile(`^[0-9a-f]{64}\.[A-Za-z0-9]+$`)
+)
+
 type ImageStore interface {
-	Save(laptopID string, imageType string, imageData bytes.Buffer) (string, error)
+	Save(image *ImageInfo, imageData bytes.Buffer) (string, error)
+	Find(imageID string) (*ImageInfo, error)
+	List(laptopID string) ([]*ImageInfo, error)
+	Reorder(laptopID string, imageIDs []string) ([]*ImageInfo, error)
+	SetPrimary(laptopID string, imageID string) ([]*ImageInfo, error)
+	ListAll() ([]*ImageInfo, error)
+	Delete(imageID string) error
 }
 
 type DiskImageStore struct {
-	mutax       sync.RWMutex
+	imageIndex
 	imageFolder string
-	images      map[string]*ImageInfo
 }
 
+// ImageInfo의 Position, IsPrimary는 조회할 때 gallery 순서로부터 계산된다
 type ImageInfo struct {
-	LaptopID string
-	Type     string
-	Path     string
+	ID        string
+	LaptopID  string
+	Type      string
+	Digest    string
+	Path      string
+	Size      int
+	AltText   string
+	Position  uint32
+	IsPrimary bool
+	CreatedAt time.Time
+}
+
+// imageIndex는 같은 내용의 이미지를 digest 기준으로 한 번만 저장하고
+// 참조 횟수로 blob 삭제 시점을 결정한다
+// gallery는 laptop별 이미지 순서이며 primary가 없으면 첫 번째 이미지가 대표 이미지가 된다
+// uploads는 저장 중인 digest이며 저장이 끝나면 channel이 닫힌다
+type imageIndex struct {
+	mutax   sync.RWMutex
+	images  map[string]*ImageInfo
+	blobs   map[string]*imageBlob
+	gallery map[string][]string
+	primary map[string]string
+	uploads map[string]chan struct{}
+}
+
+type imageBlob struct {
+	path     string
+	refCount int
 }
 
 func NewDiskImageStore(imageFolder string) *DiskImageStore {
 	return &DiskImageStore{
+		imageIndex:  newImageIndex(),
 		imageFolder: imageFolder,
-		images:      make(map[string]*ImageInfo),
 	}
 }
 
-func (store *DiskImageStore) Save(laptopID string, imageType string, imageData bytes.Buffer) (string, error) {
+func (store *DiskImageStore) Save(image *ImageInfo, imageData bytes.Buffer) (string, error) {
+	return store.save(image, func() (string, error) {
+		imagePath := store.blobPath(blobName(image.Digest, image.Type))
+		return imagePath, writeImageFile(imagePath, imageData)
+	})
+}
+
+// ListBlobs는 image folder에서 digest 이름을 가진 파일만 반환한다
+func (store *DiskImageStore) ListBlobs() ([]string, error) {
+	entries, err := os.ReadDir(store.imageFolder)
+	if err != nil {
+		return nil, fmt.Errorf("can not read image folder: %w", err)
+	}
+
+	paths := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && blobNamePattern.MatchString(entry.Name()) {
+			paths = append(paths, store.blobPath(entry.Name()))
+		}
+	}
+
+	return paths, nil
+}
+
+// RemoveBlob은 index에서 참조하지 않는 파일만 삭제한다
+func (store *DiskImageStore) RemoveBlob(path string) error {
+	return store.removeOrphan(path, removeImageFile)
+}
+
+// Delete는 이미지 참조를 제거하고 더 이상 참조되지 않는 파일만 삭제한다
+func (store *DiskImageStore) Delete(imageID string) error {
+	return store.delete(imageID, removeImageFile)
+}
+
+func (store *DiskImageStore) blobPath(name string) string {
+	return fmt.Sprintf("%s/%s", store.imageFolder, name)
+}
+
+func newImageIndex() imageIndex {
+	return imageIndex{
+		images:  make(map[string]*ImageInfo),
+		blobs:   make(map[string]*imageBlob),
+		gallery: make(map[string][]string),
+		primary: make(map[string]string),
+		uploads: make(map[string]chan struct{}),
+	}
+}
+
+// save는 digest에 해당하는 blob이 없을 때만 put을 호출해 저장한다
+// put은 lock 밖에서 실행되며 같은 digest를 저장하는 다른 호출은 그 put이 끝날 때까지 기다린다
+func (index *imageIndex) save(image *ImageInfo, put func() (string, error)) (string, error) {
 	imageID, err := uuid.NewRandom()
 	if err != nil {
 		return "", fmt.Errorf("failed to create image id : %w", err)
 	}
 
-	imagePath := fmt.Sprintf("%s/%s.%s", store.imageFolder, imageID.String(), imageType)
+	index.mutax.Lock()
+	for index.blobs[image.Digest] == nil && index.uploads[image.Digest] != nil {
+		upload := index.uploads[image.Digest]
+		index.mutax.Unlock()
+		<-upload
+		index.mutax.Lock()
+	}
+
+	if index.blobs[image.Digest] == nil {
+		upload := make(chan struct{})
+		index.uploads[image.Digest] = upload
+		index.mutax.Unlock()
+
+		path, err := put()
+
+		index.mutax.Lock()
+		delete(index.uploads, image.Digest)
+		close(upload)
+		if err != nil {
+			index.mutax.Unlock()
+			return "", err
+		}
+		index.blobs[image.Digest] = &imageBlob{path: path}
+	}
+	defer index.mutax.Unlock()
+
+	blob := index.blobs[image.Digest]
+	blob.refCount++
+
+	other := image.Clone()
+	other.ID = imageID.String()
+	other.Path = blob.path
+	other.CreatedAt = time.Now()
+	index.images[other.ID] = other
+
+	index.gallery[other.LaptopID] = append(index.gallery[other.LaptopID], other.ID)
+	if image.IsPrimary {
+		index.primary[other.LaptopID] = other.ID
+	}
+
+	return other.ID, nil
+}
+
+func (index *imageIndex) Find(imageID string) (*ImageInfo, error) {
+	index.mutax.RLock()
+	defer index.mutax.RUnlock()
+
+	image := index.images[imageID]
+	if image == nil {
+		return nil, nil
+	}
+
+	for _, other := range index.listLocked(image.LaptopID) {
+		if other.ID == imageID {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+func (index *imageIndex) List(laptopID string) ([]*ImageInfo, error) {
+	index.mutax.RLock()
+	defer index.mutax.RUnlock()
+
+	return index.listLocked(laptopID), nil
+}
+
+func (index *imageIndex) ListAll() ([]*ImageInfo, error) {
+	index.mutax.RLock()
+	defer index.mutax.RUnlock()
+
+	images := []*ImageInfo{}
+	for laptopID := range index.gallery {
+		images = append(images, index.listLocked(laptopID)...)
+	}
+
+	return images, nil
+}
+
+func (index *imageIndex) listLocked(laptopID string) []*ImageInfo {
+	imageIDs := index.gallery[laptopID]
+	primaryID := index.primary[laptopID]
+	if primaryID == "" && len(imageIDs) > 0 {
+		primaryID = imageIDs[0]
+	}
+
+	images := make([]*ImageInfo, 0, len(imageIDs))
+	for position, imageID := range imageIDs {
+		image := index.images[imageID].Clone()
+		image.Position = uint32(position)
+		image.IsPrimary = imageID == primaryID
+		images = append(images, image)
+	}
+
+	return images
+}
+
+func (index *imageIndex) Reorder(laptopID string, imageIDs []string) ([]*ImageInfo, error) {
+	index.mutax.Lock()
+	defer index.mutax.Unlock()
+
+	current := index.gallery[laptopID]
+	if len(current) != len(imageIDs) {
+		return nil, ErrInvalidImageOrder
+	}
+
+	seen := make(map[string]bool)
+	for _, imageID := range imageIDs {
+		image := index.images[imageID]
+		if image == nil || image.LaptopID != laptopID || seen[imageID] {
+			return nil, ErrInvalidImageOrder
+		}
+		seen[imageID] = true
+	}
+
+	index.gallery[laptopID] = append([]string(nil), imageIDs...)
+	return index.listLocked(laptopID), nil
+}
+
+func (index *imageIndex) SetPrimary(laptopID string, imageID string) ([]*ImageInfo, error) {
+	index.mutax.Lock()
+	defer index.mutax.Unlock()
+
+	image := index.images[imageID]
+	if image == nil || image.LaptopID != laptopID {
+		return nil, ErrNotFound
+	}
+
+	index.primary[laptopID] = imageID
+	return index.listLocked(laptopID), nil
+}
+
+// delete는 마지막 참조가 사라진 blob에 대해서만 remove를 호출한다
+func (index *imageIndex) delete(imageID string, remove func(path string) error) error {
+	index.mutax.Lock()
+	defer index.mutax.Unlock()
+
+	image := index.images[imageID]
+	if image == nil {
+		return ErrNotFound
+	}
+	delete(index.images, imageID)
 
+	imageIDs := index.gallery[image.LaptopID]
+	for i, id := range imageIDs {
+		if id == imageID {
+			imageIDs = append(imageIDs[:i:i], imageIDs[i+1:]...)
+			break
+		}
+	}
+	if len(imageIDs) == 0 {
+		delete(index.gallery, image.LaptopID)
+	} else {
+		index.gallery[image.LaptopID] = imageIDs
+	}
+	if index.primary[image.LaptopID] == imageID {
+		delete(index.primary, image.LaptopID)
+	}
+
+	blob := index.blobs[image.Digest]
+	if blob == nil {
+		return nil
+	}
+
+	blob.refCount--
+	if blob.refCount > 0 {
+		return nil
+	}
+	delete(index.blobs, image.Digest)
+
+	return remove(blob.path)
+}
+
+// removeOrphan은 index가 참조하지 않고 저장 중이지도 않은 blob에 대해서만 remove를 호출한다
+func (index *imageIndex) removeOrphan(path string, remove func(path string) error) error {
+	index.mutax.Lock()
+	defer index.mutax.Unlock()
+
+	for _, blob := range index.blobs {
+		if blob.path == path {
+			return nil
+		}
+	}
+
+	digest, _, _ := strings.Cut(path[strings.LastIndex(path, "/")+1:], ".")
+	if index.uploads[digest] != nil {
+		return nil
+	}
+
+	return remove(path)
+}
+
+func (image *ImageInfo) Clone() *ImageInfo {
+	other := *image
+	return &other
+}
+
+func blobName(digest string, imageType string) string {
+	return fmt.Sprintf("%s.%s", digest, strings.TrimPrefix(imageType, "."))
+}
+
+func writeImageFile(imagePath string, imageData bytes.Buffer) error {
 	file, err := os.Create(imagePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to create image file: %w", err)
+		return fmt.Errorf("failed to create image file: %w", err)
 	}
+	defer file.Close()
 
 	_, err = imageData.WriteTo(file)
 	if err != nil {
-		return "", fmt.Errorf("can not write image to file: %w", err)
+		file.Close()
+		os.Remove(imagePath)
+		return fmt.Errorf("can not write image to file: %w", err)
 	}
 
-	store.mutax.Lock()
-	defer store.mutax.Unlock()
+	return nil
+}
 
-	store.images[imageID.String()] = &ImageInfo{
-		LaptopID: laptopID,
-		Type:     imageType,
-		Path:     imagePath,
+func removeImageFile(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("can not remove image file: %w", err)
 	}
-
-	return imageID.String(), nil
+	return nil
 }
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskImageStoreDeduplication(t *testing.T) {
+	t.Parallel()
+
+	store := NewDiskImageStore(t.TempDir())
+
+	data := []byte("same press photo")
+	digest := digestOf(data)
+
+	id1, err := store.Save(&ImageInfo{LaptopID: "laptop-1", Type: ".png", Digest: digest}, *bytes.NewBuffer(data))
+	require.NoError(t, err)
+	id2, err := store.Save(&ImageInfo{LaptopID: "laptop-2", Type: ".png", Digest: digest}, *bytes.NewBuffer(data))
+	require.NoError(t, err)
+	require.NotEqual(t, id1, id2)
+
+	image1, err := store.Find(id1)
+	require.NoError(t, err)
+	image2, err := store.Find(id2)
+	require.NoError(t, err)
+	require.Equal(t, image1.Path, image2.Path)
+	require.FileExists(t, image1.Path)
+
+	require.NoError(t, store.Delete(id1))
+	require.FileExists(t, image2.Path)
+
+	require.NoError(t, store.Delete(id2))
+	require.NoFileExists(t, image2.Path)
+
+	require.ErrorIs(t, store.Delete(id2), ErrNotFound)
+}
+
+func TestDiskImageStoreGallery(t *testing.T) {
+	t.Parallel()
+
+	store := NewDiskImageStore(t.TempDir())
+
+	ids := make([]string, 3)
+	for i := range ids {
+		data := []byte{byte(i)}
+		id, err := store.Save(&ImageInfo{LaptopID: "laptop-1", Type: ".png", Digest: digestOf(data)}, *bytes.NewBuffer(data))
+		require.NoError(t, err)
+		ids[i] = id
+	}
+
+	images, err := store.List("laptop-1")
+	require.NoError(t, err)
+	require.Len(t, images, 3)
+	require.True(t, images[0].IsPrimary)
+
+	images, err = store.Reorder("laptop-1", []string{ids[2], ids[0], ids[1]})
+	require.NoError(t, err)
+	require.Equal(t, ids[2], images[0].ID)
+	require.Equal(t, uint32(1), images[1].Position)
+	require.True(t, images[0].IsPrimary)
+
+	_, err = store.Reorder("laptop-1", []string{ids[2], ids[0]})
+	require.ErrorIs(t, err, ErrInvalidImageOrder)
+	_, err = store.Reorder("laptop-1", []string{ids[2], ids[0], ids[0]})
+	require.ErrorIs(t, err, ErrInvalidImageOrder)
+
+	images, err = store.SetPrimary("laptop-1", ids[1])
+	require.NoError(t, err)
+	require.False(t, images[0].IsPrimary)
+	require.True(t, images[2].IsPrimary)
+
+	_, err = store.SetPrimary("laptop-2", ids[1])
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Delete(ids[1]))
+	images, err = store.List("laptop-1")
+	require.NoError(t, err)
+	require.Len(t, images, 2)
+	require.Equal(t, ids[2], images[0].ID)
+	require.True(t, images[0].IsPrimary)
+}
+
+// blob을 저장하는 동안에도 index를 읽을 수 있고 같은 digest는 한 번만 저장한다
+func TestImageIndexSaveOutsideLock(t *testing.T) {
+	t.Parallel()
+
+	index := newImageIndex()
+	release := make(chan struct{})
+	puts := 0
+	put := func() (string, error) {
+		puts++
+		<-release
+		return "blob", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := index.save(&ImageInfo{LaptopID: "laptop-1", Type: ".png", Digest: "digest"}, put)
+			require.NoError(t, err)
+		}()
+	}
+
+	listed := make(chan struct{})
+	go func() {
+		_, err := index.List("laptop-1")
+		require.NoError(t, err)
+		close(listed)
+	}()
+	select {
+	case <-listed:
+	case <-time.After(time.Second):
+		t.Fatal("index is locked while the blob is stored")
+	}
+
+	close(release)
+	wg.Wait()
+
+	images, err := index.List("laptop-1")
+	require.NoError(t, err)
+	require.Len(t, images, 3)
+	require.Equal(t, 1, puts)
+	require.Equal(t, 3, index.blobs["digest"].refCount)
+}
+
+// 저장 중이거나 index가 참조하는 blob은 GC가 orphan으로 보더라도 지우지 않는다
+func TestImageIndexRemoveOrphan(t *testing.T) {
+	t.Parallel()
+
+	index := newImageIndex()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	saved := make(chan error)
+	go func() {
+		_, err := index.save(&ImageInfo{LaptopID: "laptop-1", Type: ".png", Digest: "digest"}, func() (string, error) {
+			close(started)
+			<-release
+			return "images/digest.png", nil
+		})
+		saved <- err
+	}()
+
+	removed := []string{}
+	remove := func(path string) error {
+		removed = append(removed, path)
+		return nil
+	}
+
+	<-started
+	require.NoError(t, index.removeOrphan("images/digest.png", remove))
+	close(release)
+	require.NoError(t, <-saved)
+
+	require.NoError(t, index.removeOrphan("images/digest.png", remove))
+	require.NoError(t, index.removeOrphan("images/other.png", remove))
+	require.Equal(t, []string{"images/other.png"}, removed)
+}
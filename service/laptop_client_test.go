@@ -3,26 +3,34 @@ package service
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/JeongWoo-Seo/pcBook/pb"
 	"github.com/JeongWoo-Seo/pcBook/serializer"
 	"github.com/JeongWoo-Seo/pcBook/util"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestLaptopClient(t *testing.T) {
 	t.Parallel()
 
 	laptopStore := NewInMemoryLaptopStore()
-	severAddress := startTestLaptopServer(t, laptopStore, nil, nil)
+	severAddress, tokenManager := serveTestAuthLaptopServer(t, NewLaptopServer(laptopStore, nil, nil, nil, nil, nil))
 	laptopClient := newTestLaptopClient(t, severAddress)
 
 	laptop := util.NewLaptop()
@@ -31,7 +39,7 @@ func TestLaptopClient(t *testing.T) {
 		Laptop: laptop,
 	}
 
-	res, err := laptopClient.CreateLaptop(context.Background(), req)
+	res, err := laptopClient.CreateLaptop(newTestRoleContext(t, tokenManager, "vendor", "admin"), req)
 	require.NoError(t, err)
 	require.NotNil(t, res)
 	require.Equal(t, expectedID, res.Id)
@@ -39,13 +47,83 @@ func TestLaptopClient(t *testing.T) {
 	other, err := laptopStore.Find(res.Id)
 	require.NoError(t, err)
 	require.NotNil(t, other)
+	require.Equal(t, "vendor", other.GetOwner())
 
+	laptop.Owner = "vendor"
 	requireSameLaptop(t, laptop, other)
 }
 
-func startTestLaptopServer(t *testing.T, laptopStore LaptopStore, imageStore ImageStore, ratingStore RatingStore) string {
-	laptopServer := NewLaptopServer(laptopStore, imageStore, ratingStore, nil)
+func TestLaptopOwnership(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, NewLaptopServer(laptopStore, NewDiskImageStore(t.TempDir()), nil, nil, NewDiskUploadSessionStore(t.TempDir(), time.Hour), nil))
+	laptopClient := newTestLaptopClient(t, serverAddress)
+
+	vendorCtx := newTestRoleContext(t, tokenManager, "vendor", "admin")
+	otherCtx := newTestRoleContext(t, tokenManager, "other", "admin")
+	rootCtx := newTestRoleContext(t, tokenManager, "root", SuperAdminRole)
+
+	// client가 보낸 owner는 무시하고 요청한 사용자를 owner로 저장한다
+	laptop := util.NewLaptop()
+	laptop.Owner = "other"
+	created, err := laptopClient.CreateLaptop(vendorCtx, &pb.CreateLaptopRequest{Laptop: laptop})
+	require.NoError(t, err)
+
+	saved, err := laptopStore.Find(created.GetId())
+	require.NoError(t, err)
+	require.Equal(t, "vendor", saved.GetOwner())
+
+	saved.Name = "renamed"
+	_, err = laptopClient.UpdateLaptop(otherCtx, &pb.UpdateLaptopRequest{Laptop: saved})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = laptopClient.StartImageUpload(otherCtx, &pb.StartImageUploadRequest{
+		Info:      &pb.ImageInfo{LaptopId: saved.GetId(), ImageType: ".png"},
+		TotalSize: 10,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = laptopClient.DeleteLaptop(otherCtx, &pb.DeleteLaptopRequest{LaptopId: saved.GetId()})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// owner가 아닌 사용자는 owner가 시작한 upload도 이어서 할 수 없다
+	start, err := laptopClient.StartImageUpload(vendorCtx, &pb.StartImageUploadRequest{
+		Info:      &pb.ImageInfo{LaptopId: saved.GetId(), ImageType: ".png"},
+		TotalSize: 10,
+	})
+	require.NoError(t, err)
+
+	_, err = laptopClient.UploadImageChunk(otherCtx, &pb.UploadImageChunkRequest{UploadId: start.GetUploadId(), ChunkData: []byte("tiny image")})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	saved.Owner = "other"
+	updated, err := laptopClient.UpdateLaptop(vendorCtx, &pb.UpdateLaptopRequest{Laptop: saved})
+	require.NoError(t, err)
+	require.Equal(t, "renamed", updated.GetLaptop().GetName())
+	require.Equal(t, "vendor", updated.GetLaptop().GetOwner())
+
+	// super-admin은 모든 laptop을 수정하고 owner를 바꿀 수 있다
+	updated, err = laptopClient.UpdateLaptop(rootCtx, &pb.UpdateLaptopRequest{Laptop: saved})
+	require.NoError(t, err)
+	require.Equal(t, "other", updated.GetLaptop().GetOwner())
+
+	_, err = laptopClient.DeleteLaptop(vendorCtx, &pb.DeleteLaptopRequest{LaptopId: saved.GetId()})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
 
+	_, err = laptopClient.DeleteLaptop(otherCtx, &pb.DeleteLaptopRequest{LaptopId: saved.GetId()})
+	require.NoError(t, err)
+
+	_, err = laptopClient.DeleteLaptop(rootCtx, &pb.DeleteLaptopRequest{LaptopId: saved.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func startTestLaptopServer(t *testing.T, laptopStore LaptopStore, imageStore ImageStore, ratingStore RatingStore, uploadStore UploadSessionStore) string {
+	laptopServer := NewLaptopServer(laptopStore, imageStore, ratingStore, nil, uploadStore, nil)
+	return serveTestLaptopServer(t, laptopServer)
+}
+
+func serveTestLaptopServer(t *testing.T, laptopServer *LaptopServer) string {
 	grpcServer := grpc.NewServer()
 	pb.RegisterLaptopServiceServer(grpcServer, laptopServer)
 
@@ -124,7 +202,7 @@ func TestClientSearchLaptop(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	serverAddress := startTestLaptopServer(t, store, nil, nil)
+	serverAddress := startTestLaptopServer(t, store, nil, nil, nil)
 	laptopClient := newTestLaptopClient(t, serverAddress)
 
 	req := &pb.SearchLaptopRequest{
@@ -156,10 +234,11 @@ func TestClientUploadImage(t *testing.T) {
 	imageStore := NewDiskImageStore(testImageFolder)
 
 	laptop := util.NewLaptop()
+	laptop.Owner = "vendor"
 	err := laptopStore.Save(laptop)
 	require.NoError(t, err)
 
-	serverAddress := startTestLaptopServer(t, laptopStore, imageStore, nil)
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, NewLaptopServer(laptopStore, imageStore, nil, nil, nil, nil))
 	laptopClient := newTestLaptopClient(t, serverAddress)
 
 	imagePath := fmt.Sprintf("%s/laptop.png", testImageFolder)
@@ -167,7 +246,7 @@ func TestClientUploadImage(t *testing.T) {
 	require.NoError(t, err)
 	defer file.Close()
 
-	stream, err := laptopClient.UploadImage(context.Background())
+	stream, err := laptopClient.UploadImage(newTestRoleContext(t, tokenManager, "vendor", "admin"))
 	require.NoError(t, err)
 
 	imageType := filepath.Ext(imagePath)
@@ -206,12 +285,166 @@ func TestClientUploadImage(t *testing.T) {
 	res, err := stream.CloseAndRecv()
 	require.NoError(t, err)
 	require.NotZero(t, res.GetId())
+	require.Len(t, res.GetDigest(), 64)
 
-	savedImagePath := fmt.Sprintf("%s/%s%s", testImageFolder, res.GetId(), imageType)
+	savedImagePath := fmt.Sprintf("%s/%s%s", testImageFolder, res.GetDigest(), imageType)
 	require.FileExists(t, savedImagePath)
 	require.NoError(t, os.Remove(savedImagePath))
 }
 
+func TestClientUploadImageChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	imageStore := NewDiskImageStore(t.TempDir())
+
+	laptop := util.NewLaptop()
+	laptop.Owner = "vendor"
+	err := laptopStore.Save(laptop)
+	require.NoError(t, err)
+
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, NewLaptopServer(laptopStore, imageStore, nil, nil, nil, nil))
+	laptopClient := newTestLaptopClient(t, serverAddress)
+
+	stream, err := laptopClient.UploadImage(newTestRoleContext(t, tokenManager, "vendor", "admin"))
+	require.NoError(t, err)
+
+	err = stream.Send(&pb.UploadImageRequest{
+		Data: &pb.UploadImageRequest_Info{
+			Info: &pb.ImageInfo{
+				LaptopId:  laptop.Id,
+				ImageType: ".png",
+				Checksum:  strings.Repeat("0", 64),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	err = stream.Send(&pb.UploadImageRequest{
+		Data: &pb.UploadImageRequest_ChunkData{ChunkData: []byte("corrupted image")},
+	})
+	require.NoError(t, err)
+
+	_, err = stream.CloseAndRecv()
+	require.Error(t, err)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestClientResumableUploadImage(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	imageStore := NewDiskImageStore(t.TempDir())
+	uploadStore := NewDiskUploadSessionStore(t.TempDir(), time.Hour)
+
+	laptop := util.NewLaptop()
+	laptop.Owner = "vendor"
+	err := laptopStore.Save(laptop)
+	require.NoError(t, err)
+
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, NewLaptopServer(laptopStore, imageStore, nil, nil, uploadStore, nil))
+	laptopClient := newTestLaptopClient(t, serverAddress)
+
+	data, err := os.ReadFile("../tmp/laptop.png")
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	ctx := newTestRoleContext(t, tokenManager, "vendor", "admin")
+	start, err := laptopClient.StartImageUpload(ctx, &pb.StartImageUploadRequest{
+		Info: &pb.ImageInfo{
+			LaptopId:  laptop.Id,
+			ImageType: ".png",
+			Checksum:  checksum,
+		},
+		TotalSize: uint32(len(data)),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, start.GetUploadId())
+
+	half := uint32(len(data) / 2)
+	chunk, err := laptopClient.UploadImageChunk(ctx, &pb.UploadImageChunkRequest{
+		UploadId:  start.GetUploadId(),
+		Offset:    0,
+		ChunkData: data[:half],
+	})
+	require.NoError(t, err)
+	require.Equal(t, half, chunk.GetCommittedOffset())
+
+	_, err = laptopClient.CommitImageUpload(ctx, &pb.CommitImageUploadRequest{UploadId: start.GetUploadId()})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// 이미 기록된 chunk를 다시 보내면 거부되고, 클라이언트는 offset을 조회해 이어서 보낸다
+	_, err = laptopClient.UploadImageChunk(ctx, &pb.UploadImageChunkRequest{
+		UploadId:  start.GetUploadId(),
+		Offset:    0,
+		ChunkData: data[:half],
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	upload, err := laptopClient.GetImageUpload(ctx, &pb.GetImageUploadRequest{UploadId: start.GetUploadId()})
+	require.NoError(t, err)
+	require.Equal(t, half, upload.GetCommittedOffset())
+
+	chunk, err = laptopClient.UploadImageChunk(ctx, &pb.UploadImageChunkRequest{
+		UploadId:  start.GetUploadId(),
+		Offset:    upload.GetCommittedOffset(),
+		ChunkData: data[half:],
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(len(data)), chunk.GetCommittedOffset())
+
+	res, err := laptopClient.CommitImageUpload(ctx, &pb.CommitImageUploadRequest{UploadId: start.GetUploadId()})
+	require.NoError(t, err)
+	require.Equal(t, checksum, res.GetDigest())
+	require.Equal(t, uint32(len(data)), res.GetSize())
+
+	image, err := imageStore.Find(res.GetId())
+	require.NoError(t, err)
+	require.FileExists(t, image.Path)
+
+	_, err = laptopClient.GetImageUpload(ctx, &pb.GetImageUploadRequest{UploadId: start.GetUploadId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestClientUploadImageLimits(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	laptop := util.NewLaptop()
+	laptop.Owner = "vendor"
+	err := laptopStore.Save(laptop)
+	require.NoError(t, err)
+
+	laptopServer := NewLaptopServer(laptopStore, NewDiskImageStore(t.TempDir()), nil, nil, NewDiskUploadSessionStore(t.TempDir(), time.Hour), nil)
+	laptopServer.ImageLimits = ImageLimits{MaxImages: 1, MaxTotalBytes: 100}
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, laptopServer)
+	laptopClient := newTestLaptopClient(t, serverAddress)
+
+	ctx := newTestRoleContext(t, tokenManager, "vendor", "admin")
+	info := &pb.ImageInfo{LaptopId: laptop.Id, ImageType: ".png", AltText: "front view"}
+
+	_, err = laptopClient.StartImageUpload(ctx, &pb.StartImageUploadRequest{Info: info, TotalSize: 101})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	data := []byte("tiny image")
+	start, err := laptopClient.StartImageUpload(ctx, &pb.StartImageUploadRequest{Info: info, TotalSize: uint32(len(data))})
+	require.NoError(t, err)
+	_, err = laptopClient.UploadImageChunk(ctx, &pb.UploadImageChunkRequest{UploadId: start.GetUploadId(), ChunkData: data})
+	require.NoError(t, err)
+	_, err = laptopClient.CommitImageUpload(ctx, &pb.CommitImageUploadRequest{UploadId: start.GetUploadId()})
+	require.NoError(t, err)
+
+	_, err = laptopClient.StartImageUpload(ctx, &pb.StartImageUploadRequest{Info: info, TotalSize: uint32(len(data))})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	res, err := laptopClient.ListImages(ctx, &pb.ListImagesRequest{LaptopId: laptop.Id})
+	require.NoError(t, err)
+	require.Len(t, res.GetImages(), 1)
+	require.Equal(t, "front view", res.GetImages()[0].GetAltText())
+	require.True(t, res.GetImages()[0].GetIsPrimary())
+}
+
 func TestRatingLaptop(t *testing.T) {
 	t.Parallel()
 
@@ -222,19 +455,302 @@ func TestRatingLaptop(t *testing.T) {
 	err := laptopStore.Save(laptop)
 	require.NoError(t, err)
 
-	serverAddress := startTestLaptopServer(t, laptopStore, nil, ratingStore)
+	laptopServer := NewLaptopServer(laptopStore, nil, ratingStore, nil, nil, nil)
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, laptopServer)
 	laptopClient := newTestLaptopClient(t, serverAddress)
 
-	stream, err := laptopClient.RateLaptop(t.Context())
+	aliceCtx := newTestUserContext(t, tokenManager, "alice")
+	bobCtx := newTestUserContext(t, tokenManager, "bob")
+
+	// 같은 사용자가 다시 평가하면 이전 점수를 덮어쓴다
+	rateLaptop(t, laptopClient, aliceCtx, laptop.GetId(), []float64{8, 6}, []float64{8, 6}, []uint32{1, 1})
+	rateLaptop(t, laptopClient, bobCtx, laptop.GetId(), []float64{10}, []float64{8}, []uint32{2})
+
+	res, err := laptopClient.DeleteRating(aliceCtx, &pb.DeleteRatingRequest{LaptopId: laptop.GetId()})
 	require.NoError(t, err)
+	require.Equal(t, uint32(1), res.GetRatedCount())
+	require.Equal(t, float64(10), res.GetAverageScore())
+
+	_, err = laptopClient.DeleteRating(aliceCtx, &pb.DeleteRatingRequest{LaptopId: laptop.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	stream, err := laptopClient.RateLaptop(context.Background())
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
 
-	scores := []float64{8, 7.5, 10}
-	averages := []float64{8, 7.75, 8.5}
+func TestRatingLaptopInvalidScore(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	laptop := util.NewLaptop()
+	err := laptopStore.Save(laptop)
+	require.NoError(t, err)
+
+	laptopServer := NewLaptopServer(laptopStore, nil, NewInMemoryRatingStore(), nil, nil, nil)
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, laptopServer)
+	laptopClient := newTestLaptopClient(t, serverAddress)
+
+	stream, err := laptopClient.RateLaptop(newTestUserContext(t, tokenManager, "alice"))
+	require.NoError(t, err)
+
+	reqs := []*pb.RateLaptopRequest{
+		{LaptopId: laptop.GetId(), Score: math.NaN()},
+		{LaptopId: laptop.GetId(), Score: 1e9},
+		{LaptopId: laptop.GetId(), Score: -1},
+		{LaptopId: laptop.GetId(), Score: 7.3},
+		{LaptopId: util.RandomID(), Score: 8},
+		{LaptopId: laptop.GetId(), Score: 8.5},
+	}
+	expected := []codes.Code{codes.InvalidArgument, codes.InvalidArgument, codes.InvalidArgument, codes.InvalidArgument, codes.NotFound, codes.OK}
+
+	for _, req := range reqs {
+		require.NoError(t, stream.Send(req))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	for _, code := range expected {
+		res, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, uint32(code), res.GetError().GetCode())
+	}
+
+	catalog, err := laptopServer.Catalogs.Get(DefaultOrgID)
+	require.NoError(t, err)
+	res, err := catalog.RatingStore.ListByUser("alice")
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, 8.5, res[0].Score)
+}
+
+func TestGetLaptopRating(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	rated := util.NewLaptop()
+	unrated := util.NewLaptop()
+	require.NoError(t, laptopStore.Save(rated))
+	require.NoError(t, laptopStore.Save(unrated))
+
+	laptopServer := NewLaptopServer(laptopStore, nil, NewInMemoryRatingStore(), nil, nil, nil)
+	laptopServer.RatingScale = RatingScale{Min: 1, Max: 5, Step: 1}
+	laptopServer.RatingPrior = RatingPrior{Mean: 3, Weight: 2}
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, laptopServer)
+	laptopClient := newTestLaptopClient(t, serverAddress)
+
+	rateLaptop(t, laptopClient, newTestUserContext(t, tokenManager, "alice"), rated.GetId(), []float64{5}, []float64{5}, []uint32{1})
+	rateLaptop(t, laptopClient, newTestUserContext(t, tokenManager, "bob"), rated.GetId(), []float64{5}, []float64{5}, []uint32{2})
+
+	res, err := laptopClient.GetLaptopRating(context.Background(), &pb.GetLaptopRatingRequest{LaptopId: rated.GetId()})
+	require.NoError(t, err)
+
+	rating := res.GetRating()
+	require.Equal(t, uint32(2), rating.GetRatedCount())
+	require.Equal(t, float64(5), rating.GetAverageScore())
+	require.Equal(t, float64(4), rating.GetBayesianAverage())
+	require.Len(t, rating.GetHistogram(), 5)
+	for _, bucket := range rating.GetHistogram() {
+		expected := uint32(0)
+		if bucket.GetScore() == 5 {
+			expected = 2
+		}
+		require.Equal(t, expected, bucket.GetCount())
+	}
+
+	batch, err := laptopClient.GetLaptopRatings(context.Background(), &pb.GetLaptopRatingsRequest{
+		LaptopIds: []string{unrated.GetId(), rated.GetId()},
+	})
+	require.NoError(t, err)
+	require.Len(t, batch.GetRatings(), 2)
+	require.Equal(t, unrated.GetId(), batch.GetRatings()[0].GetLaptopId())
+	require.Equal(t, uint32(0), batch.GetRatings()[0].GetRatedCount())
+	require.Equal(t, float64(3), batch.GetRatings()[0].GetBayesianAverage())
+	require.Equal(t, float64(4), batch.GetRatings()[1].GetBayesianAverage())
+
+	_, err = laptopClient.GetLaptopRatings(context.Background(), &pb.GetLaptopRatingsRequest{
+		LaptopIds: []string{rated.GetId(), util.RandomID()},
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	tooMany := make([]string, maxRatingBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = rated.GetId()
+	}
+	_, err = laptopClient.GetLaptopRatings(context.Background(), &pb.GetLaptopRatingsRequest{LaptopIds: tooMany})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTopRatedLaptops(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	ratingStore := NewInMemoryRatingStore()
+
+	laptops := make([]*pb.Laptop, 4)
+	for i := range laptops {
+		laptops[i] = util.NewLaptop()
+		laptops[i].Brand = "Apple"
+		laptops[i].Price = 1000
+	}
+	laptops[1].Brand = "Dell"
+	laptops[2].Price = 3000
+	for _, laptop := range laptops {
+		require.NoError(t, laptopStore.Save(laptop))
+	}
+
+	ratings := map[int][]float64{0: {7, 7}, 1: {9, 9}, 2: {10, 10}, 3: {10}}
+	for i, scores := range ratings {
+		for j, score := range scores {
+			_, err := ratingStore.Add(laptops[i].GetId(), fmt.Sprintf("user-%d", j), score)
+			require.NoError(t, err)
+		}
+	}
+
+	serverAddress := startTestLaptopServer(t, laptopStore, nil, ratingStore, nil)
+	laptopClient := newTestLaptopClient(t, serverAddress)
+
+	ranked := func(req *pb.TopRatedLaptopsRequest) []string {
+		res, err := laptopClient.TopRatedLaptops(context.Background(), req)
+		require.NoError(t, err)
+
+		laptopIDs := []string{}
+		for i, ranked := range res.GetLaptops() {
+			require.Equal(t, uint32(i+1), ranked.GetRank())
+			laptopIDs = append(laptopIDs, ranked.GetLaptop().GetId())
+		}
+		return laptopIDs
+	}
+
+	require.Equal(t,
+		[]string{laptops[2].GetId(), laptops[3].GetId(), laptops[1].GetId(), laptops[0].GetId()},
+		ranked(&pb.TopRatedLaptopsRequest{}),
+	)
+	require.Equal(t,
+		[]string{laptops[2].GetId(), laptops[1].GetId()},
+		ranked(&pb.TopRatedLaptopsRequest{MinRatingCount: 2, Limit: 2}),
+	)
+	require.Equal(t,
+		[]string{laptops[3].GetId(), laptops[0].GetId()},
+		ranked(&pb.TopRatedLaptopsRequest{Brand: "apple", Filter: &pb.Filter{MaxPrice: 2000, MinRam: &pb.Memory{Value: 1, Unit: pb.Memory_BIT}}}),
+	)
+}
+
+func TestLaptopReviews(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	laptop := util.NewLaptop()
+	require.NoError(t, laptopStore.Save(laptop))
+
+	laptopServer := NewLaptopServer(laptopStore, nil, NewInMemoryRatingStore(), NewInMemoryReviewStore(), nil, nil)
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, laptopServer)
+	laptopClient := newTestLaptopClient(t, serverAddress)
+
+	aliceCtx := newTestUserContext(t, tokenManager, "alice")
+	bobCtx := newTestUserContext(t, tokenManager, "bob")
+
+	created, err := laptopClient.CreateReview(aliceCtx, &pb.CreateReviewRequest{
+		LaptopId: laptop.GetId(),
+		Title:    "great screen",
+		Body:     "battery could be better",
+		Score:    8,
+	})
+	require.NoError(t, err)
+	review := created.GetReview()
+	require.Equal(t, "alice", review.GetAuthor())
+	require.Equal(t, pb.Review_PUBLISHED, review.GetState())
+
+	rating, err := laptopClient.GetLaptopRating(context.Background(), &pb.GetLaptopRatingRequest{LaptopId: laptop.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), rating.GetRating().GetRatedCount())
+
+	_, err = laptopClient.CreateReview(aliceCtx, &pb.CreateReviewRequest{LaptopId: laptop.GetId(), Title: "again", Score: 8})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = laptopClient.CreateReview(bobCtx, &pb.CreateReviewRequest{LaptopId: laptop.GetId(), Title: "bad score", Score: 7.3})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = laptopClient.CreateReview(context.Background(), &pb.CreateReviewRequest{LaptopId: laptop.GetId(), Title: "anonymous", Score: 8})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = laptopClient.CreateReview(bobCtx, &pb.CreateReviewRequest{LaptopId: laptop.GetId(), Title: "ok", Score: 5})
+	require.NoError(t, err)
+
+	voted, err := laptopClient.VoteReviewHelpful(bobCtx, &pb.VoteReviewHelpfulRequest{ReviewId: review.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), voted.GetReview().GetHelpfulCount())
+
+	_, err = laptopClient.VoteReviewHelpful(aliceCtx, &pb.VoteReviewHelpfulRequest{ReviewId: review.GetId()})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	list, err := laptopClient.ListReviews(context.Background(), &pb.ListReviewsRequest{
+		LaptopId: laptop.GetId(),
+		Sort:     pb.Review_MOST_HELPFUL,
+		PageSize: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), list.GetTotalCount())
+	require.Len(t, list.GetReviews(), 1)
+	require.Equal(t, review.GetId(), list.GetReviews()[0].GetId())
+	require.NotEmpty(t, list.GetNextPageToken())
+
+	list, err = laptopClient.ListReviews(context.Background(), &pb.ListReviewsRequest{
+		LaptopId:  laptop.GetId(),
+		Sort:      pb.Review_MOST_HELPFUL,
+		PageSize:  1,
+		PageToken: list.GetNextPageToken(),
+	})
+	require.NoError(t, err)
+	require.Len(t, list.GetReviews(), 1)
+	require.Equal(t, "bob", list.GetReviews()[0].GetAuthor())
+	require.Empty(t, list.GetNextPageToken())
+
+	_, err = laptopClient.ModerateReview(aliceCtx, &pb.ModerateReviewRequest{ReviewId: review.GetId(), State: pb.Review_HIDDEN})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	token, err := tokenManager.CreateToken(&User{Username: "admin", Role: "admin"})
+	require.NoError(t, err)
+	adminCtx := metadata.AppendToOutgoingContext(context.Background(), authorizationHeader, "Bearer "+token)
+
+	moderated, err := laptopClient.ModerateReview(adminCtx, &pb.ModerateReviewRequest{ReviewId: review.GetId(), State: pb.Review_HIDDEN})
+	require.NoError(t, err)
+	require.Equal(t, pb.Review_HIDDEN, moderated.GetReview().GetState())
+
+	// 숨긴 review의 점수는 평가에서 빠지고 다시 승인하면 돌아온다
+	rating, err = laptopClient.GetLaptopRating(context.Background(), &pb.GetLaptopRatingRequest{LaptopId: laptop.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), rating.GetRating().GetRatedCount())
+	require.Equal(t, float64(5), rating.GetRating().GetAverageScore())
+
+	_, err = laptopClient.ModerateReview(adminCtx, &pb.ModerateReviewRequest{ReviewId: review.GetId(), State: pb.Review_HIDDEN})
+	require.NoError(t, err)
+	_, err = laptopClient.ModerateReview(adminCtx, &pb.ModerateReviewRequest{ReviewId: review.GetId(), State: pb.Review_APPROVED})
+	require.NoError(t, err)
+	rating, err = laptopClient.GetLaptopRating(context.Background(), &pb.GetLaptopRatingRequest{LaptopId: laptop.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), rating.GetRating().GetRatedCount())
+	require.Equal(t, 6.5, rating.GetRating().GetAverageScore())
+
+	_, err = laptopClient.ModerateReview(adminCtx, &pb.ModerateReviewRequest{ReviewId: review.GetId(), State: pb.Review_HIDDEN})
+	require.NoError(t, err)
+
+	list, err = laptopClient.ListReviews(context.Background(), &pb.ListReviewsRequest{LaptopId: laptop.GetId()})
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), list.GetTotalCount())
+
+	_, err = laptopClient.ListReviews(context.Background(), &pb.ListReviewsRequest{LaptopId: laptop.GetId(), PageToken: "abc"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func rateLaptop(t *testing.T, laptopClient pb.LaptopServiceClient, ctx context.Context, laptopID string, scores []float64, averages []float64, counts []uint32) {
+	stream, err := laptopClient.RateLaptop(ctx)
+	require.NoError(t, err)
 
 	n := len(scores)
 	for i := 0; i < n; i++ {
 		req := &pb.RateLaptopRequest{
-			LaptopId: laptop.GetId(),
+			LaptopId: laptopID,
 			Score:    scores[i],
 		}
 
@@ -252,8 +768,64 @@ func TestRatingLaptop(t *testing.T) {
 			return
 		}
 		require.NoError(t, err)
-		require.Equal(t, res.GetLaptopId(), laptop.GetId())
+		require.Equal(t, res.GetLaptopId(), laptopID)
 		require.Equal(t, res.GetAverageScore(), averages[idx])
-		require.Equal(t, res.GetRatedCount(), uint32(idx+1))
+		require.Equal(t, res.GetRatedCount(), counts[idx])
 	}
 }
+
+func serveTestAuthLaptopServer(t *testing.T, laptopServer *LaptopServer) (string, *PasetoManager) {
+	tokenManager := NewPasetoManager(TokenKey, TokenDuration)
+	policy, err := ParsePolicy([]byte(`{
+		"roles": {
+			"user": {"permissions": ["rating.write", "review.write"]},
+			"admin": {"inherits": ["user"], "permissions": ["laptop.write", "review.moderate"]},
+			"superadmin": {"inherits": ["admin"]}
+		},
+		"rules": {
+			"/pcbook.LaptopService/CreateLaptop": "laptop.write",
+			"/pcbook.LaptopService/UpdateLaptop": "laptop.write",
+			"/pcbook.LaptopService/DeleteLaptop": "laptop.write",
+			"/pcbook.LaptopService/UploadImage": "laptop.write",
+			"/pcbook.LaptopService/StartImageUpload": "laptop.write",
+			"/pcbook.LaptopService/UploadImageChunk": "laptop.write",
+			"/pcbook.LaptopService/GetImageUpload": "laptop.write",
+			"/pcbook.LaptopService/CommitImageUpload": "laptop.write",
+			"/pcbook.LaptopService/ReorderImages": "laptop.write",
+			"/pcbook.LaptopService/SetPrimaryImage": "laptop.write",
+			"/pcbook.LaptopService/RateLaptop": "rating.write",
+			"/pcbook.LaptopService/DeleteRating": "rating.write",
+			"/pcbook.LaptopService/CreateReview": "review.write",
+			"/pcbook.LaptopService/VoteReviewHelpful": "review.write",
+			"/pcbook.LaptopService/ModerateReview": "review.moderate",
+			"*": "public"
+		}
+	}`))
+	require.NoError(t, err)
+	interceptor := NewAuthInterceptor(tokenManager, nil, nil, nil, policy)
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(interceptor.Unary()),
+		grpc.StreamInterceptor(interceptor.Stream()),
+	)
+	pb.RegisterLaptopServiceServer(grpcServer, laptopServer)
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	return listener.Addr().String(), tokenManager
+}
+
+func newTestUserContext(t *testing.T, tokenManager *PasetoManager, username string) context.Context {
+	return newTestRoleContext(t, tokenManager, username, "user")
+}
+
+func newTestRoleContext(t *testing.T, tokenManager *PasetoManager, username string, role string) context.Context {
+	token, err := tokenManager.CreateToken(&User{Username: username, Role: role})
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(context.Background(), authorizationHeader, "Bearer "+token)
+}
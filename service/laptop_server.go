@@ -3,10 +3,14 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/JeongWoo-Seo/pcBook/pb"
@@ -16,34 +20,88 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
-	maxImageSize = 1 << 20
+	maxImageSize    = 1 << 20
+	uploadChunkSize = 64 << 10
+
+	maxReviewTitleLength  = 100
+	maxReviewBodyLength   = 5000
+	defaultReviewPageSize = 20
+	maxReviewPageSize     = 100
+
+	defaultTopRatedLimit = 10
+	maxTopRatedLimit     = 100
+	maxRatingBatchSize   = 100
 )
 
+// LaptopServer는 호출자 organization의 Catalog에서만 laptop, 이미지, 평가, review를 읽고 쓴다
 type LaptopServer struct {
 	pb.UnimplementedLaptopServiceServer
-	LaptopStore LaptopStore
-	ImageStore  ImageStore
-	RatingStore RatingStore
+	Catalogs    *Catalogs
+	UploadStore UploadSessionStore
 	RDB         *redisutil.RedisManager
+	ImageLimits ImageLimits
+	RatingScale RatingScale
+	RatingPrior RatingPrior
+}
+
+// ImageLimits는 laptop 하나에 저장할 수 있는 이미지 수와 전체 크기를 제한한다
+type ImageLimits struct {
+	MaxImages     int
+	MaxTotalBytes int
+}
+
+var DefaultImageLimits = ImageLimits{
+	MaxImages:     10,
+	MaxTotalBytes: 10 << 20,
+}
+
+// NewLaptopServer는 주어진 store를 default organization의 catalog로 사용한다
+// 여러 organization을 받으려면 factory가 있는 Catalogs로 NewLaptopServerWithCatalogs를 사용한다
+func NewLaptopServer(laptopStore LaptopStore, imageStore ImageStore, ratingStore RatingStore, reviewStore ReviewStore, uploadStore UploadSessionStore, rm *redisutil.RedisManager) *LaptopServer {
+	catalogs := NewCatalogs(nil)
+	catalogs.Set(DefaultOrgID, NewCatalog(laptopStore, imageStore, ratingStore, reviewStore))
+	return NewLaptopServerWithCatalogs(catalogs, uploadStore, rm)
 }
 
-func NewLaptopServer(laptopStore LaptopStore, imageStore ImageStore, ratingStore RatingStore, rm *redisutil.RedisManager) *LaptopServer {
+func NewLaptopServerWithCatalogs(catalogs *Catalogs, uploadStore UploadSessionStore, rm *redisutil.RedisManager) *LaptopServer {
 	return &LaptopServer{
-		LaptopStore: laptopStore,
-		ImageStore:  imageStore,
-		RatingStore: ratingStore,
+		Catalogs:    catalogs,
+		UploadStore: uploadStore,
 		RDB:         rm,
+		ImageLimits: DefaultImageLimits,
+		RatingScale: DefaultRatingScale,
+		RatingPrior: DefaultRatingPrior,
 	}
 }
 
+// catalog는 호출자 organization의 catalog를 반환하며 인증하지 않은 호출자는 default organization을 본다
+func (s *LaptopServer) catalog(ctx context.Context) (*Catalog, error) {
+	catalog, err := s.Catalogs.Get(PrincipalFromContext(ctx).Org())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.FailedPrecondition, "%v", err))
+	}
+	return catalog, nil
+}
+
+// CreateLaptop은 요청한 사용자를 laptop의 owner로 저장한다
+// super-admin은 다른 vendor를 owner로 지정해서 만들 수 있다
 func (s *LaptopServer) CreateLaptop(ctx context.Context, req *pb.CreateLaptopRequest) (*pb.CreateLaptopResponse, error) {
 	laptop := req.GetLaptop()
 
 	log.Printf("receive create laptop request with id: %s", laptop.Id)
 
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "create laptop requires an authenticated user")
+	}
+	if principal.Role != SuperAdminRole || laptop.GetOwner() == "" {
+		laptop.Owner = principal.Username
+	}
+
 	if len(laptop.Id) > 0 {
 		_, err := uuid.Parse(laptop.Id)
 		if err != nil {
@@ -61,7 +119,12 @@ func (s *LaptopServer) CreateLaptop(ctx context.Context, req *pb.CreateLaptopReq
 		return nil, err
 	}
 
-	err := s.LaptopStore.Save(laptop)
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = catalog.LaptopStore.Save(laptop)
 	if err != nil {
 		code := codes.Internal
 		if errors.Is(err, ErrAlreadyExists) {
@@ -79,11 +142,122 @@ func (s *LaptopServer) CreateLaptop(ctx context.Context, req *pb.CreateLaptopReq
 	return res, nil
 }
 
+// UpdateLaptop은 id와 owner를 제외한 laptop 정보를 바꾼다
+// owner는 super-admin만 바꿀 수 있다
+func (s *LaptopServer) UpdateLaptop(ctx context.Context, req *pb.UpdateLaptopRequest) (*pb.UpdateLaptopResponse, error) {
+	laptop := req.GetLaptop()
+	if laptop == nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "laptop is required"))
+	}
+	log.Printf("receive update laptop request with id: %s", laptop.GetId())
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.findOwnedLaptop(ctx, catalog, laptop.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if laptop.GetOwner() == "" || PrincipalFromContext(ctx).Role != SuperAdminRole {
+		laptop.Owner = previous.GetOwner()
+	}
+	laptop.UpdatedAt = timestamppb.Now()
+
+	err = catalog.LaptopStore.Update(laptop)
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrNotFound) {
+			code = codes.NotFound
+		}
+		return nil, logErr(status.Errorf(code, "can not update laptop: %v", err))
+	}
+
+	log.Printf("updated laptop with id: %s", laptop.GetId())
+	return &pb.UpdateLaptopResponse{Laptop: laptop}, nil
+}
+
+// DeleteLaptop은 laptop과 함께 그 이미지, 평가, review를 지운다
+func (s *LaptopServer) DeleteLaptop(ctx context.Context, req *pb.DeleteLaptopRequest) (*pb.DeleteLaptopResponse, error) {
+	log.Printf("receive delete laptop request with id: %s", req.GetLaptopId())
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.findOwnedLaptop(ctx, catalog, req.GetLaptopId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = catalog.LaptopStore.Delete(req.GetLaptopId())
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrNotFound) {
+			code = codes.NotFound
+		}
+		return nil, logErr(status.Errorf(code, "can not delete laptop: %v", err))
+	}
+
+	err = catalog.deleteLaptopData(req.GetLaptopId())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not delete laptop data: %v", err))
+	}
+
+	log.Printf("deleted laptop with id: %s", req.GetLaptopId())
+	return &pb.DeleteLaptopResponse{}, nil
+}
+
+// findOwnedLaptop은 laptop을 찾고 요청한 사용자가 수정할 수 있는지 확인한다
+func (s *LaptopServer) findOwnedLaptop(ctx context.Context, catalog *Catalog, laptopID string) (*pb.Laptop, error) {
+	laptop, err := catalog.LaptopStore.Find(laptopID)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find laptop: %v", err))
+	}
+	if laptop == nil {
+		return nil, logErr(status.Errorf(codes.NotFound, "laptop %s no exist", laptopID))
+	}
+
+	err = checkLaptopOwner(ctx, laptop)
+	if err != nil {
+		return nil, err
+	}
+	return laptop, nil
+}
+
+// checkLaptopOwner는 laptop의 owner나 super-admin만 laptop을 수정할 수 있게 한다
+// owner가 없는 laptop은 super-admin만 수정할 수 있다
+func checkLaptopOwner(ctx context.Context, laptop *pb.Laptop) error {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return logErr(status.Errorf(codes.Unauthenticated, "modifying a laptop requires an authenticated user"))
+	}
+
+	if !principal.AllowsLaptop(laptop.GetId()) {
+		return logErr(status.Errorf(codes.PermissionDenied, "api key is not allowed to access laptop %s", laptop.GetId()))
+	}
+	if principal.Role == SuperAdminRole {
+		return nil
+	}
+	if laptop.GetOwner() == "" || laptop.GetOwner() != principal.Username {
+		return logErr(status.Errorf(codes.PermissionDenied, "only the owner can modify laptop %s", laptop.GetId()))
+	}
+	return nil
+}
+
 func (s *LaptopServer) SearchLaptop(req *pb.SearchLaptopRequest, stream grpc.ServerStreamingServer[pb.SearchLaptopResponse]) error {
 	filter := req.GetFilter()
 	log.Printf("receive a search laptop with filter : %v", filter)
 
-	err := s.LaptopStore.Search(stream.Context(), filter, func(laptop *pb.Laptop) error {
+	catalog, err := s.catalog(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	err = catalog.LaptopStore.Search(stream.Context(), filter, func(laptop *pb.Laptop) error {
 		res := &pb.SearchLaptopResponse{Laptop: laptop}
 
 		err := stream.Send(res)
@@ -109,18 +283,32 @@ func (s *LaptopServer) UploadImage(stream grpc.ClientStreamingServer[pb.UploadIm
 	}
 
 	laptopID := req.GetInfo().GetLaptopId()
-	imageType := req.GetInfo().GetImageType()
+	checksum := req.GetInfo().GetChecksum()
 	log.Printf("recieve an image for laptop %s ", laptopID)
 
-	laptop, err := s.LaptopStore.Find(laptopID)
+	catalog, err := s.catalog(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	laptop, err := catalog.LaptopStore.Find(laptopID)
 	if err != nil {
 		return logErr(status.Errorf(codes.Internal, "can not find laptop: %v", err))
 	}
 	if laptop == nil {
 		return logErr(status.Errorf(codes.InvalidArgument, "laptop %s no exist", laptopID))
 	}
+	if err := checkLaptopOwner(stream.Context(), laptop); err != nil {
+		return err
+	}
+
+	usedBytes, err := s.checkImageLimits(catalog, laptopID, 0)
+	if err != nil {
+		return err
+	}
 
 	imageData := bytes.Buffer{}
+	hasher := sha256.New()
 	imagesie := 0
 
 	for {
@@ -143,21 +331,21 @@ func (s *LaptopServer) UploadImage(stream grpc.ClientStreamingServer[pb.UploadIm
 		if imagesie > maxImageSize {
 			return logErr(status.Errorf(codes.InvalidArgument, "image is too large: %d > %d", imagesie, maxImageSize))
 		}
+		if usedBytes+imagesie > s.ImageLimits.MaxTotalBytes {
+			return logErr(status.Errorf(codes.ResourceExhausted, "laptop %s exceeds image storage limit of %d bytes", laptopID, s.ImageLimits.MaxTotalBytes))
+		}
 
 		_, err = imageData.Write(chunk)
 		if err != nil {
 			return logErr(status.Errorf(codes.Internal, "failed to write data: %v", err))
 		}
+		hasher.Write(chunk)
 	}
 
-	imageId, err := s.ImageStore.Save(laptopID, imageType, imageData)
+	digest := hex.EncodeToString(hasher.Sum(nil))
+	res, err := s.saveImage(catalog, toImageInfo(req.GetInfo()), checksum, digest, imageData)
 	if err != nil {
-		return logErr(status.Errorf(codes.Internal, "can not save image data to store: %v", err))
-	}
-
-	res := &pb.UploadImageResponse{
-		Id:   imageId,
-		Size: uint32(imagesie),
+		return err
 	}
 
 	err = stream.SendAndClose(res)
@@ -165,11 +353,331 @@ func (s *LaptopServer) UploadImage(stream grpc.ClientStreamingServer[pb.UploadIm
 		return logErr(status.Errorf(codes.Unknown, "failed to send res: %v", err))
 	}
 
-	log.Printf("saved image with id: %s", imageId)
 	return nil
 }
 
+func (s *LaptopServer) StartImageUpload(ctx context.Context, req *pb.StartImageUploadRequest) (*pb.StartImageUploadResponse, error) {
+	info := req.GetInfo()
+	totalSize := req.GetTotalSize()
+	log.Printf("receive start image upload request for laptop %s", info.GetLaptopId())
+
+	if totalSize == 0 || totalSize > maxImageSize {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "invalid image size: %d (max %d)", totalSize, maxImageSize))
+	}
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	laptop, err := catalog.LaptopStore.Find(info.GetLaptopId())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find laptop: %v", err))
+	}
+	if laptop == nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "laptop %s no exist", info.GetLaptopId()))
+	}
+	if err := checkLaptopOwner(ctx, laptop); err != nil {
+		return nil, err
+	}
+
+	_, err = s.checkImageLimits(catalog, info.GetLaptopId(), int(totalSize))
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.UploadStore.Create(PrincipalFromContext(ctx).Org(), toImageInfo(info), info.GetChecksum(), totalSize)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not create upload session: %v", err))
+	}
+
+	res := &pb.StartImageUploadResponse{
+		UploadId:  session.ID,
+		ChunkSize: uploadChunkSize,
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+	}
+	return res, nil
+}
+
+func (s *LaptopServer) UploadImageChunk(ctx context.Context, req *pb.UploadImageChunkRequest) (*pb.UploadImageChunkResponse, error) {
+	if err := contextError(ctx); err != nil {
+		return nil, err
+	}
+
+	_, _, err := s.findUploadSession(ctx, req.GetUploadId())
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.UploadStore.Append(req.GetUploadId(), req.GetOffset(), req.GetChunkData())
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return nil, logErr(status.Errorf(codes.NotFound, "upload %s no exist", req.GetUploadId()))
+		case errors.Is(err, ErrOffsetMismatch):
+			return nil, logErr(status.Errorf(codes.FailedPrecondition, "offset %d does not match committed offset %d", req.GetOffset(), session.Offset))
+		case errors.Is(err, ErrUploadOverflow):
+			return nil, logErr(status.Errorf(codes.InvalidArgument, "can not write chunk: %v", err))
+		default:
+			return nil, logErr(status.Errorf(codes.Internal, "can not write chunk: %v", err))
+		}
+	}
+
+	res := &pb.UploadImageChunkResponse{
+		UploadId:        session.ID,
+		CommittedOffset: session.Offset,
+	}
+	return res, nil
+}
+
+func (s *LaptopServer) GetImageUpload(ctx context.Context, req *pb.GetImageUploadRequest) (*pb.GetImageUploadResponse, error) {
+	session, _, err := s.findUploadSession(ctx, req.GetUploadId())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.GetImageUploadResponse{
+		UploadId:        session.ID,
+		CommittedOffset: session.Offset,
+		TotalSize:       session.TotalSize,
+		ExpiresAt:       timestamppb.New(session.ExpiresAt),
+	}
+	return res, nil
+}
+
+func (s *LaptopServer) CommitImageUpload(ctx context.Context, req *pb.CommitImageUploadRequest) (*pb.UploadImageResponse, error) {
+	session, catalog, err := s.findUploadSession(ctx, req.GetUploadId())
+	if err != nil {
+		return nil, err
+	}
+	if session.Offset != session.TotalSize {
+		return nil, logErr(status.Errorf(codes.FailedPrecondition, "upload is incomplete: %d/%d", session.Offset, session.TotalSize))
+	}
+
+	imageData, err := s.UploadStore.Read(session.ID)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not read upload: %v", err))
+	}
+
+	sum := sha256.Sum256(imageData.Bytes())
+	res, err := s.saveImage(catalog, session.Image, session.Checksum, hex.EncodeToString(sum[:]), imageData)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.UploadStore.Delete(session.ID)
+	if err != nil {
+		log.Printf("can not remove upload %s: %v", session.ID, err)
+	}
+
+	return res, nil
+}
+
+// findUploadSession은 upload session을 찾고 호출자 organization에 있는 그 laptop의 owner인지 확인한다
+func (s *LaptopServer) findUploadSession(ctx context.Context, uploadID string) (*UploadSession, *Catalog, error) {
+	session, err := s.UploadStore.Find(uploadID)
+	if err != nil {
+		return nil, nil, logErr(status.Errorf(codes.Internal, "can not find upload: %v", err))
+	}
+	// 다른 organization의 upload는 없는 upload처럼 보인다
+	if session == nil || session.OrgID != PrincipalFromContext(ctx).Org() {
+		return nil, nil, logErr(status.Errorf(codes.NotFound, "upload %s no exist", uploadID))
+	}
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = s.findOwnedLaptop(ctx, catalog, session.Image.LaptopID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, catalog, nil
+}
+
+func (s *LaptopServer) saveImage(catalog *Catalog, image *ImageInfo, checksum string, digest string, imageData bytes.Buffer) (*pb.UploadImageResponse, error) {
+	if len(checksum) > 0 && !strings.EqualFold(checksum, digest) {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "image checksum mismatch: expected %s, got %s", checksum, digest))
+	}
+
+	// 제한 확인부터 저장까지 같은 laptop의 다른 저장이 끼어들면 둘 다 제한을 통과할 수 있다
+	unlock := catalog.imageLocks.lock(image.LaptopID)
+	defer unlock()
+
+	size := imageData.Len()
+	_, err := s.checkImageLimits(catalog, image.LaptopID, size)
+	if err != nil {
+		return nil, err
+	}
+
+	image.Digest = digest
+	image.Size = size
+	imageId, err := catalog.ImageStore.Save(image, imageData)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not save image data to store: %v", err))
+	}
+
+	log.Printf("saved image with id: %s, digest: %s", imageId, digest)
+
+	res := &pb.UploadImageResponse{
+		Id:     imageId,
+		Size:   uint32(size),
+		Digest: digest,
+	}
+	return res, nil
+}
+
+func (s *LaptopServer) ListImages(ctx context.Context, req *pb.ListImagesRequest) (*pb.ListImagesResponse, error) {
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := catalog.ImageStore.List(req.GetLaptopId())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not list images: %v", err))
+	}
+
+	return &pb.ListImagesResponse{Images: toPbImageInfos(images)}, nil
+}
+
+func (s *LaptopServer) ReorderImages(ctx context.Context, req *pb.ReorderImagesRequest) (*pb.ReorderImagesResponse, error) {
+	log.Printf("receive reorder images request for laptop %s", req.GetLaptopId())
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.findOwnedLaptop(ctx, catalog, req.GetLaptopId())
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := catalog.ImageStore.Reorder(req.GetLaptopId(), req.GetImageIds())
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrInvalidImageOrder) {
+			code = codes.InvalidArgument
+		}
+		return nil, logErr(status.Errorf(code, "can not reorder images: %v", err))
+	}
+
+	return &pb.ReorderImagesResponse{Images: toPbImageInfos(images)}, nil
+}
+
+func (s *LaptopServer) SetPrimaryImage(ctx context.Context, req *pb.SetPrimaryImageRequest) (*pb.SetPrimaryImageResponse, error) {
+	log.Printf("receive set primary image request for laptop %s: %s", req.GetLaptopId(), req.GetImageId())
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.findOwnedLaptop(ctx, catalog, req.GetLaptopId())
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := catalog.ImageStore.SetPrimary(req.GetLaptopId(), req.GetImageId())
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrNotFound) {
+			code = codes.NotFound
+		}
+		return nil, logErr(status.Errorf(code, "can not set primary image: %v", err))
+	}
+
+	return &pb.SetPrimaryImageResponse{Images: toPbImageInfos(images)}, nil
+}
+
+func (s *LaptopServer) CollectImageGarbage(ctx context.Context, req *pb.CollectImageGarbageRequest) (*pb.CollectImageGarbageResponse, error) {
+	log.Printf("receive collect image garbage request: dry run = %t", req.GetDryRun())
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := catalog.ImageGC.Collect(req.GetDryRun())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not collect image garbage: %v", err))
+	}
+
+	res := &pb.CollectImageGarbageResponse{
+		DryRun:      report.DryRun,
+		OrphanFiles: report.OrphanFiles,
+	}
+	for _, image := range report.MissingFiles {
+		res.MissingImages = append(res.MissingImages, image.ID)
+	}
+	for _, image := range report.OrphanImages {
+		res.OrphanImages = append(res.OrphanImages, image.ID)
+	}
+
+	return res, nil
+}
+
+// checkImageLimits는 size 크기의 이미지를 추가해도 제한을 넘지 않는지 확인하고 현재 사용 중인 크기를 반환한다
+func (s *LaptopServer) checkImageLimits(catalog *Catalog, laptopID string, size int) (int, error) {
+	images, err := catalog.ImageStore.List(laptopID)
+	if err != nil {
+		return 0, logErr(status.Errorf(codes.Internal, "can not list images: %v", err))
+	}
+
+	if len(images) >= s.ImageLimits.MaxImages {
+		return 0, logErr(status.Errorf(codes.ResourceExhausted, "laptop %s already has %d images", laptopID, len(images)))
+	}
+
+	usedBytes := 0
+	for _, image := range images {
+		usedBytes += image.Size
+	}
+	if usedBytes+size > s.ImageLimits.MaxTotalBytes {
+		return 0, logErr(status.Errorf(codes.ResourceExhausted, "laptop %s exceeds image storage limit of %d bytes", laptopID, s.ImageLimits.MaxTotalBytes))
+	}
+
+	return usedBytes, nil
+}
+
+func toImageInfo(info *pb.ImageInfo) *ImageInfo {
+	return &ImageInfo{
+		LaptopID:  info.GetLaptopId(),
+		Type:      info.GetImageType(),
+		AltText:   info.GetAltText(),
+		IsPrimary: info.GetIsPrimary(),
+	}
+}
+
+func toPbImageInfos(images []*ImageInfo) []*pb.ImageInfo {
+	infos := make([]*pb.ImageInfo, 0, len(images))
+	for _, image := range images {
+		infos = append(infos, &pb.ImageInfo{
+			Id:        image.ID,
+			LaptopId:  image.LaptopID,
+			ImageType: image.Type,
+			Checksum:  image.Digest,
+			Position:  image.Position,
+			IsPrimary: image.IsPrimary,
+			AltText:   image.AltText,
+			CreatedAt: timestamppb.New(image.CreatedAt),
+		})
+	}
+	return infos
+}
+
 func (s *LaptopServer) RateLaptop(stream grpc.BidiStreamingServer[pb.RateLaptopRequest, pb.RateLaptopResponse]) error {
+	user := PrincipalFromContext(stream.Context())
+	if user == nil {
+		return logErr(status.Errorf(codes.Unauthenticated, "rating requires an authenticated user"))
+	}
+
+	catalog, err := s.catalog(stream.Context())
+	if err != nil {
+		return err
+	}
+
 	for {
 		if err := contextError(stream.Context()); err != nil {
 			return err
@@ -186,17 +694,28 @@ func (s *LaptopServer) RateLaptop(stream grpc.BidiStreamingServer[pb.RateLaptopR
 		laptopID := req.GetLaptopId()
 		score := req.GetScore()
 
-		log.Printf("recieve rate laptop request: id = %s", laptopID)
+		log.Printf("recieve rate laptop request: id = %s, user = %s", laptopID, user.Username)
 
-		found, err := s.LaptopStore.Find(laptopID)
+		// 잘못된 요청은 stream을 끊지 않고 해당 메시지에 대한 에러로 응답한다
+		if err := s.RatingScale.Validate(score); err != nil {
+			if err := sendRateError(stream, laptopID, codes.InvalidArgument, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		found, err := catalog.LaptopStore.Find(laptopID)
 		if err != nil {
 			return logErr(status.Errorf(codes.Internal, "can not find laptop: %v", err))
 		}
 		if found == nil {
-			return logErr(status.Errorf(codes.NotFound, "laptop %s no exist", laptopID))
+			if err := sendRateError(stream, laptopID, codes.NotFound, fmt.Sprintf("laptop %s no exist", laptopID)); err != nil {
+				return err
+			}
+			continue
 		}
 
-		rating, err := s.RatingStore.Add(laptopID, score)
+		rating, err := catalog.RatingStore.Add(laptopID, user.Username, score)
 		if err != nil {
 			return logErr(status.Errorf(codes.Internal, "can not add rating: %v", err))
 		}
@@ -204,7 +723,7 @@ func (s *LaptopServer) RateLaptop(stream grpc.BidiStreamingServer[pb.RateLaptopR
 		res := &pb.RateLaptopResponse{
 			LaptopId:     laptopID,
 			RatedCount:   rating.Count,
-			AverageScore: rating.sum / float64(rating.Count),
+			AverageScore: rating.Average(),
 		}
 
 		err = stream.Send(res)
@@ -215,6 +734,370 @@ func (s *LaptopServer) RateLaptop(stream grpc.BidiStreamingServer[pb.RateLaptopR
 	return nil
 }
 
+func sendRateError(stream grpc.BidiStreamingServer[pb.RateLaptopRequest, pb.RateLaptopResponse], laptopID string, code codes.Code, message string) error {
+	log.Printf("reject rating for laptop %s: %s", laptopID, message)
+
+	res := &pb.RateLaptopResponse{
+		LaptopId: laptopID,
+		Error: &pb.RateError{
+			Code:    uint32(code),
+			Message: message,
+		},
+	}
+
+	err := stream.Send(res)
+	if err != nil {
+		return logErr(status.Errorf(codes.Unknown, "can not send stream: %v", err))
+	}
+	return nil
+}
+
+// TopRatedLaptops는 RatingStore의 순위를 위에서부터 읽으면서 brand와 filter에 맞는 laptop만 고른다
+func (s *LaptopServer) TopRatedLaptops(ctx context.Context, req *pb.TopRatedLaptopsRequest) (*pb.TopRatedLaptopsResponse, error) {
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultTopRatedLimit
+	}
+	limit = min(limit, maxTopRatedLimit)
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.TopRatedLaptopsResponse{}
+	err = catalog.RatingStore.Ranking(ctx, req.GetMinRatingCount(), func(laptopID string, rating *Rating) error {
+		laptop, err := catalog.LaptopStore.Find(laptopID)
+		if err != nil {
+			return err
+		}
+		if laptop == nil {
+			return nil
+		}
+		if req.GetBrand() != "" && !strings.EqualFold(laptop.GetBrand(), req.GetBrand()) {
+			return nil
+		}
+		if req.GetFilter() != nil && !isQualified(req.GetFilter(), laptop) {
+			return nil
+		}
+
+		res.Laptops = append(res.Laptops, &pb.RankedLaptop{
+			Rank:         uint32(len(res.Laptops) + 1),
+			Laptop:       laptop,
+			RatedCount:   rating.Count,
+			AverageScore: rating.Average(),
+		})
+		if len(res.Laptops) == limit {
+			return ErrStopRanking
+		}
+		return nil
+	})
+	if err != nil {
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, logErr(status.Errorf(codes.Internal, "can not rank laptops: %v", err))
+	}
+
+	return res, nil
+}
+
+func (s *LaptopServer) DeleteRating(ctx context.Context, req *pb.DeleteRatingRequest) (*pb.DeleteRatingResponse, error) {
+	user := PrincipalFromContext(ctx)
+	if user == nil {
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "rating requires an authenticated user"))
+	}
+
+	laptopID := req.GetLaptopId()
+	log.Printf("receive delete rating request: id = %s, user = %s", laptopID, user.Username)
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rating, err := catalog.RatingStore.Delete(laptopID, user.Username)
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrNotFound) {
+			code = codes.NotFound
+		}
+		return nil, logErr(status.Errorf(code, "can not delete rating: %v", err))
+	}
+
+	res := &pb.DeleteRatingResponse{
+		LaptopId:     laptopID,
+		RatedCount:   rating.Count,
+		AverageScore: rating.Average(),
+	}
+	return res, nil
+}
+
+func (s *LaptopServer) GetLaptopRating(ctx context.Context, req *pb.GetLaptopRatingRequest) (*pb.GetLaptopRatingResponse, error) {
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rating, err := s.findLaptopRating(catalog, req.GetLaptopId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetLaptopRatingResponse{Rating: rating}, nil
+}
+
+// GetLaptopRatings는 한 번에 maxRatingBatchSize개의 laptop까지 조회한다
+func (s *LaptopServer) GetLaptopRatings(ctx context.Context, req *pb.GetLaptopRatingsRequest) (*pb.GetLaptopRatingsResponse, error) {
+	if len(req.GetLaptopIds()) > maxRatingBatchSize {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "can not get more than %d laptop ratings at once", maxRatingBatchSize))
+	}
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make([]*pb.LaptopRating, 0, len(req.GetLaptopIds()))
+	for _, laptopID := range req.GetLaptopIds() {
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+
+		rating, err := s.findLaptopRating(catalog, laptopID)
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+
+	return &pb.GetLaptopRatingsResponse{Ratings: ratings}, nil
+}
+
+// findLaptopRating은 평가가 없는 laptop도 prior 평균과 빈 histogram으로 반환한다
+func (s *LaptopServer) findLaptopRating(catalog *Catalog, laptopID string) (*pb.LaptopRating, error) {
+	laptop, err := catalog.LaptopStore.Find(laptopID)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find laptop: %v", err))
+	}
+	if laptop == nil {
+		return nil, logErr(status.Errorf(codes.NotFound, "laptop %s is not found", laptopID))
+	}
+
+	rating, err := catalog.RatingStore.Find(laptopID)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find rating: %v", err))
+	}
+	if rating == nil {
+		rating = &Rating{}
+	}
+
+	return &pb.LaptopRating{
+		LaptopId:        laptopID,
+		RatedCount:      rating.Count,
+		AverageScore:    rating.Average(),
+		BayesianAverage: rating.BayesianAverage(s.RatingPrior),
+		Histogram:       s.ratingHistogram(rating),
+	}, nil
+}
+
+// ratingHistogram은 scale의 모든 점수를 0개 구간까지 포함해 오름차순으로 반환한다
+func (s *LaptopServer) ratingHistogram(rating *Rating) []*pb.ScoreBucket {
+	counts := make(map[float64]uint32)
+	for _, score := range s.RatingScale.Buckets() {
+		counts[score] = 0
+	}
+	for score, count := range rating.Distribution {
+		counts[s.RatingScale.Snap(score)] += count
+	}
+
+	scores := make([]float64, 0, len(counts))
+	for score := range counts {
+		scores = append(scores, score)
+	}
+	sort.Float64s(scores)
+
+	histogram := make([]*pb.ScoreBucket, 0, len(scores))
+	for _, score := range scores {
+		histogram = append(histogram, &pb.ScoreBucket{
+			Score: score,
+			Count: counts[score],
+		})
+	}
+	return histogram
+}
+
+// CreateReview는 review의 점수를 작성자의 rating으로도 기록한다
+func (s *LaptopServer) CreateReview(ctx context.Context, req *pb.CreateReviewRequest) (*pb.CreateReviewResponse, error) {
+	user := PrincipalFromContext(ctx)
+	if user == nil {
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "review requires an authenticated user"))
+	}
+
+	laptopID := req.GetLaptopId()
+	log.Printf("receive create review request: id = %s, user = %s", laptopID, user.Username)
+
+	title := strings.TrimSpace(req.GetTitle())
+	if title == "" || len(title) > maxReviewTitleLength {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "review title must be 1 to %d bytes", maxReviewTitleLength))
+	}
+	if len(req.GetBody()) > maxReviewBodyLength {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "review body must be at most %d bytes", maxReviewBodyLength))
+	}
+	if err := s.RatingScale.Validate(req.GetScore()); err != nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "invalid score: %v", err))
+	}
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	laptop, err := catalog.LaptopStore.Find(laptopID)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find laptop: %v", err))
+	}
+	if laptop == nil {
+		return nil, logErr(status.Errorf(codes.NotFound, "laptop %s is not found", laptopID))
+	}
+
+	review, err := catalog.ReviewStore.Create(&Review{
+		LaptopID: laptopID,
+		Author:   user.Username,
+		Title:    title,
+		Body:     req.GetBody(),
+		Score:    req.GetScore(),
+	})
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrAlreadyExists) {
+			code = codes.AlreadyExists
+		}
+		return nil, logErr(status.Errorf(code, "can not create review: %v", err))
+	}
+
+	_, err = catalog.RatingStore.Add(laptopID, user.Username, review.Score)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not save rating: %v", err))
+	}
+
+	return &pb.CreateReviewResponse{Review: toPbReview(review)}, nil
+}
+
+func (s *LaptopServer) ListReviews(ctx context.Context, req *pb.ListReviewsRequest) (*pb.ListReviewsResponse, error) {
+	offset, err := parsePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, total, err := catalog.ReviewStore.List(req.GetLaptopId(), ReviewListOptions{
+		Sort:   req.GetSort(),
+		Offset: offset,
+		Limit:  pageSize(req.GetPageSize(), defaultReviewPageSize, maxReviewPageSize),
+	})
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not list reviews: %v", err))
+	}
+
+	res := &pb.ListReviewsResponse{
+		Reviews:    make([]*pb.Review, 0, len(reviews)),
+		TotalCount: uint32(total),
+	}
+	for _, review := range reviews {
+		res.Reviews = append(res.Reviews, toPbReview(review))
+	}
+	res.NextPageToken = nextPageToken(offset, len(reviews), total)
+
+	return res, nil
+}
+
+func (s *LaptopServer) VoteReviewHelpful(ctx context.Context, req *pb.VoteReviewHelpfulRequest) (*pb.VoteReviewHelpfulResponse, error) {
+	user := PrincipalFromContext(ctx)
+	if user == nil {
+		return nil, logErr(status.Errorf(codes.Unauthenticated, "vote requires an authenticated user"))
+	}
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := catalog.ReviewStore.Vote(req.GetReviewId(), user.Username)
+	if err != nil {
+		code := codes.Internal
+		switch {
+		case errors.Is(err, ErrNotFound):
+			code = codes.NotFound
+		case errors.Is(err, ErrAlreadyExists):
+			code = codes.AlreadyExists
+		case errors.Is(err, ErrOwnReview):
+			code = codes.PermissionDenied
+		}
+		return nil, logErr(status.Errorf(code, "can not vote review: %v", err))
+	}
+
+	return &pb.VoteReviewHelpfulResponse{Review: toPbReview(review)}, nil
+}
+
+func (s *LaptopServer) ModerateReview(ctx context.Context, req *pb.ModerateReviewRequest) (*pb.ModerateReviewResponse, error) {
+	reviewState := req.GetState()
+	if reviewState != pb.Review_HIDDEN && reviewState != pb.Review_APPROVED {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "review can only be hidden or approved"))
+	}
+	log.Printf("receive moderate review request: id = %s, status = %s", req.GetReviewId(), reviewState)
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	review, previous, err := catalog.ReviewStore.SetState(req.GetReviewId(), reviewState)
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrNotFound) {
+			code = codes.NotFound
+		}
+		return nil, logErr(status.Errorf(code, "can not moderate review: %v", err))
+	}
+
+	// 숨긴 review의 점수는 평가에서 빼고 다시 승인하면 되돌린다
+	switch {
+	case reviewState == pb.Review_HIDDEN && previous != pb.Review_HIDDEN:
+		_, err = catalog.RatingStore.Delete(review.LaptopID, review.Author)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, logErr(status.Errorf(codes.Internal, "can not delete rating: %v", err))
+		}
+	case reviewState != pb.Review_HIDDEN && previous == pb.Review_HIDDEN:
+		_, err = catalog.RatingStore.Add(review.LaptopID, review.Author, review.Score)
+		if err != nil {
+			return nil, logErr(status.Errorf(codes.Internal, "can not save rating: %v", err))
+		}
+	}
+
+	return &pb.ModerateReviewResponse{Review: toPbReview(review)}, nil
+}
+
+func toPbReview(review *Review) *pb.Review {
+	return &pb.Review{
+		Id:           review.ID,
+		LaptopId:     review.LaptopID,
+		Author:       review.Author,
+		Title:        review.Title,
+		Body:         review.Body,
+		Score:        review.Score,
+		HelpfulCount: review.Helpful,
+		State:        review.State,
+		CreatedAt:    timestamppb.New(review.CreatedAt),
+	}
+}
+
+// SendLaptopInfo는 api key의 laptop scope에 없거나 호출자 organization에 없는 laptop 정보를 받으면 stream을 끝낸다
+// heartbeat와 publish는 organization마다 나눈 redis key를 사용한다
 func (s *LaptopServer) SendLaptopInfo(
 	stream grpc.ClientStreamingServer[
 		pb.SendLaptopInfoRequest,
@@ -227,6 +1110,17 @@ func (s *LaptopServer) SendLaptopInfo(
 		return err
 	}
 
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return logErr(status.Errorf(codes.Unauthenticated, "sending laptop info requires an authenticated caller"))
+	}
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return err
+	}
+	prefix := orgRedisPrefix(principal.Org())
+
 	var totalRecieved int
 	var lastHeartbeat time.Time
 
@@ -254,9 +1148,21 @@ func (s *LaptopServer) SendLaptopInfo(
 
 		log.Printf("Received laptop info: id=%s", laptopID)
 
+		if !principal.AllowsLaptop(laptopID) {
+			return logErr(status.Errorf(codes.PermissionDenied, "%s is not allowed to report laptop %s", principal.Username, laptopID))
+		}
+
+		found, err := catalog.LaptopStore.Find(laptopID)
+		if err != nil {
+			return logErr(status.Errorf(codes.Internal, "can not find laptop: %v", err))
+		}
+		if found == nil {
+			return logErr(status.Errorf(codes.NotFound, "laptop %s is not found", laptopID))
+		}
+
 		//heartbeat는 5초에 1번만
 		if time.Since(lastHeartbeat) >= 5*time.Second {
-			if err := redisutil.UpdateLaptopHeartbeat(ctx, s.RDB, laptopID); err != nil {
+			if err := redisutil.UpdateLaptopHeartbeat(ctx, s.RDB, prefix, laptopID); err != nil {
 				if !errors.Is(err, redisutil.ErrRedisOpenCircuit) {
 					log.Printf("redis heartbeat error: %v", err)
 				}
@@ -264,7 +1170,7 @@ func (s *LaptopServer) SendLaptopInfo(
 			lastHeartbeat = time.Now()
 		}
 
-		if err := redisutil.PublishToRedis(ctx, s.RDB, laptop); err != nil {
+		if err := redisutil.PublishToRedis(ctx, s.RDB, prefix, laptop); err != nil {
 			if !errors.Is(err, redisutil.ErrRedisOpenCircuit) {
 				log.Printf("redis publish error: %v", err)
 			}
@@ -1,13 +1,20 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/JeongWoo-Seo/pcBook/pb"
 	"github.com/JeongWoo-Seo/pcBook/util"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -66,8 +73,9 @@ func TestLaptopServer(t *testing.T) {
 				Laptop: tc.laptop,
 			}
 
-			server := NewLaptopServer(tc.laptopstore, nil, nil, nil)
-			res, err := server.CreateLaptop(context.Background(), req)
+			server := NewLaptopServer(tc.laptopstore, nil, nil, nil, nil, nil)
+			ctx := contextWithPrincipal(context.Background(), &Principal{Username: "vendor", Role: "admin"})
+			res, err := server.CreateLaptop(ctx, req)
 			if tc.code == codes.OK {
 				require.NoError(t, err)
 				require.NotNil(t, res)
@@ -87,3 +95,179 @@ func TestLaptopServer(t *testing.T) {
 	}
 
 }
+
+func TestLaptopServerSaveImageLimitsConcurrent(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	laptop := util.NewLaptop()
+	require.NoError(t, laptopStore.Save(laptop))
+
+	server := NewLaptopServer(laptopStore, slowImageStore{NewDiskImageStore(t.TempDir())}, nil, nil, nil, nil)
+	server.ImageLimits = ImageLimits{MaxImages: 3, MaxTotalBytes: 1 << 20}
+	catalog, err := server.catalog(context.Background())
+	require.NoError(t, err)
+
+	// 동시에 저장해도 제한을 넘는 이미지는 저장되지 않는다
+	var wg sync.WaitGroup
+	var saved atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			image := &ImageInfo{LaptopID: laptop.GetId(), Type: ".png"}
+			_, err := server.saveImage(catalog, image, "", fmt.Sprintf("digest-%d", i), *bytes.NewBufferString("image"))
+			if err == nil {
+				saved.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	images, err := catalog.ImageStore.List(laptop.GetId())
+	require.NoError(t, err)
+	require.Len(t, images, 3)
+	require.Equal(t, int32(3), saved.Load())
+}
+
+func TestLaptopServerDeleteLaptop(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	laptop := util.NewLaptop()
+	laptop.Owner = "vendor"
+	require.NoError(t, laptopStore.Save(laptop))
+	other := util.NewLaptop()
+	require.NoError(t, laptopStore.Save(other))
+
+	ratingStore := NewInMemoryRatingStore()
+	reviewStore := NewInMemoryReviewStore()
+	server := NewLaptopServer(laptopStore, NewDiskImageStore(t.TempDir()), ratingStore, reviewStore, nil, nil)
+	catalog, err := server.catalog(context.Background())
+	require.NoError(t, err)
+
+	for _, laptopID := range []string{laptop.GetId(), other.GetId()} {
+		_, err = catalog.ImageStore.Save(&ImageInfo{LaptopID: laptopID, Type: ".png", Digest: "digest-" + laptopID}, *bytes.NewBufferString("image"))
+		require.NoError(t, err)
+		_, err = ratingStore.Add(laptopID, "reviewer", 4)
+		require.NoError(t, err)
+		_, err = reviewStore.Create(&Review{LaptopID: laptopID, Author: "reviewer", Title: "good", Score: 4})
+		require.NoError(t, err)
+	}
+
+	ctx := contextWithPrincipal(context.Background(), &Principal{Username: "vendor", Role: "admin"})
+	_, err = server.DeleteLaptop(ctx, &pb.DeleteLaptopRequest{LaptopId: laptop.GetId()})
+	require.NoError(t, err)
+
+	// 삭제된 laptop의 데이터만 지워지고 leaderboard에서도 빠진다
+	images, err := catalog.ImageStore.List(laptop.GetId())
+	require.NoError(t, err)
+	require.Empty(t, images)
+	rating, err := ratingStore.Find(laptop.GetId())
+	require.NoError(t, err)
+	require.Nil(t, rating)
+	reviews, _, err := reviewStore.List(laptop.GetId(), ReviewListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, reviews)
+
+	ranked := []string{}
+	err = ratingStore.Ranking(context.Background(), 0, func(laptopID string, rating *Rating) error {
+		ranked = append(ranked, laptopID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{other.GetId()}, ranked)
+
+	images, err = catalog.ImageStore.List(other.GetId())
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	reviews, _, err = reviewStore.List(other.GetId(), ReviewListOptions{})
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+}
+
+func TestLaptopServerUploadSessionOrg(t *testing.T) {
+	t.Parallel()
+
+	laptopStore := NewInMemoryLaptopStore()
+	laptop := util.NewLaptop()
+	laptop.Owner = "vendor"
+	require.NoError(t, laptopStore.Save(laptop))
+
+	uploadStore := NewDiskUploadSessionStore(t.TempDir(), time.Hour)
+	server := NewLaptopServer(laptopStore, NewDiskImageStore(t.TempDir()), nil, nil, uploadStore, nil)
+
+	image := &ImageInfo{LaptopID: laptop.GetId(), Type: ".png"}
+	session, err := uploadStore.Create(DefaultOrgID, image, "", 10)
+	require.NoError(t, err)
+	acmeSession, err := uploadStore.Create("acme", image, "", 10)
+	require.NoError(t, err)
+
+	ctx := contextWithPrincipal(context.Background(), &Principal{Username: "vendor", Role: "admin"})
+	_, _, err = server.findUploadSession(ctx, session.ID)
+	require.NoError(t, err)
+
+	// 다른 organization의 upload는 laptop id가 같아도 없는 upload처럼 보인다
+	_, _, err = server.findUploadSession(ctx, acmeSession.ID)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	acmeCtx := contextWithPrincipal(context.Background(), &Principal{Username: "vendor", Role: "admin", OrgID: "acme"})
+	_, _, err = server.findUploadSession(acmeCtx, session.ID)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestLaptopServerSendLaptopInfoOrg(t *testing.T) {
+	t.Parallel()
+
+	_, rm := newTestRedis(t)
+
+	laptop := util.NewLaptop()
+	laptopStore := NewInMemoryLaptopStore()
+	require.NoError(t, laptopStore.Save(laptop))
+	acmeLaptop := util.NewLaptop()
+	acmeLaptopStore := NewInMemoryLaptopStore()
+	require.NoError(t, acmeLaptopStore.Save(acmeLaptop))
+
+	catalogs := NewCatalogs(nil)
+	catalogs.Set(DefaultOrgID, NewCatalog(laptopStore, nil, nil, nil))
+	catalogs.Set("acme", NewCatalog(acmeLaptopStore, nil, nil, nil))
+	serverAddress, tokenManager := serveTestAuthLaptopServer(t, NewLaptopServerWithCatalogs(catalogs, nil, rm))
+	laptopClient := newTestLaptopClient(t, serverAddress)
+
+	token, err := tokenManager.CreateToken(&User{Username: "agent", Role: "user", OrgID: "acme"})
+	require.NoError(t, err)
+	acmeCtx := metadata.AppendToOutgoingContext(context.Background(), authorizationHeader, "Bearer "+token)
+	ctx := newTestUserContext(t, tokenManager, "agent")
+
+	send := func(ctx context.Context, laptopID string) error {
+		stream, err := laptopClient.SendLaptopInfo(ctx)
+		require.NoError(t, err)
+		err = stream.Send(&pb.SendLaptopInfoRequest{Laptop: &pb.LaptopInfo{Id: laptopID}})
+		if err != nil && err != io.EOF {
+			return err
+		}
+		_, err = stream.CloseAndRecv()
+		return err
+	}
+
+	// 다른 organization의 laptop 정보는 보낼 수 없다
+	require.Equal(t, codes.NotFound, status.Code(send(acmeCtx, laptop.GetId())))
+	require.Equal(t, codes.NotFound, status.Code(send(ctx, acmeLaptop.GetId())))
+
+	require.NoError(t, send(acmeCtx, acmeLaptop.GetId()))
+	require.NoError(t, send(ctx, laptop.GetId()))
+
+	// heartbeat는 organization마다 나뉘고 default organization은 이전과 같은 member를 쓴다
+	members, err := rm.Client.ZRevRange(context.Background(), "laptop:alive", 0, -1).Result()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"org:acme:" + acmeLaptop.GetId(), laptop.GetId()}, members)
+}
+
+// slowImageStore는 저장을 늦춰서 제한 확인과 저장 사이의 경쟁이 드러나게 한다
+type slowImageStore struct {
+	ImageStore
+}
+
+func (store slowImageStore) Save(image *ImageInfo, imageData bytes.Buffer) (string, error) {
+	time.Sleep(10 * time.Millisecond)
+	return store.ImageStore.Save(image, imageData)
+}
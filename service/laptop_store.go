@@ -10,11 +10,17 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-var ErrAlreadyExists = errors.New("record already exists")
+var (
+	ErrAlreadyExists = errors.New("record already exists")
+	ErrNotFound      = errors.New("record not found")
+	ErrStaleRecord   = errors.New("record was changed by another request")
+)
 
 type LaptopStore interface {
 	Save(laptop *pb.Laptop) error
 	Find(id string) (*pb.Laptop, error)
+	Update(laptop *pb.Laptop) error
+	Delete(id string) error
 	Search(ctx context.Context, filter *pb.Filter, found func(laptop *pb.Laptop) error) error
 }
 
@@ -57,6 +63,32 @@ func (s *inMemoryLaptopStore) Find(id string) (*pb.Laptop, error) {
 	return other, nil
 }
 
+func (s *inMemoryLaptopStore) Update(laptop *pb.Laptop) error {
+	s.mutax.Lock()
+	defer s.mutax.Unlock()
+
+	if s.data[laptop.GetId()] == nil {
+		return ErrNotFound
+	}
+
+	other := proto.Clone(laptop).(*pb.Laptop)
+	s.data[other.Id] = other
+
+	return nil
+}
+
+func (s *inMemoryLaptopStore) Delete(id string) error {
+	s.mutax.Lock()
+	defer s.mutax.Unlock()
+
+	if s.data[id] == nil {
+		return ErrNotFound
+	}
+
+	delete(s.data, id)
+	return nil
+}
+
 func (s *inMemoryLaptopStore) Search(ctx context.Context, filter *pb.Filter, found func(laptop *pb.Laptop) error) error {
 	s.mutax.Lock()
 	defer s.mutax.Unlock()
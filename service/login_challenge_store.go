@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const LoginChallengeDuration = 5 * time.Minute
+
+// LoginChallengeStore는 비밀번호를 확인했지만 2단계 인증이 남은 로그인을 기록한다
+// challenge는 한 번만 쓸 수 있으며 code가 틀리면 다시 로그인해야 한다
+type LoginChallengeStore interface {
+	Create(challenge *LoginChallenge) error
+	Take(hash string) (*LoginChallenge, error)
+}
+
+type LoginChallenge struct {
+	Hash      string
+	Username  string
+	ExpiresAt time.Time
+}
+
+type InmemoryLoginChallengeStore struct {
+	mutax      sync.RWMutex
+	challenges map[string]*LoginChallenge
+}
+
+func NewInMemoryLoginChallengeStore() *InmemoryLoginChallengeStore {
+	return &InmemoryLoginChallengeStore{
+		challenges: make(map[string]*LoginChallenge),
+	}
+}
+
+// NewLoginChallenge는 client에 보낼 challenge token과 store에 저장할 정보를 만든다
+func NewLoginChallenge(username string, duration time.Duration) (string, *LoginChallenge, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, fmt.Errorf("can not generate login challenge: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	return token, &LoginChallenge{
+		Hash:      HashLoginChallenge(token),
+		Username:  username,
+		ExpiresAt: time.Now().Add(duration),
+	}, nil
+}
+
+func HashLoginChallenge(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (store *InmemoryLoginChallengeStore) Create(challenge *LoginChallenge) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	if store.challenges[challenge.Hash] != nil {
+		return ErrAlreadyExists
+	}
+
+	other := *challenge
+	store.challenges[challenge.Hash] = &other
+	return nil
+}
+
+// Take는 challenge를 지우고 반환하며 없거나 만료되었으면 nil을 반환한다
+func (store *InmemoryLoginChallengeStore) Take(hash string) (*LoginChallenge, error) {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	challenge := store.challenges[hash]
+	if challenge == nil {
+		return nil, nil
+	}
+	delete(store.challenges, hash)
+
+	if !time.Now().Before(challenge.ExpiresAt) {
+		return nil, nil
+	}
+	return challenge, nil
+}
+
+func (store *InmemoryLoginChallengeStore) RemoveExpired() int {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	removed := 0
+	now := time.Now()
+	for hash, challenge := range store.challenges {
+		if !now.Before(challenge.ExpiresAt) {
+			delete(store.challenges, hash)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (store *InmemoryLoginChallengeStore) StartCleanup(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if removed := store.RemoveExpired(); removed > 0 {
+					log.Printf("removed %d expired login challenges", removed)
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// LoginAttemptStore는 username이나 ip별 로그인 실패 횟수와 잠금 시각을 기록한다
+// window 동안 실패가 없으면 실패 횟수는 0부터 다시 센다
+type LoginAttemptStore interface {
+	Reserve(key string, window time.Duration, delay func(failures int) time.Duration) (*LoginReservation, error)
+	Release(reservation *LoginReservation) error
+	LockedUntil(key string) (time.Time, error)
+	Reset(key string) error
+}
+
+// LoginReservation은 Reserve가 결과를 알기 전에 실패로 센 로그인 시도이다
+// Reserve는 잠겨 있지 않을 때만 시도를 세고 delay(실패 횟수)가 0보다 크면 그동안 잠근다
+// LockedUntil은 이미 잠겨 있어서 시도를 세지 않았을 때의 잠금 시각이며 Lock은 이 시도가 건 잠금이다
+type LoginReservation struct {
+	Key         string
+	Failures    int
+	LockedUntil time.Time
+	Lock        time.Time
+}
+
+// BackoffPolicy는 FreeAttempts번 실패한 뒤부터 실패할 때마다
+// BaseDelay에서 두 배씩 늘어나는 시간 동안 잠그며 MaxDelay보다 오래 잠그지는 않는다
+type BackoffPolicy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Window       time.Duration
+}
+
+var (
+	DefaultUserBackoff = BackoffPolicy{FreeAttempts: 5, BaseDelay: time.Second, MaxDelay: 15 * time.Minute, Window: time.Hour}
+	DefaultIPBackoff   = BackoffPolicy{FreeAttempts: 20, BaseDelay: time.Second, MaxDelay: 15 * time.Minute, Window: time.Hour}
+)
+
+// Delay는 failures번 실패한 뒤 잠글 시간을 반환한다
+func (policy BackoffPolicy) Delay(failures int) time.Duration {
+	if failures <= policy.FreeAttempts {
+		return 0
+	}
+
+	delay := policy.BaseDelay
+	for i := policy.FreeAttempts + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= policy.MaxDelay {
+			return policy.MaxDelay
+		}
+	}
+	return min(delay, policy.MaxDelay)
+}
+
+// LoginThrottle은 username과 client ip마다 따로 로그인 실패를 센다
+// 로그인에 성공하면 username의 실패 기록만 지우며
+// ip 기록은 다른 계정으로 로그인해서 지울 수 없도록 window가 지나야 사라진다
+type LoginThrottle struct {
+	Store LoginAttemptStore
+	User  BackoffPolicy
+	IP    BackoffPolicy
+}
+
+func NewLoginThrottle(store LoginAttemptStore) *LoginThrottle {
+	return &LoginThrottle{
+		Store: store,
+		User:  DefaultUserBackoff,
+		IP:    DefaultIPBackoff,
+	}
+}
+
+// LoginAttempt는 username과 ip에 미리 센 로그인 시도이다
+type LoginAttempt struct {
+	throttle     *LoginThrottle
+	username     string
+	reservations []*LoginReservation
+}
+
+// Attempt는 비밀번호를 확인하기 전에 시도를 실패로 미리 세고 허용된 횟수를 넘으면 결과와 상관없이 잠근다
+// 그래서 동시에 들어온 요청도 잠금이 걸리기 전에 허용된 횟수보다 많이 시도할 수 없다
+// username이나 ip가 이미 잠겨 있으면 시도를 세지 않고 남은 시간을 반환한다
+func (throttle *LoginThrottle) Attempt(username string, ip string) (*LoginAttempt, time.Duration, error) {
+	attempt := &LoginAttempt{throttle: throttle, username: username}
+	for _, key := range loginKeys(username, ip) {
+		policy := throttle.policy(key)
+		reservation, err := throttle.Store.Reserve(key, policy.Window, policy.Delay)
+		if err != nil {
+			attempt.release(attempt.reservations)
+			return nil, 0, err
+		}
+		if !reservation.LockedUntil.IsZero() {
+			attempt.release(attempt.reservations)
+			return nil, max(time.Until(reservation.LockedUntil), time.Nanosecond), nil
+		}
+		if !reservation.Lock.IsZero() {
+			log.Printf("lock login of %s until %s after %d failures", key, reservation.Lock.Format(time.RFC3339), reservation.Failures)
+		}
+		attempt.reservations = append(attempt.reservations, reservation)
+	}
+	return attempt, 0, nil
+}
+
+// Succeed는 username의 실패 기록과 잠금을 지우고 ip에는 미리 센 이 시도만 되돌린다
+// 실패한 시도는 이미 세었으므로 따로 기록하지 않는다
+func (attempt *LoginAttempt) Succeed() error {
+	err := attempt.throttle.Unlock(attempt.username)
+	if err != nil {
+		return err
+	}
+	return attempt.release(attempt.ipReservations())
+}
+
+// ReleaseIP는 username에 센 시도는 실패로 남기고 ip에 센 시도만 되돌린다
+// 비밀번호는 맞았지만 2단계 인증이 남은 로그인에 쓴다
+func (attempt *LoginAttempt) ReleaseIP() error {
+	return attempt.release(attempt.ipReservations())
+}
+
+func (attempt *LoginAttempt) ipReservations() []*LoginReservation {
+	reservations := []*LoginReservation{}
+	for _, reservation := range attempt.reservations {
+		if reservation.Key != loginUserKey(attempt.username) {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations
+}
+
+func (attempt *LoginAttempt) release(reservations []*LoginReservation) error {
+	for _, reservation := range reservations {
+		err := attempt.throttle.Store.Release(reservation)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (throttle *LoginThrottle) policy(key string) BackoffPolicy {
+	if strings.HasPrefix(key, "ip:") {
+		return throttle.IP
+	}
+	return throttle.User
+}
+
+// Unlock은 username의 실패 기록과 잠금을 지운다
+func (throttle *LoginThrottle) Unlock(username string) error {
+	return throttle.Store.Reset(loginUserKey(username))
+}
+
+func loginKeys(username string, ip string) []string {
+	keys := []string{loginUserKey(username)}
+	if ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+	return keys
+}
+
+func loginUserKey(username string) string {
+	return "user:" + username
+}
+
+// clientIP는 grpc peer 주소를 사용한다
+// rest gateway를 거친 요청은 peer가 loopback이므로 gateway가 붙인 x-forwarded-for를 믿는다
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return host
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+		addresses := strings.Split(forwarded[len(forwarded)-1], ",")
+		if last := strings.TrimSpace(addresses[len(addresses)-1]); last != "" {
+			return last
+		}
+	}
+	return host
+}
+
+type InmemoryLoginAttemptStore struct {
+	mutax    sync.RWMutex
+	attempts map[string]*loginAttempt
+}
+
+type loginAttempt struct {
+	failures    int
+	expiresAt   time.Time
+	lockedUntil time.Time
+}
+
+func NewInMemoryLoginAttemptStore() *InmemoryLoginAttemptStore {
+	return &InmemoryLoginAttemptStore{
+		attempts: make(map[string]*loginAttempt),
+	}
+}
+
+func (store *InmemoryLoginAttemptStore) Reserve(key string, window time.Duration, delay func(failures int) time.Duration) (*LoginReservation, error) {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	now := time.Now()
+	attempt := store.attempts[key]
+	if attempt == nil {
+		attempt = &loginAttempt{}
+		store.attempts[key] = attempt
+	}
+	if now.Before(attempt.lockedUntil) {
+		return &LoginReservation{Key: key, LockedUntil: attempt.lockedUntil}, nil
+	}
+	if !now.Before(attempt.expiresAt) {
+		attempt.failures = 0
+	}
+
+	attempt.failures++
+	attempt.expiresAt = now.Add(window)
+	reservation := &LoginReservation{Key: key, Failures: attempt.failures}
+	if lockDelay := delay(attempt.failures); lockDelay > 0 {
+		attempt.lockedUntil = now.Add(lockDelay)
+		reservation.Lock = attempt.lockedUntil
+	}
+	return reservation, nil
+}
+
+func (store *InmemoryLoginAttemptStore) Release(reservation *LoginReservation) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	attempt := store.attempts[reservation.Key]
+	if attempt == nil {
+		return nil
+	}
+	if attempt.failures > 0 {
+		attempt.failures--
+	}
+	if !reservation.Lock.IsZero() && attempt.lockedUntil.Equal(reservation.Lock) {
+		attempt.lockedUntil = time.Time{}
+	}
+	return nil
+}
+
+func (store *InmemoryLoginAttemptStore) LockedUntil(key string) (time.Time, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	attempt := store.attempts[key]
+	if attempt == nil {
+		return time.Time{}, nil
+	}
+	return attempt.lockedUntil, nil
+}
+
+func (store *InmemoryLoginAttemptStore) Reset(key string) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	delete(store.attempts, key)
+	return nil
+}
+
+// RemoveExpired는 window가 지나고 잠금도 풀린 기록을 지운다
+func (store *InmemoryLoginAttemptStore) RemoveExpired() int {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	removed := 0
+	now := time.Now()
+	for key, attempt := range store.attempts {
+		if !now.Before(attempt.expiresAt) && !now.Before(attempt.lockedUntil) {
+			delete(store.attempts, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (store *InmemoryLoginAttemptStore) StartCleanup(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if removed := store.RemoveExpired(); removed > 0 {
+					log.Printf("removed %d expired login attempts", removed)
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := BackoffPolicy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	require.Zero(t, policy.Delay(3))
+	require.Equal(t, time.Second, policy.Delay(4))
+	require.Equal(t, 2*time.Second, policy.Delay(5))
+	require.Equal(t, 8*time.Second, policy.Delay(7))
+	require.Equal(t, 10*time.Second, policy.Delay(8))
+	require.Equal(t, 10*time.Second, policy.Delay(1000))
+}
+
+func TestLoginThrottle(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryLoginAttemptStore()
+	throttle := NewLoginThrottle(store)
+	throttle.User = BackoffPolicy{FreeAttempts: 5, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+	throttle.IP = BackoffPolicy{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+
+	// ip 제한은 여러 username에 걸쳐 센다
+	for _, username := range []string{"alice", "bob", "carol"} {
+		_, retryAfter, err := throttle.Attempt(username, "10.0.0.1")
+		require.NoError(t, err)
+		require.Zero(t, retryAfter)
+	}
+
+	_, retryAfter, err := throttle.Attempt("dave", "10.0.0.1")
+	require.NoError(t, err)
+	require.Greater(t, retryAfter, 59*time.Second)
+
+	// 잠긴 ip로 한 시도는 username에 세지 않는다
+	reservation, err := store.Reserve("user:dave", time.Hour, throttle.User.Delay)
+	require.NoError(t, err)
+	require.Equal(t, 1, reservation.Failures)
+
+	_, retryAfter, err = throttle.Attempt("dave", "10.0.0.2")
+	require.NoError(t, err)
+	require.Zero(t, retryAfter)
+
+	// username을 unlock해도 ip 잠금은 남는다
+	require.NoError(t, throttle.Unlock("carol"))
+	_, retryAfter, err = throttle.Attempt("carol", "10.0.0.1")
+	require.NoError(t, err)
+	require.Greater(t, retryAfter, time.Duration(0))
+
+	// 성공한 시도는 ip에 세지 않으며 그 시도가 건 잠금도 푼다
+	for i := 0; i < 3; i++ {
+		attempt, retryAfter, err := throttle.Attempt("erin", "10.0.0.3")
+		require.NoError(t, err)
+		require.Zero(t, retryAfter)
+		require.NoError(t, attempt.Succeed())
+	}
+	until, err := store.LockedUntil("ip:10.0.0.3")
+	require.NoError(t, err)
+	require.True(t, until.IsZero())
+
+	// window가 지난 실패는 다시 센다
+	noDelay := func(int) time.Duration { return 0 }
+	reservation, err = store.Reserve("user:frank", time.Millisecond, noDelay)
+	require.NoError(t, err)
+	require.Equal(t, 1, reservation.Failures)
+	time.Sleep(5 * time.Millisecond)
+	reservation, err = store.Reserve("user:frank", time.Millisecond, noDelay)
+	require.NoError(t, err)
+	require.Equal(t, 1, reservation.Failures)
+
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(t, 1, store.RemoveExpired())
+}
+
+func TestLoginThrottleConcurrent(t *testing.T) {
+	t.Parallel()
+
+	throttle := NewLoginThrottle(NewInMemoryLoginAttemptStore())
+	throttle.User = BackoffPolicy{FreeAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+
+	// 동시에 들어온 시도도 잠금 전에 허용된 횟수를 넘지 않는다
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, retryAfter, err := throttle.Attempt("alice", "")
+			require.NoError(t, err)
+			if retryAfter == 0 {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(throttle.User.FreeAttempts+1), allowed.Load())
+}
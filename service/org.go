@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultOrgID는 organization이 지정되지 않은 사용자와 인증하지 않은 호출자의 organization이다
+const DefaultOrgID = "default"
+
+var (
+	ErrInvalidOrgID = errors.New("org id must be 2-32 lowercase letters, digits or '-'")
+	ErrOrgDeleted   = errors.New("org id was used by a deleted organization")
+
+	// org id는 redis key와 이미지 폴더 이름에 쓰이므로 '.', ':', '/'를 허용하지 않는다
+	orgIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,31}$`)
+)
+
+func NormalizeOrgID(orgID string) (string, error) {
+	orgID = strings.ToLower(strings.TrimSpace(orgID))
+	if !orgIDPattern.MatchString(orgID) {
+		return "", ErrInvalidOrgID
+	}
+	return orgID, nil
+}
+
+// orgOrDefault는 organization이 없던 이전 데이터를 default organization으로 본다
+func orgOrDefault(orgID string) string {
+	if orgID == "" {
+		return DefaultOrgID
+	}
+	return orgID
+}
+
+// orgRedisPrefix는 organization의 redis key 앞에 붙일 prefix다
+// default organization은 organization이 생기기 전과 같은 key를 사용한다
+func orgRedisPrefix(orgID string) string {
+	if orgOrDefault(orgID) == DefaultOrgID {
+		return ""
+	}
+	return "org:" + orgID + ":"
+}
+
+type OrgStore interface {
+	Create(org *Org) error
+	Find(id string) (*Org, error)
+	List() ([]*Org, error)
+	Delete(id string) error
+}
+
+type Org struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+}
+
+// 삭제된 organization의 평가와 이미지는 org id를 prefix로 남아 있으므로
+// 삭제된 org id는 deleted에 기록해 두고 다시 만들 수 없게 한다
+type InmemoryOrgStore struct {
+	mutax   sync.RWMutex
+	orgs    map[string]*Org
+	deleted map[string]bool
+}
+
+// NewInMemoryOrgStore는 default organization이 있는 store를 만든다
+func NewInMemoryOrgStore() *InmemoryOrgStore {
+	return &InmemoryOrgStore{
+		orgs: map[string]*Org{
+			DefaultOrgID: {ID: DefaultOrgID, Name: "Default", CreatedAt: time.Now()},
+		},
+		deleted: make(map[string]bool),
+	}
+}
+
+func (store *InmemoryOrgStore) Create(org *Org) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	if store.orgs[org.ID] != nil {
+		return ErrAlreadyExists
+	}
+	if store.deleted[org.ID] {
+		return ErrOrgDeleted
+	}
+
+	other := org.Clone()
+	other.CreatedAt = time.Now()
+	store.orgs[org.ID] = other
+	return nil
+}
+
+func (store *InmemoryOrgStore) Find(id string) (*Org, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	org := store.orgs[id]
+	if org == nil {
+		return nil, nil
+	}
+	return org.Clone(), nil
+}
+
+// List는 id 순으로 정렬해서 반환한다
+func (store *InmemoryOrgStore) List() ([]*Org, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	orgs := make([]*Org, 0, len(store.orgs))
+	for _, org := range store.orgs {
+		orgs = append(orgs, org.Clone())
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].ID < orgs[j].ID
+	})
+	return orgs, nil
+}
+
+func (store *InmemoryOrgStore) Delete(id string) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	if store.orgs[id] == nil {
+		return ErrNotFound
+	}
+	delete(store.orgs, id)
+	store.deleted[id] = true
+	return nil
+}
+
+func (org *Org) Clone() *Org {
+	other := *org
+	return &other
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// OrgServer의 rpc는 super-admin만 호출할 수 있다
+// TokenDuration은 SetUserOrg가 access token 폐기 기록을 유지할 기간이다
+type OrgServer struct {
+	pb.UnimplementedOrgServiceServer
+	OrgStore      OrgStore
+	UserStore     UserStore
+	Catalogs      *Catalogs
+	RefreshTokens RefreshTokenStore
+	Revocations   RevocationStore
+	APIKeys       APIKeyStore
+	TokenDuration time.Duration
+}
+
+func NewOrgServer(orgStore OrgStore, userStore UserStore, catalogs *Catalogs, refreshTokens RefreshTokenStore, revocations RevocationStore, apiKeys APIKeyStore) *OrgServer {
+	return &OrgServer{
+		OrgStore:      orgStore,
+		UserStore:     userStore,
+		Catalogs:      catalogs,
+		RefreshTokens: refreshTokens,
+		Revocations:   revocations,
+		APIKeys:       apiKeys,
+		TokenDuration: TokenDuration,
+	}
+}
+
+// CreateOrg는 이름이 없으면 id를 이름으로 사용한다
+func (server *OrgServer) CreateOrg(ctx context.Context, req *pb.CreateOrgRequest) (*pb.CreateOrgResponse, error) {
+	orgID, err := NormalizeOrgID(req.GetOrgId())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+
+	name := strings.TrimSpace(req.GetName())
+	if name == "" {
+		name = orgID
+	}
+
+	err = server.OrgStore.Create(&Org{ID: orgID, Name: name})
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrAlreadyExists) {
+			code = codes.AlreadyExists
+		} else if errors.Is(err, ErrOrgDeleted) {
+			code = codes.FailedPrecondition
+		}
+		return nil, logErr(status.Errorf(code, "can not create organization: %v", err))
+	}
+
+	org, err := server.findOrg(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("created organization: %s", orgID)
+	return &pb.CreateOrgResponse{Org: toPbOrg(org)}, nil
+}
+
+func (server *OrgServer) ListOrgs(ctx context.Context, req *pb.ListOrgsRequest) (*pb.ListOrgsResponse, error) {
+	orgs, err := server.OrgStore.List()
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not list organizations: %v", err))
+	}
+
+	res := &pb.ListOrgsResponse{Orgs: make([]*pb.Org, 0, len(orgs))}
+	for _, org := range orgs {
+		res.Orgs = append(res.Orgs, toPbOrg(org))
+	}
+	return res, nil
+}
+
+// DeleteOrg는 사용자가 남아 있지 않은 organization만 삭제하고 그 api key를 폐기한 뒤 catalog를 버린다
+// 삭제된 organization에 연결된 client 인증서는 AuthInterceptor가 거부한다
+// default organization은 삭제할 수 없다
+func (server *OrgServer) DeleteOrg(ctx context.Context, req *pb.DeleteOrgRequest) (*pb.DeleteOrgResponse, error) {
+	org, err := server.findOrg(req.GetOrgId())
+	if err != nil {
+		return nil, err
+	}
+	if org.ID == DefaultOrgID {
+		return nil, logErr(status.Errorf(codes.FailedPrecondition, "can not delete the default organization"))
+	}
+
+	_, total, err := server.UserStore.List(org.ID, 0, 1)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not list users: %v", err))
+	}
+	if total > 0 {
+		return nil, logErr(status.Errorf(codes.FailedPrecondition, "organization %s still has %d users", org.ID, total))
+	}
+
+	err = server.revokeAPIKeys(org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.OrgStore.Delete(org.ID)
+	if err != nil {
+		return nil, logErr(userStoreError(err, "can not delete organization"))
+	}
+	server.Catalogs.Remove(org.ID)
+
+	log.Printf("deleted organization: %s", org.ID)
+	return &pb.DeleteOrgResponse{}, nil
+}
+
+// revokeAPIKeys는 organization의 관리자가 만든 api key를 모두 폐기한다
+func (server *OrgServer) revokeAPIKeys(orgID string) error {
+	if server.APIKeys == nil {
+		return nil
+	}
+
+	keys, err := server.APIKeys.List()
+	if err != nil {
+		return logErr(status.Errorf(codes.Internal, "can not list api keys: %v", err))
+	}
+	for _, key := range keys {
+		if key.Revoked || orgOrDefault(key.OrgID) != orgID {
+			continue
+		}
+		_, err = server.APIKeys.Revoke(key.ID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return logErr(status.Errorf(codes.Internal, "can not revoke api key %s: %v", key.ID, err))
+		}
+		log.Printf("revoked api key %s of deleted organization %s", key.ID, orgID)
+	}
+	return nil
+}
+
+// SetUserOrg는 사용자를 다른 organization으로 옮기고 세션을 모두 폐기한다
+// 이전 token의 org claim으로 새 organization의 catalog를 볼 수 없도록 다시 로그인해야 한다
+func (server *OrgServer) SetUserOrg(ctx context.Context, req *pb.SetUserOrgRequest) (*pb.SetUserOrgResponse, error) {
+	username, err := NormalizeUsername(req.GetUsername())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+
+	org, err := server.findOrg(req.GetOrgId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := server.UserStore.Find(username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	if user == nil {
+		return nil, logErr(status.Errorf(codes.NotFound, "user %s is not found", username))
+	}
+
+	if user.Org() != org.ID {
+		user.OrgID = org.ID
+		err = server.UserStore.Update(user)
+		if err != nil {
+			return nil, logErr(userStoreError(err, "can not update user"))
+		}
+
+		err = revokeSessions(server.Revocations, server.RefreshTokens, username, server.TokenDuration)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("moved user %s to organization %s", username, org.ID)
+	}
+
+	user, err = server.UserStore.Find(username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	if user == nil {
+		return nil, logErr(status.Errorf(codes.NotFound, "user %s is not found", username))
+	}
+	return &pb.SetUserOrgResponse{User: toPbUserInfo(user)}, nil
+}
+
+func (server *OrgServer) findOrg(orgID string) (*Org, error) {
+	orgID, err := NormalizeOrgID(orgID)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+
+	org, err := server.OrgStore.Find(orgID)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find organization: %v", err))
+	}
+	if org == nil {
+		return nil, logErr(status.Errorf(codes.NotFound, "organization %s is not found", orgID))
+	}
+	return org, nil
+}
+
+func toPbOrg(org *Org) *pb.Org {
+	return &pb.Org{
+		Id:        org.ID,
+		Name:      org.Name,
+		CreatedAt: timestamppb.New(org.CreatedAt),
+	}
+}
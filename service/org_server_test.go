@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"github.com/JeongWoo-Seo/pcBook/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestOrgServer(t *testing.T) {
+	t.Parallel()
+
+	userStore := NewInMemoryUserStore()
+	for _, user := range []struct{ username, role string }{{"acme-admin", "admin"}, {"globex-admin", "admin"}, {"root", SuperAdminRole}} {
+		newUser, err := NewUser(user.username, "secret-password", user.role)
+		require.NoError(t, err)
+		require.NoError(t, userStore.Save(newUser))
+	}
+
+	tokenManager := NewPasetoManager(TokenKey, TokenDuration)
+	refreshTokens := NewInMemoryRefreshTokenStore()
+	revocations := NewInMemoryRevocationStore()
+	apiKeys := NewInMemoryAPIKeyStore()
+	policy, err := ParsePolicy([]byte(`{
+		"roles": {
+			"admin": {"permissions": ["laptop.write", "user.admin"]},
+			"superadmin": {"inherits": ["admin"], "permissions": ["org.admin"]}
+		},
+		"rules": {
+			"/pcbook.AuthService/*": "public",
+			"/pcbook.UserAdminService/*": "user.admin",
+			"/pcbook.OrgService/*": "org.admin",
+			"/pcbook.LaptopService/CreateLaptop": "laptop.write",
+			"/pcbook.LaptopService/DeleteLaptop": "laptop.write",
+			"/pcbook.LaptopService/GetLaptopRating": "public"
+		}
+	}`))
+	require.NoError(t, err)
+	identities, err := ParseCertIdentities([]byte(`{
+		"identities": {"cn:acme-agent": {"username": "acme-agent", "role": "admin", "org_id": "acme"}}
+	}`))
+	require.NoError(t, err)
+
+	orgStore := NewInMemoryOrgStore()
+	interceptor := NewAuthInterceptor(tokenManager, userStore, revocations, apiKeys, policy)
+	interceptor.SetCertIdentities(identities)
+	interceptor.SetOrgStore(orgStore)
+	catalogs := NewCatalogs(func(ctx context.Context, orgID string) (*Catalog, error) {
+		return NewCatalog(NewInMemoryLaptopStore(), nil, NewInMemoryRatingStore(), NewInMemoryReviewStore()), nil
+	})
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(interceptor.Unary()))
+	pb.RegisterAuthServiceServer(grpcServer, NewAuthServer(userStore, tokenManager, refreshTokens, revocations))
+	pb.RegisterUserAdminServiceServer(grpcServer, NewUserAdminServer(userStore, refreshTokens, revocations, apiKeys))
+	pb.RegisterOrgServiceServer(grpcServer, NewOrgServer(orgStore, userStore, catalogs, refreshTokens, revocations, apiKeys))
+	pb.RegisterLaptopServiceServer(grpcServer, NewLaptopServerWithCatalogs(catalogs, nil, nil))
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	authClient := pb.NewAuthServiceClient(conn)
+	adminClient := pb.NewUserAdminServiceClient(conn)
+	orgClient := pb.NewOrgServiceClient(conn)
+	laptopClient := pb.NewLaptopServiceClient(conn)
+
+	login := func(username string) context.Context {
+		res, err := authClient.Login(context.Background(), &pb.LoginRequest{Username: username, Password: "secret-password"})
+		require.NoError(t, err)
+		return metadata.AppendToOutgoingContext(context.Background(), authorizationHeader, "Bearer "+res.GetAccessToken())
+	}
+	rootCtx := login("root")
+	oldAcmeCtx := login("acme-admin")
+
+	_, err = orgClient.CreateOrg(oldAcmeCtx, &pb.CreateOrgRequest{OrgId: "acme"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	created, err := orgClient.CreateOrg(rootCtx, &pb.CreateOrgRequest{OrgId: " ACME ", Name: "Acme Corp"})
+	require.NoError(t, err)
+	require.Equal(t, "acme", created.GetOrg().GetId())
+	require.Equal(t, "Acme Corp", created.GetOrg().GetName())
+
+	_, err = orgClient.CreateOrg(rootCtx, &pb.CreateOrgRequest{OrgId: "acme"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = orgClient.CreateOrg(rootCtx, &pb.CreateOrgRequest{OrgId: "org:acme"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = orgClient.CreateOrg(rootCtx, &pb.CreateOrgRequest{OrgId: "globex"})
+	require.NoError(t, err)
+
+	orgs, err := orgClient.ListOrgs(rootCtx, &pb.ListOrgsRequest{})
+	require.NoError(t, err)
+	require.Len(t, orgs.GetOrgs(), 3)
+	require.Equal(t, DefaultOrgID, orgs.GetOrgs()[1].GetId())
+
+	// 옮겨진 사용자의 이전 token은 폐기되어 이전 organization을 볼 수 없다
+	moved, err := orgClient.SetUserOrg(rootCtx, &pb.SetUserOrgRequest{Username: "acme-admin", OrgId: "acme"})
+	require.NoError(t, err)
+	require.Equal(t, "acme", moved.GetUser().GetOrgId())
+	_, err = adminClient.ListUsers(oldAcmeCtx, &pb.ListUsersRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = orgClient.SetUserOrg(rootCtx, &pb.SetUserOrgRequest{Username: "globex-admin", OrgId: "globex"})
+	require.NoError(t, err)
+	_, err = orgClient.SetUserOrg(rootCtx, &pb.SetUserOrgRequest{Username: "globex-admin", OrgId: "initech"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// token 발급 시각은 초 단위이므로 폐기한 시각과 다른 초에 다시 로그인한다
+	time.Sleep(time.Second)
+	acmeCtx := login("acme-admin")
+	globexCtx := login("globex-admin")
+
+	// 다른 organization의 laptop은 없는 laptop처럼 보인다
+	laptop, err := laptopClient.CreateLaptop(acmeCtx, &pb.CreateLaptopRequest{Laptop: util.NewLaptop()})
+	require.NoError(t, err)
+
+	_, err = laptopClient.GetLaptopRating(acmeCtx, &pb.GetLaptopRatingRequest{LaptopId: laptop.GetId()})
+	require.NoError(t, err)
+	_, err = laptopClient.GetLaptopRating(globexCtx, &pb.GetLaptopRatingRequest{LaptopId: laptop.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = laptopClient.GetLaptopRating(context.Background(), &pb.GetLaptopRatingRequest{LaptopId: laptop.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = laptopClient.GetLaptopRating(rootCtx, &pb.GetLaptopRatingRequest{LaptopId: laptop.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = laptopClient.DeleteLaptop(globexCtx, &pb.DeleteLaptopRequest{LaptopId: laptop.GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// 관리자는 자기 organization의 사용자만 다룰 수 있다
+	users, err := adminClient.ListUsers(acmeCtx, &pb.ListUsersRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), users.GetTotalCount())
+	require.Equal(t, "acme-admin", users.GetUsers()[0].GetUsername())
+
+	_, err = adminClient.GetUser(acmeCtx, &pb.GetUserRequest{Username: "globex-admin"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = adminClient.DisableUser(acmeCtx, &pb.DisableUserRequest{Username: "globex-admin"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	users, err = adminClient.ListUsers(rootCtx, &pb.ListUsersRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), users.GetTotalCount())
+
+	key, err := adminClient.CreateApiKey(acmeCtx, &pb.CreateApiKeyRequest{Name: "agent", Methods: []string{"/pcbook.LaptopService/SendLaptopInfo"}})
+	require.NoError(t, err)
+	keys, err := adminClient.ListApiKeys(globexCtx, &pb.ListApiKeysRequest{})
+	require.NoError(t, err)
+	require.Empty(t, keys.GetApiKeys())
+	_, err = adminClient.RevokeApiKey(globexCtx, &pb.RevokeApiKeyRequest{Id: key.GetApiKey().GetId()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	keyCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, "ApiKey "+key.GetKey()))
+	_, err = interceptor.authorizeHeader(keyCtx, "/pcbook.LaptopService/SendLaptopInfo")
+	require.NoError(t, err)
+	certCtx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{newTestClientCert("acme-agent", "")}},
+	}}})
+	_, err = interceptor.authorizeCert(certCtx)
+	require.NoError(t, err)
+
+	_, err = orgClient.DeleteOrg(rootCtx, &pb.DeleteOrgRequest{OrgId: DefaultOrgID})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = orgClient.DeleteOrg(rootCtx, &pb.DeleteOrgRequest{OrgId: "acme"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = orgClient.SetUserOrg(rootCtx, &pb.SetUserOrgRequest{Username: "acme-admin", OrgId: DefaultOrgID})
+	require.NoError(t, err)
+	_, err = orgClient.DeleteOrg(rootCtx, &pb.DeleteOrgRequest{OrgId: "acme"})
+	require.NoError(t, err)
+	_, err = orgClient.DeleteOrg(rootCtx, &pb.DeleteOrgRequest{OrgId: "acme"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// 삭제된 organization의 api key와 client 인증서는 거부되고 org id는 다시 만들 수 없다
+	_, err = interceptor.authorizeHeader(keyCtx, "/pcbook.LaptopService/SendLaptopInfo")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = interceptor.authorizeCert(certCtx)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = orgClient.CreateOrg(rootCtx, &pb.CreateOrgRequest{OrgId: "acme"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = interceptor.authorizeHeader(keyCtx, "/pcbook.LaptopService/SendLaptopInfo")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
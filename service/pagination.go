@@ -0,0 +1,35 @@
+package service
+
+import (
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// page token은 다음 page가 시작하는 offset이다
+func parsePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	offset, err := strconv.Atoi(token)
+	if err != nil || offset < 0 {
+		return 0, logErr(status.Errorf(codes.InvalidArgument, "invalid page token: %s", token))
+	}
+	return offset, nil
+}
+
+func nextPageToken(offset int, count int, total int) string {
+	if next := offset + count; next < total {
+		return strconv.Itoa(next)
+	}
+	return ""
+}
+
+func pageSize(size uint32, defaultSize int, maxSize int) int {
+	if size == 0 {
+		return defaultSize
+	}
+	return min(int(size), maxSize)
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// v2.local은 32 byte 대칭키를, v2.public은 32 byte Ed25519 seed를 Secret으로 사용한다
+const pasetoKeySize = 32
+
+const (
+	PurposeLocal  = "v2.local"
+	PurposePublic = "v2.public"
+)
+
+var ErrUnknownKey = errors.New("unknown or expired token key")
+
+// PasetoKey는 kid로 구분되는 서명 키이다
+// ExpiresAt이 지난 키로 발급된 token은 더 이상 검증하지 않으며 zero value이면 만료되지 않는다
+// 다른 서비스가 v2.public token을 검증할 때는 Secret 없이 PublicKey만 가진다
+type PasetoKey struct {
+	ID        string
+	Secret    []byte
+	PublicKey ed25519.PublicKey
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// PasetoKeySet의 ActiveKeyID 키로 새 token을 발급하고
+// 만료되지 않은 나머지 키는 이전에 발급된 token을 검증하는 데만 사용한다
+type PasetoKeySet struct {
+	Purpose     string
+	ActiveKeyID string
+	Keys        []*PasetoKey
+}
+
+type pasetoKeyJSON struct {
+	ID        string `json:"id"`
+	Secret    string `json:"secret"`
+	CreatedAt string `json:"created_at,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+type pasetoKeySetJSON struct {
+	Purpose     string           `json:"purpose,omitempty"`
+	ActiveKeyID string           `json:"active_key_id"`
+	Keys        []*pasetoKeyJSON `json:"keys"`
+}
+
+// GeneratePasetoKey는 임의의 kid와 키를 만든다
+func GeneratePasetoKey() (*PasetoKey, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("can not generate key id: %w", err)
+	}
+
+	secret := make([]byte, pasetoKeySize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("can not generate key: %w", err)
+	}
+
+	return &PasetoKey{
+		ID:        hex.EncodeToString(id),
+		Secret:    secret,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}, nil
+}
+
+// NewPasetoKeySet은 키 하나로 이루어진 key set을 만든다
+func NewPasetoKeySet(purpose string, key *PasetoKey) *PasetoKeySet {
+	return &PasetoKeySet{Purpose: purpose, ActiveKeyID: key.ID, Keys: []*PasetoKey{key}}
+}
+
+// LoadPasetoKeys는 key set JSON 파일을 읽는다
+func LoadPasetoKeys(path string) (*PasetoKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not read token key file: %w", err)
+	}
+	return ParsePasetoKeys(data)
+}
+
+func ParsePasetoKeys(data []byte) (*PasetoKeySet, error) {
+	var file pasetoKeySetJSON
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("can not parse token keys: %w", err)
+	}
+
+	set := &PasetoKeySet{Purpose: file.Purpose, ActiveKeyID: file.ActiveKeyID}
+	if set.Purpose == "" {
+		set.Purpose = PurposeLocal
+	}
+	for _, entry := range file.Keys {
+		key := &PasetoKey{ID: entry.ID}
+
+		secret, err := hex.DecodeString(entry.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret of key %s: %w", entry.ID, err)
+		}
+		key.Secret = secret
+
+		if key.CreatedAt, err = parseKeyTime(entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("invalid created_at of key %s: %w", entry.ID, err)
+		}
+		if key.ExpiresAt, err = parseKeyTime(entry.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("invalid expires_at of key %s: %w", entry.ID, err)
+		}
+
+		set.Keys = append(set.Keys, key)
+	}
+
+	if err := set.Check(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// Check는 kid가 겹치지 않고 키 크기가 맞으며 active 키가 만료되지 않았는지 확인한다
+func (set *PasetoKeySet) Check() error {
+	if set.Purpose != PurposeLocal && set.Purpose != PurposePublic {
+		return fmt.Errorf("unknown token purpose: %s", set.Purpose)
+	}
+
+	ids := make(map[string]bool, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.ID == "" {
+			return fmt.Errorf("token key id must not be empty")
+		}
+		if ids[key.ID] {
+			return fmt.Errorf("duplicate token key id: %s", key.ID)
+		}
+		ids[key.ID] = true
+
+		if len(key.Secret) != pasetoKeySize {
+			return fmt.Errorf("token key %s must be %d bytes", key.ID, pasetoKeySize)
+		}
+	}
+
+	active := set.Find(set.ActiveKeyID)
+	if active == nil {
+		return fmt.Errorf("active token key %q is not in the key set", set.ActiveKeyID)
+	}
+	if active.Expired(time.Now()) {
+		return fmt.Errorf("active token key %s is expired", active.ID)
+	}
+	return nil
+}
+
+func (set *PasetoKeySet) Find(id string) *PasetoKey {
+	for _, key := range set.Keys {
+		if key.ID == id {
+			return key
+		}
+	}
+	return nil
+}
+
+func (set *PasetoKeySet) Active() *PasetoKey {
+	return set.Find(set.ActiveKeyID)
+}
+
+// Publish는 새 키를 검증에만 쓰는 키로 추가하고 이미 만료된 키는 key set에서 제거한다
+// 모든 서버가 새 key set을 읽은 뒤에 Activate해야 새 키로 발급된 token을 어느 서버에서나 검증할 수 있다
+func (set *PasetoKeySet) Publish() (*PasetoKey, error) {
+	key, err := GeneratePasetoKey()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	keys := []*PasetoKey{key}
+	for _, other := range set.Keys {
+		if other.Expired(now) {
+			continue
+		}
+		keys = append(keys, other)
+	}
+
+	set.Keys = keys
+	return key, nil
+}
+
+// Activate는 Publish한 키를 active로 만들고 이전 active 키는 grace 후에 만료시킨다
+// grace는 적어도 access token 유효 기간보다 길어야 로그인한 사용자가 로그아웃되지 않는다
+func (set *PasetoKeySet) Activate(id string, grace time.Duration) error {
+	now := time.Now()
+	key := set.Find(id)
+	if key == nil {
+		return fmt.Errorf("token key %s is not published", id)
+	}
+	if key.Expired(now) {
+		return fmt.Errorf("token key %s is expired", id)
+	}
+	if id == set.ActiveKeyID {
+		return fmt.Errorf("token key %s is already active", id)
+	}
+
+	previous := set.Active()
+	if previous != nil && (previous.ExpiresAt.IsZero() || previous.ExpiresAt.After(now.Add(grace))) {
+		previous.ExpiresAt = now.Add(grace).UTC().Truncate(time.Second)
+	}
+
+	set.ActiveKeyID = id
+	return nil
+}
+
+// Save는 key set을 소유자만 읽을 수 있는 파일로 저장한다
+func (set *PasetoKeySet) Save(path string) error {
+	file := pasetoKeySetJSON{Purpose: set.Purpose, ActiveKeyID: set.ActiveKeyID}
+	for _, key := range set.Keys {
+		file.Keys = append(file.Keys, &pasetoKeyJSON{
+			ID:        key.ID,
+			Secret:    hex.EncodeToString(key.Secret),
+			CreatedAt: formatKeyTime(key.CreatedAt),
+			ExpiresAt: formatKeyTime(key.ExpiresAt),
+		})
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can not encode token keys: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("can not write token key file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("can not replace token key file: %w", err)
+	}
+	return nil
+}
+
+// VerificationKeys는 v2.public key set에서 만료되지 않은 공개키를 반환한다
+func (set *PasetoKeySet) VerificationKeys() []*PasetoKey {
+	if set.Purpose != PurposePublic {
+		return nil
+	}
+
+	now := time.Now()
+	keys := []*PasetoKey{}
+	for _, key := range set.Keys {
+		if key.Expired(now) {
+			continue
+		}
+		keys = append(keys, &PasetoKey{
+			ID:        key.ID,
+			PublicKey: key.publicKey(),
+			CreatedAt: key.CreatedAt,
+			ExpiresAt: key.ExpiresAt,
+		})
+	}
+	return keys
+}
+
+// PasetoKeysFromPb는 GetVerificationKeys 응답을 NewPasetoVerifier에 넘길 키로 바꾼다
+func PasetoKeysFromPb(res *pb.GetVerificationKeysResponse) ([]*PasetoKey, error) {
+	keys := make([]*PasetoKey, 0, len(res.GetKeys()))
+	for _, key := range res.GetKeys() {
+		if key.GetPurpose() != PurposePublic {
+			return nil, fmt.Errorf("unsupported purpose of key %s: %s", key.GetKeyId(), key.GetPurpose())
+		}
+		if len(key.GetPublicKey()) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("public key %s must be %d bytes", key.GetKeyId(), ed25519.PublicKeySize)
+		}
+
+		other := &PasetoKey{
+			ID:        key.GetKeyId(),
+			PublicKey: ed25519.PublicKey(key.GetPublicKey()),
+			CreatedAt: key.GetCreatedAt().AsTime(),
+		}
+		if key.GetExpiresAt() != nil {
+			other.ExpiresAt = key.GetExpiresAt().AsTime()
+		}
+		keys = append(keys, other)
+	}
+	return keys, nil
+}
+
+func toPbVerificationKey(key *PasetoKey) *pb.VerificationKey {
+	other := &pb.VerificationKey{
+		KeyId:     key.ID,
+		Purpose:   PurposePublic,
+		PublicKey: key.PublicKey,
+		CreatedAt: timestamppb.New(key.CreatedAt),
+	}
+	if !key.ExpiresAt.IsZero() {
+		other.ExpiresAt = timestamppb.New(key.ExpiresAt)
+	}
+	return other
+}
+
+func (key *PasetoKey) privateKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(key.Secret)
+}
+
+func (key *PasetoKey) publicKey() ed25519.PublicKey {
+	if key.PublicKey != nil {
+		return key.PublicKey
+	}
+	return key.privateKey().Public().(ed25519.PublicKey)
+}
+
+func (key *PasetoKey) Expired(now time.Time) bool {
+	return !key.ExpiresAt.IsZero() && !now.Before(key.ExpiresAt)
+}
+
+func parseKeyTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func formatKeyTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPasetoKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	key, err := GeneratePasetoKey()
+	require.NoError(t, err)
+	keys := NewPasetoKeySet(PurposeLocal, key)
+
+	user := &User{Username: "alice", Role: "user"}
+	oldToken, err := NewPasetoManagerWithKeys(keys, TokenDuration).CreateToken(user)
+	require.NoError(t, err)
+
+	// publish한 키는 activate 전까지 검증에만 쓰이므로 먼저 activate한 서버의 token도 검증된다
+	next, err := keys.Publish()
+	require.NoError(t, err)
+	require.Equal(t, key.ID, keys.ActiveKeyID)
+	require.True(t, key.ExpiresAt.IsZero())
+
+	publishedManager := NewPasetoManagerWithKeys(keys, TokenDuration)
+	earlyToken, err := NewPasetoManagerWithKeys(NewPasetoKeySet(PurposeLocal, next), TokenDuration).CreateToken(user)
+	require.NoError(t, err)
+	_, err = publishedManager.VerifyToken(earlyToken)
+	require.NoError(t, err)
+
+	require.Error(t, keys.Activate("unknown", time.Hour))
+	require.Error(t, keys.Activate(key.ID, time.Hour))
+
+	// 이전 키로 발급된 token은 grace 동안 계속 검증된다
+	require.NoError(t, keys.Activate(next.ID, time.Hour))
+	require.Equal(t, next.ID, keys.ActiveKeyID)
+	require.False(t, key.ExpiresAt.IsZero())
+
+	path := filepath.Join(t.TempDir(), "token_keys.json")
+	require.NoError(t, keys.Save(path))
+	loaded, err := LoadPasetoKeys(path)
+	require.NoError(t, err)
+	require.Equal(t, keys.ActiveKeyID, loaded.ActiveKeyID)
+	require.Len(t, loaded.Keys, 2)
+
+	manager := NewPasetoManagerWithKeys(loaded, TokenDuration)
+	payload, err := manager.VerifyToken(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, "alice", payload.Username)
+
+	newToken, err := manager.CreateToken(user)
+	require.NoError(t, err)
+	_, err = manager.VerifyToken(newToken)
+	require.NoError(t, err)
+
+	// 만료된 키나 모르는 키로 발급된 token은 거부한다
+	loaded.Find(key.ID).ExpiresAt = time.Now().Add(-time.Second)
+	_, err = manager.VerifyToken(oldToken)
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = NewPasetoManager(TokenKey, TokenDuration).VerifyToken(newToken)
+	require.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = keys.Publish()
+	require.NoError(t, err)
+	require.Len(t, keys.Keys, 3)
+
+	_, err = ParsePasetoKeys([]byte(`{"active_key_id":"a","keys":[{"id":"a","secret":"00"}]}`))
+	require.Error(t, err)
+}
+
+func TestPasetoPublicTokens(t *testing.T) {
+	t.Parallel()
+
+	key, err := GeneratePasetoKey()
+	require.NoError(t, err)
+	keys := NewPasetoKeySet(PurposePublic, key)
+	require.NoError(t, keys.Check())
+
+	tokenManager := NewPasetoManagerWithKeys(keys, TokenDuration)
+	token, err := tokenManager.CreateToken(&User{Username: "alice", Role: "user"})
+	require.NoError(t, err)
+	require.Contains(t, token, PurposePublic+".")
+
+	// 다른 서비스는 공개키만 받아서 token을 검증한다
+	server := NewAuthServer(NewInMemoryUserStore(), tokenManager, NewInMemoryRefreshTokenStore(), NewInMemoryRevocationStore())
+	res, err := server.GetVerificationKeys(context.Background(), &pb.GetVerificationKeysRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.GetKeys(), 1)
+	require.Equal(t, key.ID, res.GetKeys()[0].GetKeyId())
+
+	verificationKeys, err := PasetoKeysFromPb(res)
+	require.NoError(t, err)
+	verifier := NewPasetoVerifier(verificationKeys)
+
+	payload, err := verifier.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, "alice", payload.Username)
+
+	_, err = verifier.CreateToken(&User{Username: "mallory", Role: "admin"})
+	require.Error(t, err)
+
+	// v2.local과 v2.public token은 서로 섞어 쓸 수 없다
+	localManager := NewPasetoManager(TokenKey, TokenDuration)
+	_, err = localManager.VerifyToken(token)
+	require.Error(t, err)
+
+	localToken, err := localManager.CreateToken(&User{Username: "alice", Role: "user"})
+	require.NoError(t, err)
+	_, err = verifier.VerifyToken(localToken)
+	require.Error(t, err)
+
+	server.TokenManager = localManager
+	_, err = server.GetVerificationKeys(context.Background(), &pb.GetVerificationKeysRequest{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
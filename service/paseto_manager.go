@@ -1,50 +1,110 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/o1egl/paseto"
 )
 
 const (
 	authorizationHeader = "authorization"
 	authorizationBearer = "bearer"
-	TokenKey            = "cd56e76e8bf6a1c32eb26966c864e983"
+	authorizationAPIKey = "apikey"
 	TokenDuration       = 15 * time.Minute
+
+	// TokenKey는 개발용 키이며 운영 환경에서는 key 파일이나 환경 변수로 키를 지정해야 한다
+	TokenKey   = "cd56e76e8bf6a1c32eb26966c864e983"
+	TokenKeyID = "default"
 )
 
+var ErrTokenExpired = errors.New("token has expired")
+
+// PasetoManager는 active 키로 token을 발급하고 footer의 kid에 해당하는 키로 검증한다
+// v2.public key set이면 Ed25519로 서명하므로 공개키만 가진 서비스도 token을 검증할 수 있다
 type PasetoManager struct {
 	paseto        *paseto.V2
-	symmetricKey  []byte
+	keys          *PasetoKeySet
 	tokenDuration time.Duration
 }
 
+type tokenFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// TwoFactor는 2단계 인증을 마친 로그인에서 발급된 token이면 true이다
+// OrgID는 사용자의 organization이며 org claim이 없는 이전 token은 default organization으로 본다
 type UserPayload struct {
 	paseto.JSONToken
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	OrgID     string `json:"org"`
+	TwoFactor bool   `json:"2fa"`
 }
 
 func NewPasetoManager(secretKey string, tokenDuration time.Duration) *PasetoManager {
+	return NewPasetoManagerWithKeys(NewPasetoKeySet(PurposeLocal, &PasetoKey{ID: TokenKeyID, Secret: []byte(secretKey)}), tokenDuration)
+}
+
+// NewPasetoVerifier는 GetVerificationKeys로 받은 공개키로 v2.public token을 검증만 한다
+func NewPasetoVerifier(keys []*PasetoKey) *PasetoManager {
+	return NewPasetoManagerWithKeys(&PasetoKeySet{Purpose: PurposePublic, Keys: keys}, 0)
+}
+
+func NewPasetoManagerWithKeys(keys *PasetoKeySet, tokenDuration time.Duration) *PasetoManager {
 	return &PasetoManager{
 		paseto:        paseto.NewV2(),
-		symmetricKey:  []byte(secretKey),
+		keys:          keys,
 		tokenDuration: tokenDuration,
 	}
 }
 
+// CreateToken은 token마다 jti를 붙여 logout할 때 그 token만 폐기할 수 있게 한다
 func (manager *PasetoManager) CreateToken(user *User) (string, error) {
+	return manager.createToken(user, false)
+}
+
+// CreateTwoFactorToken은 2단계 인증을 마친 사용자의 token을 발급한다
+func (manager *PasetoManager) CreateTwoFactorToken(user *User) (string, error) {
+	return manager.createToken(user, true)
+}
+
+func (manager *PasetoManager) createToken(user *User, twoFactor bool) (string, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return "", fmt.Errorf("failed to create token id: %w", err)
+	}
+
+	now := time.Now()
 	payload := &UserPayload{
 		JSONToken: paseto.JSONToken{
-			Expiration: time.Now().Add(manager.tokenDuration),
+			Jti:        tokenID.String(),
+			IssuedAt:   now,
+			Expiration: now.Add(manager.tokenDuration),
 		},
 	}
 	payload.Set("username", user.Username)
 	payload.Set("role", user.Role)
+	payload.Set("org", user.Org())
+	if twoFactor {
+		payload.Set("2fa", "true")
+	}
 
-	token, err := manager.paseto.Encrypt(manager.symmetricKey, payload, nil)
+	key := manager.keys.Active()
+	if key == nil || key.Secret == nil {
+		return "", fmt.Errorf("no active signing key")
+	}
+
+	footer := &tokenFooter{KeyID: key.ID}
+	var token string
+	if manager.keys.Purpose == PurposePublic {
+		token, err = manager.paseto.Sign(key.privateKey(), payload, footer)
+	} else {
+		token, err = manager.paseto.Encrypt(key.Secret, payload, footer)
+	}
 	if err != nil {
 		return "", fmt.Errorf("token encryption failed: %w", err)
 	}
@@ -52,9 +112,75 @@ func (manager *PasetoManager) CreateToken(user *User) (string, error) {
 	return token, nil
 }
 
+func (manager *PasetoManager) TokenDuration() time.Duration {
+	return manager.tokenDuration
+}
+
+// VerificationKeys는 v2.local 모드이면 공개할 키가 없으므로 nil을 반환한다
+func (manager *PasetoManager) VerificationKeys() []*PasetoKey {
+	return manager.keys.VerificationKeys()
+}
+
 func (manager *PasetoManager) VerifyToken(token string) (*UserPayload, error) {
+	payload, err := manager.InspectToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	err = payload.ValidAt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// ValidAt은 만료 시각과 발급 시각 claim을 확인하며 만료되었으면 ErrTokenExpired를 반환한다
+func (payload *UserPayload) ValidAt(now time.Time) error {
+	if !payload.Expiration.IsZero() && now.After(payload.Expiration) {
+		return fmt.Errorf("%w at %s", ErrTokenExpired, payload.Expiration.Format(time.RFC3339))
+	}
+
+	err := payload.Validate(paseto.ValidAt(now))
+	if err != nil {
+		return fmt.Errorf("invalid token claims: %w", err)
+	}
+	return nil
+}
+
+// InspectToken은 token의 키와 서명을 확인하고 payload를 반환하지만 만료 시각 등 claim은 확인하지 않는다
+// kid가 없는 token은 이전 버전에서 발급된 것으로 보고 active 키로 검증한다
+// key set과 purpose가 다른 token은 거부한다
+func (manager *PasetoManager) InspectToken(token string) (*UserPayload, error) {
+	_, purpose, err := paseto.GetTokenInfo(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	public := manager.keys.Purpose == PurposePublic
+	if public != (purpose == paseto.PUBLIC) {
+		return nil, fmt.Errorf("unexpected token purpose")
+	}
+
+	var footer tokenFooter
+	if err := paseto.ParseFooter(token, &footer); err != nil {
+		return nil, fmt.Errorf("invalid token footer: %w", err)
+	}
+
+	key := manager.keys.Active()
+	if footer.KeyID != "" {
+		key = manager.keys.Find(footer.KeyID)
+	}
+	if key == nil || key.Expired(time.Now()) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, footer.KeyID)
+	}
+
 	var newPayload paseto.JSONToken
-	err := manager.paseto.Decrypt(token, manager.symmetricKey, &newPayload, nil)
+	if public {
+		err = manager.paseto.Verify(token, key.publicKey(), &newPayload, nil)
+	} else {
+		err = manager.paseto.Decrypt(token, key.Secret, &newPayload, nil)
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "expired") {
 			return nil, fmt.Errorf("token expired: %w", err)
@@ -69,6 +195,8 @@ func (manager *PasetoManager) VerifyToken(token string) (*UserPayload, error) {
 	payload := &UserPayload{
 		Username:  username,
 		Role:      role,
+		OrgID:     orgOrDefault(newPayload.Get("org")),
+		TwoFactor: newPayload.Get("2fa") == "true",
 		JSONToken: newPayload,
 	}
 
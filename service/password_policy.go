@@ -0,0 +1,79 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidUsername = errors.New("username must be 3 to 32 characters of a-z, 0-9, '.', '_' or '-' and start with a letter or digit")
+	ErrWeakPassword    = errors.New("password does not satisfy the password policy")
+
+	usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{2,31}$`)
+)
+
+// bcrypt는 72 byte 이후를 무시하므로 더 긴 비밀번호는 받지 않는다
+const maxPasswordLength = 72
+
+type PasswordPolicy struct {
+	MinLength int
+	Breached  map[string]bool
+}
+
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength: 8,
+}
+
+// NormalizeUsername은 앞뒤 공백을 제거하고 소문자로 바꾼 뒤 형식을 검사한다
+func NormalizeUsername(username string) (string, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if !usernamePattern.MatchString(username) {
+		return "", ErrInvalidUsername
+	}
+	return username, nil
+}
+
+func (policy PasswordPolicy) Check(username string, password string) error {
+	if len(password) < policy.MinLength {
+		return fmt.Errorf("%w: must be at least %d characters", ErrWeakPassword, policy.MinLength)
+	}
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: must be at most %d bytes", ErrWeakPassword, maxPasswordLength)
+	}
+	if strings.EqualFold(password, username) {
+		return fmt.Errorf("%w: must not be the same as the username", ErrWeakPassword)
+	}
+	if policy.Breached[password] {
+		return fmt.Errorf("%w: appears in a list of breached passwords", ErrWeakPassword)
+	}
+	return nil
+}
+
+// LoadBreachedPasswords는 한 줄에 하나씩 적힌 유출 비밀번호 목록을 읽는다
+// 빈 줄과 '#'으로 시작하는 줄은 무시한다
+func LoadBreachedPasswords(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not open breached password file: %w", err)
+	}
+	defer file.Close()
+
+	breached := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		breached[line] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can not read breached password file: %w", err)
+	}
+
+	return breached, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// 호출자가 어떤 방식으로 인증했는지 나타낸다
+const (
+	AuthMethodToken  = "token"
+	AuthMethodAPIKey = "apikey"
+)
+
+// Principal은 interceptor가 인증한 호출자이다
+// 공개 rpc에는 principal이 없다
+// api key로 인증하면 TokenID는 key id이고 LaptopIDs가 key의 laptop scope이다
+// client 인증서로 인증하면 TokenID는 인증서 serial이다
+// TwoFactor는 2단계 인증을 마친 token으로 인증했으면 true이다
+// OrgID는 호출자가 속한 organization이며 laptop server는 이 organization의 catalog만 사용한다
+type Principal struct {
+	Username   string
+	Role       string
+	OrgID      string
+	TokenID    string
+	AuthMethod string
+	ExpiresAt  time.Time
+	LaptopIDs  []string
+	TwoFactor  bool
+}
+
+type principalKey struct{}
+
+func newTokenPrincipal(payload *UserPayload) *Principal {
+	return &Principal{
+		Username:   payload.Username,
+		Role:       payload.Role,
+		OrgID:      payload.OrgID,
+		TokenID:    payload.Jti,
+		AuthMethod: AuthMethodToken,
+		ExpiresAt:  payload.Expiration,
+		TwoFactor:  payload.TwoFactor,
+	}
+}
+
+// api key principal의 username은 사용자 이름과 겹치지 않도록 "apikey:"로 시작한다
+func newAPIKeyPrincipal(key *APIKey) *Principal {
+	return &Principal{
+		Username:   AuthMethodAPIKey + ":" + key.ID,
+		Role:       APIKeyRole,
+		OrgID:      key.OrgID,
+		TokenID:    key.ID,
+		AuthMethod: AuthMethodAPIKey,
+		LaptopIDs:  key.LaptopIDs,
+	}
+}
+
+// PrincipalFromContext는 인증된 호출자를 반환하고 없으면 nil을 반환한다
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+func contextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	if principal == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// AllowsLaptop은 principal이 laptop을 다룰 수 있는지 확인한다
+// laptop scope는 api key와 client 인증서에만 있으며 비어 있으면 제한이 없다
+func (principal *Principal) AllowsLaptop(laptopID string) bool {
+	if principal == nil {
+		return false
+	}
+	if len(principal.LaptopIDs) == 0 {
+		return true
+	}
+	for _, id := range principal.LaptopIDs {
+		if id == laptopID {
+			return true
+		}
+	}
+	return false
+}
+
+// Org는 호출자의 organization을 반환하며 인증하지 않은 호출자는 default organization에 속한다
+func (principal *Principal) Org() string {
+	if principal == nil {
+		return DefaultOrgID
+	}
+	return orgOrDefault(principal.OrgID)
+}
+
+// CanAccessOrg는 super-admin이거나 orgID organization에 속한 호출자인지 확인한다
+func (principal *Principal) CanAccessOrg(orgID string) bool {
+	if principal != nil && principal.Role == SuperAdminRole {
+		return true
+	}
+	return principal.Org() == orgOrDefault(orgID)
+}
+
+func (principal *Principal) String() string {
+	if principal == nil {
+		return "anonymous"
+	}
+	return principal.Username + "(" + principal.Role + ") via " + principal.AuthMethod
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizePrincipal(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParsePolicy([]byte(`{
+		"roles": {"user": {"permissions": ["laptop.read"]}},
+		"rules": {
+			"/pcbook.AuthService/Login": "public",
+			"/pcbook.LaptopService/SearchLaptop": "laptop.read"
+		}
+	}`))
+	require.NoError(t, err)
+
+	tokenManager := NewPasetoManager(TokenKey, TokenDuration)
+	interceptor := NewAuthInterceptor(tokenManager, nil, nil, nil, policy)
+
+	token, err := tokenManager.CreateToken(&User{Username: "alice", Role: "user"})
+	require.NoError(t, err)
+	payload, err := tokenManager.VerifyToken(token)
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, "Bearer "+token))
+	principal, err := interceptor.Authorize(ctx, "/pcbook.LaptopService/SearchLaptop")
+	require.NoError(t, err)
+	require.Equal(t, "alice", principal.Username)
+	require.Equal(t, "user", principal.Role)
+	require.Equal(t, payload.Jti, principal.TokenID)
+	require.Equal(t, AuthMethodToken, principal.AuthMethod)
+	require.True(t, payload.Expiration.Equal(principal.ExpiresAt))
+
+	ctx = contextWithPrincipal(context.Background(), principal)
+	require.Same(t, principal, PrincipalFromContext(ctx))
+
+	// 공개 rpc에는 principal이 없다
+	principal, err = interceptor.Authorize(context.Background(), "/pcbook.AuthService/Login")
+	require.NoError(t, err)
+	require.Nil(t, principal)
+	require.Nil(t, PrincipalFromContext(contextWithPrincipal(context.Background(), principal)))
+	require.Equal(t, "anonymous", principal.String())
+	require.Equal(t, DefaultOrgID, principal.Org())
+
+	// 공개 rpc도 유효한 token을 보내면 호출자 organization을 알 수 있도록 principal이 있다
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, "Bearer "+token))
+	principal, err = interceptor.Authorize(ctx, "/pcbook.AuthService/Login")
+	require.NoError(t, err)
+	require.Equal(t, "alice", principal.Username)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, "Bearer invalid"))
+	principal, err = interceptor.Authorize(ctx, "/pcbook.AuthService/Login")
+	require.NoError(t, err)
+	require.Nil(t, principal)
+}
+
+func TestAuthorizeRequiresTwoFactor(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParsePolicy([]byte(`{
+		"roles": {
+			"user": {"permissions": ["laptop.read", "account.manage"]},
+			"admin": {"inherits": ["user"], "require_two_factor": true},
+			"superadmin": {"inherits": ["admin"]}
+		},
+		"rules": {
+			"/pcbook.LaptopService/SearchLaptop": "laptop.read",
+			"/pcbook.AuthService/EnrollTOTP": "account.manage"
+		}
+	}`))
+	require.NoError(t, err)
+	require.False(t, policy.RequiresTwoFactor("user"))
+	require.True(t, policy.RequiresTwoFactor("superadmin"))
+
+	tokenManager := NewPasetoManager(TokenKey, TokenDuration)
+	interceptor := NewAuthInterceptor(tokenManager, nil, nil, nil, policy)
+	authorize := func(token string, method string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, "Bearer "+token))
+		_, err := interceptor.Authorize(ctx, method)
+		return err
+	}
+
+	token, err := tokenManager.CreateToken(&User{Username: "root", Role: "superadmin"})
+	require.NoError(t, err)
+	require.Equal(t, codes.PermissionDenied, status.Code(authorize(token, "/pcbook.LaptopService/SearchLaptop")))
+	// TOTP를 등록하는 rpc는 2단계 인증 없이 호출할 수 있다
+	require.NoError(t, authorize(token, "/pcbook.AuthService/EnrollTOTP"))
+
+	token, err = tokenManager.CreateTwoFactorToken(&User{Username: "root", Role: "superadmin"})
+	require.NoError(t, err)
+	require.NoError(t, authorize(token, "/pcbook.LaptopService/SearchLaptop"))
+
+	token, err = tokenManager.CreateToken(&User{Username: "alice", Role: "user"})
+	require.NoError(t, err)
+	require.NoError(t, authorize(token, "/pcbook.LaptopService/SearchLaptop"))
+}
+
+func TestAuthorizeAPIKey(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParsePolicy([]byte(`{
+		"roles": {
+			"user": {"permissions": ["laptop.read", "laptop.report"]},
+			"device": {"permissions": ["laptop.report"]}
+		},
+		"rules": {
+			"/pcbook.LaptopService/SearchLaptop": "laptop.read",
+			"/pcbook.LaptopService/SendLaptopInfo": "laptop.report"
+		}
+	}`))
+	require.NoError(t, err)
+
+	apiKeys := NewInMemoryAPIKeyStore()
+	interceptor := NewAuthInterceptor(NewPasetoManager(TokenKey, TokenDuration), nil, nil, apiKeys, policy)
+
+	value, key, err := NewAPIKey("agent", []string{"/pcbook.LaptopService/SendLaptopInfo", "/pcbook.LaptopService/SearchLaptop"}, []string{"serial-1"}, "admin")
+	require.NoError(t, err)
+	require.NoError(t, apiKeys.Create(key))
+
+	apiKeyContext := func(value string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, "ApiKey "+value))
+	}
+
+	principal, err := interceptor.Authorize(apiKeyContext(value), "/pcbook.LaptopService/SendLaptopInfo")
+	require.NoError(t, err)
+	require.Equal(t, AuthMethodAPIKey, principal.AuthMethod)
+	require.Equal(t, APIKeyRole, principal.Role)
+	require.Equal(t, key.ID, principal.TokenID)
+	require.True(t, principal.AllowsLaptop("serial-1"))
+	require.False(t, principal.AllowsLaptop("serial-2"))
+
+	// scope에 있어도 device role에 permission이 없으면 호출할 수 없다
+	_, err = interceptor.Authorize(apiKeyContext(value), "/pcbook.LaptopService/SearchLaptop")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = interceptor.Authorize(apiKeyContext(key.ID+".wrong"), "/pcbook.LaptopService/SendLaptopInfo")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = apiKeys.Revoke(key.ID)
+	require.NoError(t, err)
+	_, err = interceptor.Authorize(apiKeyContext(value), "/pcbook.LaptopService/SendLaptopInfo")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// scope에 없는 rpc는 거부한다
+	other, key, err := NewAPIKey("agent", []string{"/pcbook.LaptopService/SendLaptopInfo"}, nil, "admin")
+	require.NoError(t, err)
+	require.NoError(t, apiKeys.Create(key))
+	_, err = interceptor.Authorize(apiKeyContext(other), "/pcbook.LaptopService/SearchLaptop")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	principal, err = interceptor.Authorize(apiKeyContext(other), "/pcbook.LaptopService/SendLaptopInfo")
+	require.NoError(t, err)
+	require.True(t, principal.AllowsLaptop("serial-2"))
+}
+
+func TestAuditRecord(t *testing.T) {
+	t.Parallel()
+
+	// 인증에 실패한 호출도 method와 error code를 남긴다
+	record := auditRecord(nil, "/pcbook.LaptopService/SearchLaptop", status.Error(codes.Unauthenticated, "invalid token"))
+	require.Equal(t, "audit: user=- role=- auth=- token=- method=/pcbook.LaptopService/SearchLaptop code=Unauthenticated", record)
+
+	principal := &Principal{Username: "alice", Role: "user", AuthMethod: AuthMethodToken, TokenID: "token-1"}
+	record = auditRecord(principal, "/pcbook.LaptopService/SearchLaptop", nil)
+	require.Equal(t, "audit: user=alice role=user auth="+AuthMethodToken+" token=token-1 method=/pcbook.LaptopService/SearchLaptop code=OK", record)
+}
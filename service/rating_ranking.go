@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrStopRanking을 found가 반환하면 순위 탐색을 오류 없이 멈춘다
+var ErrStopRanking = errors.New("stop ranking")
+
+// ratingRanking은 laptop을 평균 점수 내림차순으로 정렬해 두고
+// 평균이 같으면 평가 수가 많은 순, 그다음 id 순으로 정렬한다
+type ratingRanking struct {
+	entries []rankingEntry
+}
+
+type rankingEntry struct {
+	laptopID string
+	average  float64
+	count    uint32
+}
+
+func newRankingEntry(laptopID string, rating *Rating) rankingEntry {
+	return rankingEntry{
+		laptopID: laptopID,
+		average:  rating.Average(),
+		count:    rating.Count,
+	}
+}
+
+func (entry rankingEntry) before(other rankingEntry) bool {
+	if entry.average != other.average {
+		return entry.average > other.average
+	}
+	if entry.count != other.count {
+		return entry.count > other.count
+	}
+	return entry.laptopID < other.laptopID
+}
+
+func (ranking *ratingRanking) search(entry rankingEntry) int {
+	return sort.Search(len(ranking.entries), func(i int) bool {
+		return !ranking.entries[i].before(entry)
+	})
+}
+
+func (ranking *ratingRanking) insert(entry rankingEntry) {
+	i := ranking.search(entry)
+	ranking.entries = append(ranking.entries, rankingEntry{})
+	copy(ranking.entries[i+1:], ranking.entries[i:])
+	ranking.entries[i] = entry
+}
+
+func (ranking *ratingRanking) remove(entry rankingEntry) {
+	i := ranking.search(entry)
+	if i < len(ranking.entries) && ranking.entries[i] == entry {
+		ranking.entries = append(ranking.entries[:i], ranking.entries[i+1:]...)
+	}
+}
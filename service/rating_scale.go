@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"math"
+)
+
+// RatingScale은 허용되는 점수 범위와 간격이다 (예: 1~10, 0.5 단위)
+type RatingScale struct {
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+var DefaultRatingScale = RatingScale{
+	Min:  1,
+	Max:  10,
+	Step: 0.5,
+}
+
+// RatingPrior는 Bayesian 평균에 사용하는 사전 평균과 가중치(가상의 평가 수)이다
+type RatingPrior struct {
+	Mean   float64
+	Weight float64
+}
+
+var DefaultRatingPrior = RatingPrior{
+	Mean:   5.5,
+	Weight: 5,
+}
+
+const (
+	ratingStepEpsilon = 1e-9
+	maxHistogramSteps = 1000
+)
+
+func (scale RatingScale) Validate(score float64) error {
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		return fmt.Errorf("score must be a finite number")
+	}
+
+	if score < scale.Min || score > scale.Max {
+		return fmt.Errorf("score %g is out of range [%g, %g]", score, scale.Min, scale.Max)
+	}
+
+	if scale.Step > 0 {
+		steps := (score - scale.Min) / scale.Step
+		if math.Abs(steps-math.Round(steps)) > ratingStepEpsilon {
+			return fmt.Errorf("score %g is not a multiple of %g", score, scale.Step)
+		}
+	}
+
+	return nil
+}
+
+func (scale RatingScale) Check() error {
+	if math.IsNaN(scale.Min) || math.IsNaN(scale.Max) || scale.Min > scale.Max {
+		return fmt.Errorf("invalid rating range [%g, %g]", scale.Min, scale.Max)
+	}
+	if scale.Step < 0 {
+		return fmt.Errorf("rating step must not be negative: %g", scale.Step)
+	}
+	return nil
+}
+
+// Buckets는 scale의 모든 점수를 오름차순으로 반환한다
+// step이 없거나 구간이 너무 많으면 nil을 반환한다
+func (scale RatingScale) Buckets() []float64 {
+	if scale.Step <= 0 || (scale.Max-scale.Min)/scale.Step > maxHistogramSteps {
+		return nil
+	}
+
+	buckets := []float64{}
+	for i := 0; ; i++ {
+		score := scale.Min + float64(i)*scale.Step
+		if score > scale.Max+ratingStepEpsilon {
+			break
+		}
+		buckets = append(buckets, score)
+	}
+	return buckets
+}
+
+// Snap은 부동소수점 오차가 있는 점수를 Buckets의 값과 같아지도록 맞춘다
+func (scale RatingScale) Snap(score float64) float64 {
+	if scale.Step <= 0 {
+		return score
+	}
+	return scale.Min + math.Round((score-scale.Min)/scale.Step)*scale.Step
+}
@@ -1,42 +1,220 @@
 package service
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
 
 type RatingStore interface {
-	Add(laptopID string, score float64) (*Rating, error)
+	Add(laptopID string, username string, score float64) (*Rating, error)
+	Find(laptopID string) (*Rating, error)
+	Delete(laptopID string, username string) (*Rating, error)
+	DeleteLaptop(laptopID string) error
+	ListByUser(username string) ([]*UserRating, error)
+	Ranking(ctx context.Context, minCount uint32, found func(laptopID string, rating *Rating) error) error
 }
 
+// Distribution은 점수별 평가 수이다
 type Rating struct {
-	Count uint32
-	sum   float64
+	Count        uint32
+	sum          float64
+	Distribution map[float64]uint32
+}
+
+// UserRating은 한 사용자가 laptop에 매긴 점수이며 다시 평가하면 덮어쓴다
+type UserRating struct {
+	LaptopID  string
+	Username  string
+	Score     float64
+	UpdatedAt time.Time
 }
 
 type InmemoryRatingStore struct {
-	mutax  sync.RWMutex
-	rating map[string]*Rating
+	mutax   sync.RWMutex
+	rating  map[string]*Rating
+	scores  map[string]map[string]*UserRating
+	ranking ratingRanking
 }
 
 func NewInMemoryRatingStore() *InmemoryRatingStore {
 	return &InmemoryRatingStore{
 		rating: make(map[string]*Rating),
+		scores: make(map[string]map[string]*UserRating),
 	}
 }
 
-func (store *InmemoryRatingStore) Add(laptopID string, score float64) (*Rating, error) {
+func (store *InmemoryRatingStore) Add(laptopID string, username string, score float64) (*Rating, error) {
 	store.mutax.Lock()
 	defer store.mutax.Unlock()
 
 	rating := store.rating[laptopID]
 	if rating == nil {
-		rating = &Rating{
-			Count: 1,
-			sum:   score,
-		}
-	} else {
+		rating = &Rating{Distribution: make(map[float64]uint32)}
+		store.rating[laptopID] = rating
+	}
+
+	scores := store.scores[laptopID]
+	if scores == nil {
+		scores = make(map[string]*UserRating)
+		store.scores[laptopID] = scores
+	}
+
+	if rating.Count > 0 {
+		store.ranking.remove(newRankingEntry(laptopID, rating))
+	}
+
+	previous := scores[username]
+	if previous == nil {
 		rating.Count++
-		rating.sum += score
+	} else {
+		rating.sum -= previous.Score
+		rating.removeScore(previous.Score)
+	}
+	rating.sum += score
+	rating.Distribution[score]++
+	store.ranking.insert(newRankingEntry(laptopID, rating))
+
+	scores[username] = &UserRating{
+		LaptopID:  laptopID,
+		Username:  username,
+		Score:     score,
+		UpdatedAt: time.Now(),
+	}
+
+	return rating.Clone(), nil
+}
+
+func (store *InmemoryRatingStore) Delete(laptopID string, username string) (*Rating, error) {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	previous := store.scores[laptopID][username]
+	if previous == nil {
+		return nil, ErrNotFound
+	}
+	delete(store.scores[laptopID], username)
+
+	rating := store.rating[laptopID]
+	store.ranking.remove(newRankingEntry(laptopID, rating))
+	rating.Count--
+	rating.sum -= previous.Score
+	rating.removeScore(previous.Score)
+	if rating.Count == 0 {
+		rating.sum = 0
+	} else {
+		store.ranking.insert(newRankingEntry(laptopID, rating))
+	}
+
+	return rating.Clone(), nil
+}
+
+// DeleteLaptop은 laptop의 모든 평가를 지운다
+func (store *InmemoryRatingStore) DeleteLaptop(laptopID string) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	rating := store.rating[laptopID]
+	if rating == nil {
+		return nil
+	}
+	if rating.Count > 0 {
+		store.ranking.remove(newRankingEntry(laptopID, rating))
+	}
+	delete(store.rating, laptopID)
+	delete(store.scores, laptopID)
+
+	return nil
+}
+
+func (store *InmemoryRatingStore) Find(laptopID string) (*Rating, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	rating := store.rating[laptopID]
+	if rating == nil {
+		return nil, nil
+	}
+
+	return rating.Clone(), nil
+}
+
+func (store *InmemoryRatingStore) ListByUser(username string) ([]*UserRating, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	ratings := []*UserRating{}
+	for _, scores := range store.scores {
+		if score := scores[username]; score != nil {
+			other := *score
+			ratings = append(ratings, &other)
+		}
 	}
 
-	store.rating[laptopID] = rating
-	return rating, nil
+	sort.Slice(ratings, func(i, j int) bool {
+		return ratings[i].UpdatedAt.After(ratings[j].UpdatedAt)
+	})
+
+	return ratings, nil
+}
+
+// Ranking은 평균 점수가 높은 순으로 평가 수가 minCount 이상인 laptop을 found에 전달한다
+// found는 lock을 잡은 상태에서 호출되므로 rating store를 다시 호출하면 안 된다
+func (store *InmemoryRatingStore) Ranking(ctx context.Context, minCount uint32, found func(laptopID string, rating *Rating) error) error {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	for _, entry := range store.ranking.entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.count < minCount {
+			continue
+		}
+
+		err := found(entry.laptopID, store.rating[entry.laptopID].Clone())
+		if errors.Is(err, ErrStopRanking) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rating *Rating) Average() float64 {
+	if rating.Count == 0 {
+		return 0
+	}
+	return rating.sum / float64(rating.Count)
+}
+
+// BayesianAverage는 평가 수가 적은 laptop의 평균을 prior 쪽으로 당긴다
+// (prior.Weight * prior.Mean + sum) / (prior.Weight + count)
+func (rating *Rating) BayesianAverage(prior RatingPrior) float64 {
+	weight := prior.Weight + float64(rating.Count)
+	if weight == 0 {
+		return 0
+	}
+	return (prior.Weight*prior.Mean + rating.sum) / weight
+}
+
+func (rating *Rating) Clone() *Rating {
+	other := *rating
+	other.Distribution = make(map[float64]uint32, len(rating.Distribution))
+	for score, count := range rating.Distribution {
+		other.Distribution[score] = count
+	}
+	return &other
+}
+
+func (rating *Rating) removeScore(score float64) {
+	rating.Distribution[score]--
+	if rating.Distribution[score] == 0 {
+		delete(rating.Distribution, score)
+	}
 }
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryRatingStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryRatingStore()
+
+	rating, err := store.Add("laptop-1", "alice", 4)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), rating.Count)
+
+	rating, err = store.Add("laptop-1", "alice", 9)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), rating.Count)
+	require.Equal(t, float64(9), rating.Average())
+
+	rating, err = store.Add("laptop-1", "bob", 5)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), rating.Count)
+	require.Equal(t, float64(7), rating.Average())
+
+	_, err = store.Add("laptop-2", "alice", 3)
+	require.NoError(t, err)
+
+	ratings, err := store.ListByUser("alice")
+	require.NoError(t, err)
+	require.Len(t, ratings, 2)
+
+	rating, err = store.Find("laptop-1")
+	require.NoError(t, err)
+	require.Equal(t, map[float64]uint32{9: 1, 5: 1}, rating.Distribution)
+
+	rating, err = store.Delete("laptop-1", "bob")
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), rating.Count)
+	require.Equal(t, float64(9), rating.Average())
+	require.Equal(t, map[float64]uint32{9: 1}, rating.Distribution)
+
+	rating, err = store.Find("laptop-3")
+	require.NoError(t, err)
+	require.Nil(t, rating)
+
+	_, err = store.Delete("laptop-1", "bob")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRatingScaleValidate(t *testing.T) {
+	t.Parallel()
+
+	scale := DefaultRatingScale
+	for _, score := range []float64{1, 1.5, 7, 9.5, 10} {
+		require.NoError(t, scale.Validate(score), score)
+	}
+	for _, score := range []float64{0, 0.5, 10.5, 7.3, -1, 1e9, math.NaN(), math.Inf(1)} {
+		require.Error(t, scale.Validate(score), score)
+	}
+
+	require.NoError(t, RatingScale{Min: 0, Max: 5}.Validate(3.14))
+}
+
+func TestRatingBayesianAverage(t *testing.T) {
+	t.Parallel()
+
+	prior := RatingPrior{Mean: 5, Weight: 3}
+	require.Equal(t, float64(5), (&Rating{}).BayesianAverage(prior))
+	require.Equal(t, float64(6), (&Rating{Count: 1, sum: 9}).BayesianAverage(prior))
+	require.Equal(t, float64(0), (&Rating{}).BayesianAverage(RatingPrior{}))
+
+	scale := RatingScale{Min: 0, Max: 1, Step: 0.1}
+	require.Len(t, scale.Buckets(), 11)
+	require.Equal(t, scale.Buckets()[3], scale.Snap(0.3))
+	require.Nil(t, RatingScale{Min: 0, Max: 5}.Buckets())
+}
+
+func TestInMemoryRatingStoreRanking(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryRatingStore()
+	scores := []struct {
+		laptopID string
+		username string
+		score    float64
+	}{
+		{"laptop-1", "alice", 6},
+		{"laptop-2", "alice", 9},
+		{"laptop-2", "bob", 7},
+		{"laptop-3", "alice", 10},
+		{"laptop-4", "alice", 8},
+		{"laptop-4", "bob", 8},
+		{"laptop-1", "alice", 9}, // 다시 평가하면 순위도 갱신된다
+	}
+	for _, s := range scores {
+		_, err := store.Add(s.laptopID, s.username, s.score)
+		require.NoError(t, err)
+	}
+
+	ranking := func(minCount uint32, limit int) []string {
+		laptopIDs := []string{}
+		err := store.Ranking(context.Background(), minCount, func(laptopID string, rating *Rating) error {
+			require.GreaterOrEqual(t, rating.Count, minCount)
+			laptopIDs = append(laptopIDs, laptopID)
+			if len(laptopIDs) == limit {
+				return ErrStopRanking
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return laptopIDs
+	}
+
+	// 평균과 평가 수가 같으면 id 순이다
+	require.Equal(t, []string{"laptop-3", "laptop-1", "laptop-2", "laptop-4"}, ranking(0, 10))
+	require.Equal(t, []string{"laptop-2", "laptop-4"}, ranking(2, 10))
+	require.Equal(t, []string{"laptop-3", "laptop-1"}, ranking(0, 2))
+
+	_, err := store.Delete("laptop-3", "alice")
+	require.NoError(t, err)
+	require.Equal(t, []string{"laptop-1", "laptop-2", "laptop-4"}, ranking(0, 10))
+}
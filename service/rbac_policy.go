@@ -0,0 +1,225 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// PublicPermission은 인증 없이 호출할 수 있는 rpc에 지정한다
+const PublicPermission = "public"
+
+// Policy는 rpc method를 permission에, role을 permission 집합에 연결한다
+// rule이 없는 method는 호출할 수 없다
+// require_two_factor인 role은 2단계 인증을 마친 token으로만 rpc를 호출할 수 있고
+// 이 설정도 permission처럼 상속된다
+//
+//	{
+//	  "roles": {
+//	    "user":  {"permissions": ["laptop.read"]},
+//	    "admin": {"inherits": ["user"], "permissions": ["user.admin"], "require_two_factor": true}
+//	  },
+//	  "rules": {
+//	    "/pcbook.AuthService/Login": "public",
+//	    "/pcbook.LaptopService/SearchLaptop": "laptop.read",
+//	    "/pcbook.UserAdminService/*": "user.admin"
+//	  }
+//	}
+type Policy struct {
+	permissions map[string]map[string]bool
+	twoFactor   map[string]bool
+	rules       *methodRules
+}
+
+// methodRules는 전체 method 이름이나 끝이 '*'인 prefix로 값을 찾는다
+type methodRules struct {
+	exact     map[string]string
+	wildcards []*wildcardRule
+}
+
+type wildcardRule struct {
+	prefix string
+	value  string
+}
+
+type policyJSON struct {
+	Roles map[string]*policyRoleJSON `json:"roles"`
+	Rules map[string]string          `json:"rules"`
+}
+
+type policyRoleJSON struct {
+	Inherits         []string `json:"inherits"`
+	Permissions      []string `json:"permissions"`
+	RequireTwoFactor bool     `json:"require_two_factor"`
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can not read policy file: %w", err)
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy는 role 상속을 풀어서 role마다 가진 permission을 모두 계산한다
+func ParsePolicy(data []byte) (*Policy, error) {
+	var file policyJSON
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("can not parse policy: %w", err)
+	}
+
+	policy := &Policy{
+		permissions: make(map[string]map[string]bool, len(file.Roles)),
+		twoFactor:   make(map[string]bool),
+		rules:       newMethodRules(),
+	}
+
+	for role := range file.Roles {
+		if _, err := policy.resolveRole(file.Roles, role, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+
+	granted := make(map[string]bool)
+	for _, permissions := range policy.permissions {
+		for permission := range permissions {
+			granted[permission] = true
+		}
+	}
+
+	for method, permission := range file.Rules {
+		if permission == "" {
+			return nil, fmt.Errorf("rule %s has no permission", method)
+		}
+		if permission != PublicPermission && !granted[permission] {
+			return nil, fmt.Errorf("permission %s of rule %s is not granted to any role", permission, method)
+		}
+
+		if err := policy.rules.add(method, permission); err != nil {
+			return nil, err
+		}
+	}
+	policy.rules.sort()
+
+	return policy, nil
+}
+
+func (policy *Policy) resolveRole(roles map[string]*policyRoleJSON, role string, visiting map[string]bool) (map[string]bool, error) {
+	if permissions, ok := policy.permissions[role]; ok {
+		return permissions, nil
+	}
+
+	definition := roles[role]
+	if definition == nil {
+		return nil, fmt.Errorf("unknown role: %s", role)
+	}
+	if visiting[role] {
+		return nil, fmt.Errorf("role %s inherits itself", role)
+	}
+	visiting[role] = true
+
+	permissions := make(map[string]bool)
+	twoFactor := definition.RequireTwoFactor
+	for _, permission := range definition.Permissions {
+		if permission == PublicPermission {
+			return nil, fmt.Errorf("role %s can not be granted the %s permission", role, PublicPermission)
+		}
+		permissions[permission] = true
+	}
+	for _, parent := range definition.Inherits {
+		inherited, err := policy.resolveRole(roles, parent, visiting)
+		if err != nil {
+			return nil, err
+		}
+		for permission := range inherited {
+			permissions[permission] = true
+		}
+		twoFactor = twoFactor || policy.twoFactor[parent]
+	}
+
+	policy.permissions[role] = permissions
+	if twoFactor {
+		policy.twoFactor[role] = true
+	}
+	return permissions, nil
+}
+
+// Permission은 method에 맞는 rule의 permission을 반환한다
+// 정확히 일치하는 rule이 wildcard보다 우선한다
+func (policy *Policy) Permission(method string) (string, bool) {
+	return policy.rules.lookup(method)
+}
+
+func (policy *Policy) HasPermission(role string, permission string) bool {
+	return policy.permissions[role][permission]
+}
+
+// Permissions는 role이 상속받은 것을 포함해 가진 permission을 정렬해서 반환한다
+func (policy *Policy) Permissions(role string) []string {
+	permissions := make([]string, 0, len(policy.permissions[role]))
+	for permission := range policy.permissions[role] {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+	return permissions
+}
+
+func (policy *Policy) RequiresTwoFactor(role string) bool {
+	return policy.twoFactor[role]
+}
+
+// Uncovered는 rule이 없어 아무도 호출할 수 없는 method를 반환한다
+func (policy *Policy) Uncovered(methods []string) []string {
+	uncovered := []string{}
+	for _, method := range methods {
+		if _, ok := policy.Permission(method); !ok {
+			uncovered = append(uncovered, method)
+		}
+	}
+	return uncovered
+}
+
+func newMethodRules() *methodRules {
+	return &methodRules{exact: make(map[string]string)}
+}
+
+func (rules *methodRules) add(method string, value string) error {
+	prefix, wildcard := strings.CutSuffix(method, "*")
+	if strings.Contains(prefix, "*") {
+		return fmt.Errorf("rule %s: wildcard is only allowed at the end", method)
+	}
+	if wildcard {
+		rules.wildcards = append(rules.wildcards, &wildcardRule{prefix: prefix, value: value})
+		return nil
+	}
+	if !strings.HasPrefix(method, "/") {
+		return fmt.Errorf("rule %s must be a full method name like /package.Service/Method", method)
+	}
+	rules.exact[method] = value
+	return nil
+}
+
+// sort는 가장 구체적인 wildcard가 먼저 맞도록 긴 prefix부터 정렬한다
+func (rules *methodRules) sort() {
+	sort.Slice(rules.wildcards, func(i, j int) bool {
+		return len(rules.wildcards[i].prefix) > len(rules.wildcards[j].prefix)
+	})
+}
+
+// lookup은 정확히 일치하는 rule을 wildcard보다 먼저 찾는다
+func (rules *methodRules) lookup(method string) (string, bool) {
+	if value, ok := rules.exact[method]; ok {
+		return value, true
+	}
+	for _, rule := range rules.wildcards {
+		if strings.HasPrefix(method, rule.prefix) {
+			return rule.value, true
+		}
+	}
+	return "", false
+}
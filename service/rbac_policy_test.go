@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+func TestDefaultPolicyCoversAllRPCs(t *testing.T) {
+	t.Parallel()
+
+	policy, err := LoadPolicy("../rbac_policy.json")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterAuthServiceServer(grpcServer, &pb.UnimplementedAuthServiceServer{})
+	pb.RegisterUserAdminServiceServer(grpcServer, &pb.UnimplementedUserAdminServiceServer{})
+	pb.RegisterLaptopServiceServer(grpcServer, &pb.UnimplementedLaptopServiceServer{})
+	reflection.Register(grpcServer)
+
+	interceptor := NewAuthInterceptor(NewPasetoManager(TokenKey, TokenDuration), nil, nil, nil, policy)
+	require.NoError(t, interceptor.CheckCoverage(grpcServer.GetServiceInfo()))
+
+	// admin은 user의 permission을 상속한다
+	permission, ok := policy.Permission("/pcbook.LaptopService/SearchLaptop")
+	require.True(t, ok)
+	require.True(t, policy.HasPermission("user", permission))
+	require.True(t, policy.HasPermission("admin", permission))
+
+	permission, ok = policy.Permission("/pcbook.UserAdminService/DeleteUser")
+	require.True(t, ok)
+	require.False(t, policy.HasPermission("user", permission))
+	require.True(t, policy.HasPermission("admin", permission))
+
+	// rpc가 빠진 policy로는 교체할 수 없다
+	partial, err := ParsePolicy([]byte(`{"roles": {}, "rules": {"/pcbook.AuthService/*": "public"}}`))
+	require.NoError(t, err)
+	require.Error(t, interceptor.SetPolicy(partial))
+}
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParsePolicy([]byte(`{
+		"roles": {
+			"viewer": {"permissions": ["read"]},
+			"editor": {"inherits": ["viewer"], "permissions": ["write"]}
+		},
+		"rules": {
+			"*": "read",
+			"/pcbook.LaptopService/*": "write",
+			"/pcbook.LaptopService/SearchLaptop": "public"
+		}
+	}`))
+	require.NoError(t, err)
+
+	// 정확한 rule, 긴 wildcard, 짧은 wildcard 순서로 적용된다
+	permission, _ := policy.Permission("/pcbook.LaptopService/SearchLaptop")
+	require.Equal(t, PublicPermission, permission)
+	permission, _ = policy.Permission("/pcbook.LaptopService/CreateLaptop")
+	require.Equal(t, "write", permission)
+	permission, _ = policy.Permission("/pcbook.AuthService/Login")
+	require.Equal(t, "read", permission)
+	require.True(t, policy.HasPermission("editor", "read"))
+	require.False(t, policy.HasPermission("viewer", "write"))
+	require.False(t, policy.HasPermission("unknown", "read"))
+
+	invalid := []string{
+		`{"roles": {"a": {"inherits": ["b"]}, "b": {"inherits": ["a"]}}, "rules": {}}`,
+		`{"roles": {"a": {"inherits": ["missing"]}}, "rules": {}}`,
+		`{"roles": {"a": {"permissions": ["read"]}}, "rules": {"/pcbook.AuthService/Login": "write"}}`,
+		`{"roles": {}, "rules": {"/pcbook.*/Login": "public"}}`,
+		`{"roles": {}, "rules": {}, "unknown": true}`,
+	}
+	for _, data := range invalid {
+		_, err := ParsePolicy([]byte(data))
+		require.Error(t, err, data)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/redisutil"
+)
+
+// RedisLoginAttemptStore는 여러 서버가 로그인 실패 기록을 공유할 수 있게 redis에 저장한다
+type RedisLoginAttemptStore struct {
+	rm *redisutil.RedisManager
+}
+
+func NewRedisLoginAttemptStore(rm *redisutil.RedisManager) *RedisLoginAttemptStore {
+	return &RedisLoginAttemptStore{rm: rm}
+}
+
+func (store *RedisLoginAttemptStore) Reserve(key string, window time.Duration, delay func(failures int) time.Duration) (*LoginReservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	reservation, err := redisutil.ReserveLogin(ctx, store.rm, key, window, func(failures int64) time.Duration {
+		return delay(int(failures))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &LoginReservation{
+		Key:         key,
+		Failures:    int(reservation.Failures),
+		LockedUntil: reservation.LockedUntil,
+		Lock:        reservation.Lock,
+	}, nil
+}
+
+func (store *RedisLoginAttemptStore) Release(reservation *LoginReservation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	return redisutil.ReleaseLogin(ctx, store.rm, reservation.Key, reservation.Lock)
+}
+
+func (store *RedisLoginAttemptStore) LockedUntil(key string) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	return redisutil.LoginLockedUntil(ctx, store.rm, key)
+}
+
+func (store *RedisLoginAttemptStore) Reset(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	return redisutil.ResetLoginFailures(ctx, store.rm, key)
+}
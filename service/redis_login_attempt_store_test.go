@@ -0,0 +1,94 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisLoginAttemptStore(t *testing.T) {
+	t.Parallel()
+
+	server, rm := newTestRedis(t)
+	store := NewRedisLoginAttemptStore(rm)
+	policy := BackoffPolicy{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+	reserve := func(key string) *LoginReservation {
+		reservation, err := store.Reserve(key, policy.Window, policy.Delay)
+		require.NoError(t, err)
+		return reservation
+	}
+	lockedUntil := func(key string) time.Time {
+		until, err := store.LockedUntil(key)
+		require.NoError(t, err)
+		return until
+	}
+
+	require.Equal(t, 1, reserve("user:alice").Failures)
+	require.Equal(t, 2, reserve("user:alice").Failures)
+	require.True(t, lockedUntil("user:alice").IsZero())
+
+	// 허용된 횟수를 넘는 시도는 세면서 바로 잠근다
+	reservation := reserve("user:alice")
+	require.Equal(t, 3, reservation.Failures)
+	require.WithinDuration(t, time.Now().Add(time.Minute), reservation.Lock, time.Second)
+	require.True(t, reservation.Lock.Equal(lockedUntil("user:alice")))
+
+	// 잠긴 동안의 시도는 세지 않는다
+	locked := reserve("user:alice")
+	require.Zero(t, locked.Failures)
+	require.True(t, reservation.Lock.Equal(locked.LockedUntil))
+
+	// 잠금은 TTL로 풀리고 실패 횟수는 window 동안 남는다
+	server.FastForward(2 * time.Minute)
+	require.True(t, lockedUntil("user:alice").IsZero())
+	require.Equal(t, 4, reserve("user:alice").Failures)
+
+	// window 동안 실패가 없으면 실패 횟수는 TTL로 사라진다
+	server.FastForward(policy.Window + time.Minute)
+	require.Equal(t, 1, reserve("user:alice").Failures)
+
+	// Release는 센 시도를 되돌리고 그 시도가 건 잠금만 푼다
+	reserve("ip:10.0.0.1")
+	reserve("ip:10.0.0.1")
+	reservation = reserve("ip:10.0.0.1")
+	require.False(t, reservation.Lock.IsZero())
+	require.NoError(t, store.Release(reservation))
+	require.True(t, lockedUntil("ip:10.0.0.1").IsZero())
+	require.Equal(t, 3, reserve("ip:10.0.0.1").Failures)
+
+	// Reset은 실패 횟수와 잠금을 모두 지운다
+	require.False(t, lockedUntil("ip:10.0.0.1").IsZero())
+	require.NoError(t, store.Reset("ip:10.0.0.1"))
+	require.True(t, lockedUntil("ip:10.0.0.1").IsZero())
+	require.False(t, server.Exists("login:failures:ip:10.0.0.1"))
+	require.Equal(t, 1, reserve("ip:10.0.0.1").Failures)
+}
+
+func TestRedisLoginAttemptStoreConcurrent(t *testing.T) {
+	t.Parallel()
+
+	_, rm := newTestRedis(t)
+	throttle := NewLoginThrottle(NewRedisLoginAttemptStore(rm))
+	throttle.User = BackoffPolicy{FreeAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+
+	// 여러 서버가 같은 redis로 동시에 시도해도 잠금 전에 허용된 횟수를 넘지 않는다
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, retryAfter, err := throttle.Attempt("alice", "")
+			require.NoError(t, err)
+			if retryAfter == 0 {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(throttle.User.FreeAttempts+1), allowed.Load())
+}
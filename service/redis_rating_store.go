@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/redisutil"
+)
+
+const (
+	redisRequestTimeout = 5 * time.Second
+	redisRankingPage    = 50
+)
+
+// RedisRatingStore는 평가를 redis에 저장하고 평균 점수를 sorted set으로 정렬해 둔다
+// 평균이 같은 laptop은 sorted set의 순서(id 역순)를 따른다
+type RedisRatingStore struct {
+	rm     *redisutil.RedisManager
+	prefix string
+}
+
+// NewRedisRatingStore는 orgID organization의 평가를 저장한다
+func NewRedisRatingStore(rm *redisutil.RedisManager, orgID string) *RedisRatingStore {
+	return &RedisRatingStore{rm: rm, prefix: orgRedisPrefix(orgID)}
+}
+
+func (store *RedisRatingStore) Add(laptopID string, username string, score float64) (*Rating, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	stats, err := redisutil.AddRating(ctx, store.rm, store.prefix, laptopID, username, score)
+	if err != nil {
+		return nil, err
+	}
+	return toRating(stats), nil
+}
+
+func (store *RedisRatingStore) Find(laptopID string) (*Rating, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	stats, err := redisutil.FindRating(ctx, store.rm, store.prefix, laptopID)
+	if err != nil || stats == nil {
+		return nil, err
+	}
+	return toRating(stats), nil
+}
+
+func (store *RedisRatingStore) Delete(laptopID string, username string) (*Rating, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	stats, err := redisutil.DeleteRating(ctx, store.rm, store.prefix, laptopID, username)
+	if errors.Is(err, redisutil.ErrRatingNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toRating(stats), nil
+}
+
+func (store *RedisRatingStore) DeleteLaptop(laptopID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	return redisutil.DeleteLaptopRatings(ctx, store.rm, store.prefix, laptopID)
+}
+
+func (store *RedisRatingStore) ListByUser(username string) ([]*UserRating, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	entries, err := redisutil.ListUserRatings(ctx, store.rm, store.prefix, username)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make([]*UserRating, 0, len(entries))
+	for _, entry := range entries {
+		ratings = append(ratings, &UserRating{
+			LaptopID:  entry.LaptopID,
+			Username:  username,
+			Score:     entry.Score,
+			UpdatedAt: entry.UpdatedAt,
+		})
+	}
+
+	sort.Slice(ratings, func(i, j int) bool {
+		return ratings[i].UpdatedAt.After(ratings[j].UpdatedAt)
+	})
+
+	return ratings, nil
+}
+
+// Ranking은 sorted set을 page 단위로 읽으면서 평가 수가 부족한 laptop을 건너뛴다
+// page의 통계는 pipeline 한 번으로 읽는다
+func (store *RedisRatingStore) Ranking(ctx context.Context, minCount uint32, found func(laptopID string, rating *Rating) error) error {
+	for offset := int64(0); ; offset += redisRankingPage {
+		laptopIDs, err := redisutil.TopRatedLaptops(ctx, store.rm, store.prefix, offset, redisRankingPage)
+		if err != nil {
+			return err
+		}
+
+		ratings, err := redisutil.FindRatings(ctx, store.rm, store.prefix, laptopIDs)
+		if err != nil {
+			return err
+		}
+
+		for i, laptopID := range laptopIDs {
+			stats := ratings[i]
+			if stats == nil || stats.Count < minCount {
+				continue
+			}
+
+			err = found(laptopID, toRating(stats))
+			if errors.Is(err, ErrStopRanking) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(laptopIDs) < redisRankingPage {
+			return nil
+		}
+	}
+}
+
+func toRating(stats *redisutil.RatingStats) *Rating {
+	return &Rating{
+		Count:        stats.Count,
+		sum:          stats.Sum,
+		Distribution: stats.Distribution,
+	}
+}
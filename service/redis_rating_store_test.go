@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/JeongWoo-Seo/pcBook/redisutil"
+	"github.com/JeongWoo-Seo/pcBook/redisutil/redistest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisRatingStore(t *testing.T) {
+	t.Parallel()
+
+	_, rm := newTestRedis(t)
+	store := NewRedisRatingStore(rm, DefaultOrgID)
+
+	rating, err := store.Add("laptop-1", "alice", 4)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), rating.Count)
+
+	rating, err = store.Add("laptop-1", "alice", 9)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), rating.Count)
+	require.Equal(t, float64(9), rating.Average())
+
+	rating, err = store.Add("laptop-1", "bob", 5)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), rating.Count)
+	require.Equal(t, float64(7), rating.Average())
+
+	_, err = store.Add("laptop-2", "alice", 3)
+	require.NoError(t, err)
+
+	ratings, err := store.ListByUser("alice")
+	require.NoError(t, err)
+	require.Len(t, ratings, 2)
+	require.Equal(t, "laptop-2", ratings[0].LaptopID)
+	require.Equal(t, float64(9), ratings[1].Score)
+
+	rating, err = store.Find("laptop-1")
+	require.NoError(t, err)
+	require.Equal(t, map[float64]uint32{9: 1, 5: 1}, rating.Distribution)
+
+	rating, err = store.Delete("laptop-1", "bob")
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), rating.Count)
+	require.Equal(t, float64(9), rating.Average())
+	require.Equal(t, map[float64]uint32{9: 1}, rating.Distribution)
+
+	_, err = store.Delete("laptop-1", "bob")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	rating, err = store.Delete("laptop-2", "alice")
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), rating.Count)
+
+	rating, err = store.Find("laptop-2")
+	require.NoError(t, err)
+	require.Nil(t, rating)
+
+	ratings, err = store.ListByUser("alice")
+	require.NoError(t, err)
+	require.Len(t, ratings, 1)
+
+	// 다른 organization은 같은 redis를 써도 평가를 볼 수 없다
+	rating, err = NewRedisRatingStore(rm, "acme").Find("laptop-1")
+	require.NoError(t, err)
+	require.Nil(t, rating)
+
+	// 삭제된 laptop의 평가는 사용자 목록과 leaderboard에서도 빠진다
+	require.NoError(t, store.DeleteLaptop("laptop-1"))
+	rating, err = store.Find("laptop-1")
+	require.NoError(t, err)
+	require.Nil(t, rating)
+	ratings, err = store.ListByUser("alice")
+	require.NoError(t, err)
+	require.Empty(t, ratings)
+	err = store.Ranking(context.Background(), 0, func(laptopID string, rating *Rating) error {
+		return fmt.Errorf("laptop %s is still ranked", laptopID)
+	})
+	require.NoError(t, err)
+}
+
+func TestRedisRatingStoreConcurrent(t *testing.T) {
+	t.Parallel()
+
+	_, rm := newTestRedis(t)
+	store := NewRedisRatingStore(rm, DefaultOrgID)
+
+	// 같은 laptop을 동시에 평가해도 통계가 어긋나지 않는다
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for score := 1; score <= 5; score++ {
+				_, err := store.Add("laptop-1", fmt.Sprintf("user-%d", i), float64(score))
+				require.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	rating, err := store.Find("laptop-1")
+	require.NoError(t, err)
+	require.Equal(t, uint32(8), rating.Count)
+	require.Equal(t, float64(5), rating.Average())
+	require.Equal(t, map[float64]uint32{5: 8}, rating.Distribution)
+}
+
+func TestRedisRatingStoreRanking(t *testing.T) {
+	t.Parallel()
+
+	_, rm := newTestRedis(t)
+	store := NewRedisRatingStore(rm, DefaultOrgID)
+	scores := []struct {
+		laptopID string
+		username string
+		score    float64
+	}{
+		{"laptop-1", "alice", 6},
+		{"laptop-2", "alice", 9},
+		{"laptop-2", "bob", 7},
+		{"laptop-3", "alice", 10},
+		{"laptop-4", "alice", 8},
+		{"laptop-4", "bob", 8},
+		{"laptop-1", "alice", 9}, // 다시 평가하면 순위도 갱신된다
+	}
+	for _, s := range scores {
+		_, err := store.Add(s.laptopID, s.username, s.score)
+		require.NoError(t, err)
+	}
+	// page 경계를 넘도록 평가 수가 부족한 laptop을 채운다
+	for i := 0; i < 2*redisRankingPage; i++ {
+		_, err := store.Add(fmt.Sprintf("filler-%03d", i), "alice", 9.5)
+		require.NoError(t, err)
+	}
+
+	ranking := func(minCount uint32, limit int) []string {
+		laptopIDs := []string{}
+		err := store.Ranking(context.Background(), minCount, func(laptopID string, rating *Rating) error {
+			require.GreaterOrEqual(t, rating.Count, minCount)
+			laptopIDs = append(laptopIDs, laptopID)
+			if len(laptopIDs) == limit {
+				return ErrStopRanking
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return laptopIDs
+	}
+
+	// 평균이 같으면 id 역순이다
+	require.Equal(t, []string{"laptop-4", "laptop-2"}, ranking(2, 10))
+	require.Equal(t, []string{"laptop-3", "filler-099"}, ranking(0, 2))
+	require.Len(t, ranking(1, 1000), 4+2*redisRankingPage)
+}
+
+// newTestRedis는 테스트가 끝나면 닫히는 redis 서버와 그 서버에 연결한 RedisManager를 만든다
+func newTestRedis(t *testing.T) (*redistest.Server, *redisutil.RedisManager) {
+	server := redistest.NewServer()
+	t.Cleanup(server.Close)
+
+	rm := redisutil.NewRedisManager(server.Addr)
+	t.Cleanup(func() { rm.Client.Close() })
+
+	return server, rm
+}
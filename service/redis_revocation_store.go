@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/redisutil"
+)
+
+// RedisRevocationStore는 여러 서버가 폐기 기록을 공유할 수 있게 redis에 저장한다
+type RedisRevocationStore struct {
+	rm *redisutil.RedisManager
+}
+
+func NewRedisRevocationStore(rm *redisutil.RedisManager) *RedisRevocationStore {
+	return &RedisRevocationStore{rm: rm}
+}
+
+func (store *RedisRevocationStore) RevokeToken(tokenID string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	return redisutil.RevokeToken(ctx, store.rm, tokenID, expiresAt)
+}
+
+func (store *RedisRevocationStore) RevokeUser(username string, issuedBefore time.Time, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	return redisutil.RevokeUserTokens(ctx, store.rm, username, issuedBefore, expiresAt)
+}
+
+func (store *RedisRevocationStore) IsRevoked(payload *UserPayload) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRequestTimeout)
+	defer cancel()
+
+	return redisutil.IsTokenRevoked(ctx, store.rm, payload.Jti, payload.Username, payload.IssuedAt)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/o1egl/paseto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisRevocationStore(t *testing.T) {
+	t.Parallel()
+
+	server, rm := newTestRedis(t)
+	store := NewRedisRevocationStore(rm)
+	now := time.Now()
+	newPayload := func(tokenID string, username string, issuedAt time.Time) *UserPayload {
+		return &UserPayload{
+			JSONToken: paseto.JSONToken{Jti: tokenID, IssuedAt: issuedAt},
+			Username:  username,
+		}
+	}
+	isRevoked := func(payload *UserPayload) bool {
+		revoked, err := store.IsRevoked(payload)
+		require.NoError(t, err)
+		return revoked
+	}
+
+	require.NoError(t, store.RevokeToken("token-1", now.Add(time.Hour)))
+	require.NoError(t, store.RevokeToken("token-2", now.Add(-time.Second)))
+
+	require.True(t, isRevoked(newPayload("token-1", "alice", now)))
+	require.False(t, isRevoked(newPayload("token-2", "alice", now)))
+	require.False(t, isRevoked(newPayload("", "alice", now)))
+
+	// 폐기 시각 이전에 발급된 token만 폐기된다
+	require.NoError(t, store.RevokeUser("bob", now, now.Add(2*time.Hour)))
+
+	require.True(t, isRevoked(newPayload("token-3", "bob", now.Add(-time.Minute))))
+	require.True(t, isRevoked(newPayload("token-3", "bob", now)))
+	require.False(t, isRevoked(newPayload("token-4", "bob", now.Add(time.Minute))))
+
+	// 기록은 token이 만료되는 시각에 TTL로 사라진다
+	server.FastForward(time.Hour + time.Minute)
+	require.False(t, isRevoked(newPayload("token-1", "alice", now)))
+	require.True(t, isRevoked(newPayload("token-3", "bob", now.Add(-time.Minute))))
+
+	server.FastForward(time.Hour)
+	require.False(t, isRevoked(newPayload("token-3", "bob", now.Add(-time.Minute))))
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const RefreshTokenDuration = 7 * 24 * time.Hour
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used")
+)
+
+// RefreshTokenStore는 refresh token의 hash만 저장한다
+// 한 번 로그인해서 이어지는 token들은 같은 family에 속하며
+// 이미 사용한 token이 다시 오면 탈취된 것으로 보고 family 전체를 폐기한다
+type RefreshTokenStore interface {
+	Create(token *RefreshToken) error
+	Find(hash string) (*RefreshToken, error)
+	Rotate(hash string, next *RefreshToken) (*RefreshToken, error)
+	RevokeFamily(familyID string) error
+	RevokeUser(username string) error
+}
+
+// TwoFactor는 family를 시작한 로그인이 2단계 인증을 마쳤는지 나타낸다
+type RefreshToken struct {
+	Hash      string
+	FamilyID  string
+	Username  string
+	TwoFactor bool
+	Used      bool
+	Revoked   bool
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type InmemoryRefreshTokenStore struct {
+	mutax    sync.RWMutex
+	tokens   map[string]*RefreshToken
+	families map[string][]string
+}
+
+func NewInMemoryRefreshTokenStore() *InmemoryRefreshTokenStore {
+	return &InmemoryRefreshTokenStore{
+		tokens:   make(map[string]*RefreshToken),
+		families: make(map[string][]string),
+	}
+}
+
+// NewRefreshToken은 client에 보낼 token과 store에 저장할 정보를 만든다
+func NewRefreshToken(familyID string, username string, duration time.Duration) (string, *RefreshToken, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, fmt.Errorf("can not generate refresh token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	now := time.Now()
+	return token, &RefreshToken{
+		Hash:      HashRefreshToken(token),
+		FamilyID:  familyID,
+		Username:  username,
+		CreatedAt: now,
+		ExpiresAt: now.Add(duration),
+	}, nil
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (store *InmemoryRefreshTokenStore) Create(token *RefreshToken) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	if store.tokens[token.Hash] != nil {
+		return ErrAlreadyExists
+	}
+
+	store.save(token)
+	return nil
+}
+
+func (store *InmemoryRefreshTokenStore) Find(hash string) (*RefreshToken, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	token := store.tokens[hash]
+	if token == nil {
+		return nil, nil
+	}
+	return token.Clone(), nil
+}
+
+// Rotate는 hash에 해당하는 token을 사용 처리하고 같은 family로 next를 저장한다
+func (store *InmemoryRefreshTokenStore) Rotate(hash string, next *RefreshToken) (*RefreshToken, error) {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	token := store.tokens[hash]
+	if token == nil || token.Revoked || time.Now().After(token.ExpiresAt) {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	if token.Used {
+		store.revokeFamily(token.FamilyID)
+		return nil, ErrRefreshTokenReused
+	}
+
+	token.Used = true
+	other := next.Clone()
+	other.FamilyID = token.FamilyID
+	other.Username = token.Username
+	other.TwoFactor = token.TwoFactor
+	store.save(other)
+
+	return token.Clone(), nil
+}
+
+func (store *InmemoryRefreshTokenStore) RevokeFamily(familyID string) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	if len(store.families[familyID]) == 0 {
+		return ErrNotFound
+	}
+
+	store.revokeFamily(familyID)
+	return nil
+}
+
+// RevokeUser는 사용자의 모든 family를 폐기한다
+func (store *InmemoryRefreshTokenStore) RevokeUser(username string) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	for familyID, hashes := range store.families {
+		if store.tokens[hashes[0]].Username == username {
+			store.revokeFamily(familyID)
+		}
+	}
+	return nil
+}
+
+// RemoveExpired는 만료된 family를 지운다
+// family의 마지막 token이 만료되기 전에는 재사용 감지를 위해 사용한 token도 남겨둔다
+func (store *InmemoryRefreshTokenStore) RemoveExpired() int {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	removed := 0
+	now := time.Now()
+	for familyID, hashes := range store.families {
+		latest := store.tokens[hashes[len(hashes)-1]]
+		if now.Before(latest.ExpiresAt) {
+			continue
+		}
+
+		for _, hash := range hashes {
+			delete(store.tokens, hash)
+		}
+		delete(store.families, familyID)
+		removed++
+	}
+
+	return removed
+}
+
+func (store *InmemoryRefreshTokenStore) StartCleanup(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if removed := store.RemoveExpired(); removed > 0 {
+					log.Printf("removed %d expired refresh token families", removed)
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (store *InmemoryRefreshTokenStore) save(token *RefreshToken) {
+	other := token.Clone()
+	store.tokens[other.Hash] = other
+	store.families[other.FamilyID] = append(store.families[other.FamilyID], other.Hash)
+}
+
+func (store *InmemoryRefreshTokenStore) revokeFamily(familyID string) {
+	for _, hash := range store.families[familyID] {
+		store.tokens[hash].Revoked = true
+	}
+}
+
+func (token *RefreshToken) Clone() *RefreshToken {
+	other := *token
+	return &other
+}
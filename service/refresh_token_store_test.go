@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryRefreshTokenStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryRefreshTokenStore()
+
+	first, info, err := NewRefreshToken("family-1", "alice", time.Hour)
+	require.NoError(t, err)
+	require.NotEqual(t, first, info.Hash)
+	require.NoError(t, store.Create(info))
+
+	second, next, err := NewRefreshToken("", "", time.Hour)
+	require.NoError(t, err)
+
+	previous, err := store.Rotate(HashRefreshToken(first), next)
+	require.NoError(t, err)
+	require.Equal(t, "alice", previous.Username)
+	require.Equal(t, "family-1", previous.FamilyID)
+
+	// 이미 교체된 token을 다시 사용하면 family 전체가 폐기된다
+	_, next, err = NewRefreshToken("", "", time.Hour)
+	require.NoError(t, err)
+	_, err = store.Rotate(HashRefreshToken(first), next)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	_, err = store.Rotate(HashRefreshToken(second), next)
+	require.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+	_, err = store.Rotate(HashRefreshToken("unknown"), next)
+	require.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+	expired, info, err := NewRefreshToken("family-2", "bob", -time.Second)
+	require.NoError(t, err)
+	require.NoError(t, store.Create(info))
+
+	_, err = store.Rotate(HashRefreshToken(expired), next)
+	require.ErrorIs(t, err, ErrRefreshTokenInvalid)
+
+	require.Equal(t, 1, store.RemoveExpired())
+	require.ErrorIs(t, store.RevokeFamily("family-2"), ErrNotFound)
+	require.NoError(t, store.RevokeFamily("family-1"))
+}
+
+func TestInMemoryRefreshTokenStoreRevokeUser(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryRefreshTokenStore()
+
+	alice, info, err := NewRefreshToken("family-1", "alice", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, store.Create(info))
+
+	bob, info, err := NewRefreshToken("family-2", "bob", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, store.Create(info))
+
+	require.NoError(t, store.RevokeUser("alice"))
+
+	token, err := store.Find(HashRefreshToken(alice))
+	require.NoError(t, err)
+	require.True(t, token.Revoked)
+
+	token, err = store.Find(HashRefreshToken(bob))
+	require.NoError(t, err)
+	require.False(t, token.Revoked)
+
+	token, err = store.Find(HashRefreshToken("unknown"))
+	require.NoError(t, err)
+	require.Nil(t, token)
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"github.com/google/uuid"
+)
+
+var ErrOwnReview = errors.New("can not vote for your own review")
+
+type ReviewStore interface {
+	Create(review *Review) (*Review, error)
+	Find(reviewID string) (*Review, error)
+	List(laptopID string, options ReviewListOptions) ([]*Review, int, error)
+	Vote(reviewID string, username string) (*Review, error)
+	SetState(reviewID string, status pb.Review_State) (*Review, pb.Review_State, error)
+	DeleteLaptop(laptopID string) error
+}
+
+// Review는 사용자가 laptop에 남긴 평가 글이며 사용자당 laptop 하나에 하나만 작성할 수 있다
+type Review struct {
+	ID        string
+	LaptopID  string
+	Author    string
+	Title     string
+	Body      string
+	Score     float64
+	Helpful   uint32
+	State     pb.Review_State
+	CreatedAt time.Time
+}
+
+// ReviewListOptions의 Offset, Limit은 정렬된 결과에서 가져올 범위이다
+// Limit이 0이면 끝까지 반환한다
+type ReviewListOptions struct {
+	Sort   pb.Review_Sort
+	Offset int
+	Limit  int
+}
+
+type InmemoryReviewStore struct {
+	mutax   sync.RWMutex
+	reviews map[string]*Review
+	authors map[string]map[string]string // laptop id -> author -> review id
+	voters  map[string]map[string]bool   // review id -> helpful 투표한 사용자
+}
+
+func NewInMemoryReviewStore() *InmemoryReviewStore {
+	return &InmemoryReviewStore{
+		reviews: make(map[string]*Review),
+		authors: make(map[string]map[string]string),
+		voters:  make(map[string]map[string]bool),
+	}
+}
+
+func (store *InmemoryReviewStore) Create(review *Review) (*Review, error) {
+	reviewID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create review id : %w", err)
+	}
+
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	authors := store.authors[review.LaptopID]
+	if authors == nil {
+		authors = make(map[string]string)
+		store.authors[review.LaptopID] = authors
+	}
+	if authors[review.Author] != "" {
+		return nil, ErrAlreadyExists
+	}
+
+	other := review.Clone()
+	other.ID = reviewID.String()
+	other.Helpful = 0
+	other.State = pb.Review_PUBLISHED
+	other.CreatedAt = time.Now()
+
+	store.reviews[other.ID] = other
+	authors[other.Author] = other.ID
+
+	return other.Clone(), nil
+}
+
+func (store *InmemoryReviewStore) Find(reviewID string) (*Review, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	review := store.reviews[reviewID]
+	if review == nil {
+		return nil, nil
+	}
+
+	return review.Clone(), nil
+}
+
+// List는 숨겨지지 않은 review를 정렬해 요청한 범위와 전체 개수를 반환한다
+func (store *InmemoryReviewStore) List(laptopID string, options ReviewListOptions) ([]*Review, int, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	reviews := []*Review{}
+	for _, reviewID := range store.authors[laptopID] {
+		review := store.reviews[reviewID]
+		if review.State == pb.Review_HIDDEN {
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+
+	sort.Slice(reviews, func(i, j int) bool {
+		a, b := reviews[i], reviews[j]
+		if options.Sort == pb.Review_MOST_HELPFUL && a.Helpful != b.Helpful {
+			return a.Helpful > b.Helpful
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	total := len(reviews)
+	start := min(options.Offset, total)
+	end := total
+	if options.Limit > 0 {
+		end = min(start+options.Limit, total)
+	}
+
+	page := make([]*Review, 0, end-start)
+	for _, review := range reviews[start:end] {
+		page = append(page, review.Clone())
+	}
+
+	return page, total, nil
+}
+
+// Vote는 사용자당 한 번만 helpful 투표를 허용한다
+func (store *InmemoryReviewStore) Vote(reviewID string, username string) (*Review, error) {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	review := store.reviews[reviewID]
+	if review == nil || review.State == pb.Review_HIDDEN {
+		return nil, ErrNotFound
+	}
+	if review.Author == username {
+		return nil, ErrOwnReview
+	}
+
+	voters := store.voters[reviewID]
+	if voters == nil {
+		voters = make(map[string]bool)
+		store.voters[reviewID] = voters
+	}
+	if voters[username] {
+		return nil, ErrAlreadyExists
+	}
+
+	voters[username] = true
+	review.Helpful++
+
+	return review.Clone(), nil
+}
+
+// SetState는 바뀐 review와 함께 바뀌기 전 상태를 반환한다
+func (store *InmemoryReviewStore) SetState(reviewID string, status pb.Review_State) (*Review, pb.Review_State, error) {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	review := store.reviews[reviewID]
+	if review == nil {
+		return nil, 0, ErrNotFound
+	}
+
+	previous := review.State
+	review.State = status
+	return review.Clone(), previous, nil
+}
+
+// DeleteLaptop은 laptop의 모든 review와 투표를 지운다
+func (store *InmemoryReviewStore) DeleteLaptop(laptopID string) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	for _, reviewID := range store.authors[laptopID] {
+		delete(store.reviews, reviewID)
+		delete(store.voters, reviewID)
+	}
+	delete(store.authors, laptopID)
+
+	return nil
+}
+
+func (review *Review) Clone() *Review {
+	other := *review
+	return &other
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryReviewStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryReviewStore()
+
+	first, err := store.Create(&Review{LaptopID: "laptop-1", Author: "alice", Title: "good", Score: 8})
+	require.NoError(t, err)
+	require.NotEmpty(t, first.ID)
+	require.Equal(t, pb.Review_PUBLISHED, first.State)
+
+	_, err = store.Create(&Review{LaptopID: "laptop-1", Author: "alice", Title: "again", Score: 2})
+	require.ErrorIs(t, err, ErrAlreadyExists)
+
+	second, err := store.Create(&Review{LaptopID: "laptop-1", Author: "bob", Title: "bad", Score: 3})
+	require.NoError(t, err)
+
+	_, err = store.Vote(first.ID, "alice")
+	require.ErrorIs(t, err, ErrOwnReview)
+
+	voted, err := store.Vote(first.ID, "bob")
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), voted.Helpful)
+
+	_, err = store.Vote(first.ID, "bob")
+	require.ErrorIs(t, err, ErrAlreadyExists)
+
+	reviews, total, err := store.List("laptop-1", ReviewListOptions{Sort: pb.Review_NEWEST})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Equal(t, second.ID, reviews[0].ID)
+
+	reviews, _, err = store.List("laptop-1", ReviewListOptions{Sort: pb.Review_MOST_HELPFUL, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	require.Equal(t, first.ID, reviews[0].ID)
+
+	reviews, _, err = store.List("laptop-1", ReviewListOptions{Sort: pb.Review_MOST_HELPFUL, Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, second.ID, reviews[0].ID)
+
+	_, previous, err := store.SetState(second.ID, pb.Review_HIDDEN)
+	require.NoError(t, err)
+	require.Equal(t, pb.Review_PUBLISHED, previous)
+
+	reviews, total, err = store.List("laptop-1", ReviewListOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, first.ID, reviews[0].ID)
+
+	_, err = store.Vote(second.ID, "alice")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, _, err = store.SetState("unknown", pb.Review_APPROVED)
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// RevocationStore는 만료 전에 폐기한 access token을 기록한다
+// 각 기록은 해당 token이 어차피 만료되는 시각까지만 유지된다
+type RevocationStore interface {
+	RevokeToken(tokenID string, expiresAt time.Time) error
+	RevokeUser(username string, issuedBefore time.Time, expiresAt time.Time) error
+	IsRevoked(payload *UserPayload) (bool, error)
+}
+
+type InmemoryRevocationStore struct {
+	mutax  sync.RWMutex
+	tokens map[string]time.Time
+	users  map[string]*userRevocation
+}
+
+// userRevocation은 issuedBefore 이전에 발급된 사용자의 모든 token을 폐기한다
+type userRevocation struct {
+	issuedBefore time.Time
+	expiresAt    time.Time
+}
+
+func NewInMemoryRevocationStore() *InmemoryRevocationStore {
+	return &InmemoryRevocationStore{
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]*userRevocation),
+	}
+}
+
+func (store *InmemoryRevocationStore) RevokeToken(tokenID string, expiresAt time.Time) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	store.tokens[tokenID] = expiresAt
+	return nil
+}
+
+func (store *InmemoryRevocationStore) RevokeUser(username string, issuedBefore time.Time, expiresAt time.Time) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	store.users[username] = &userRevocation{
+		issuedBefore: issuedBefore,
+		expiresAt:    expiresAt,
+	}
+	return nil
+}
+
+// IsRevoked는 token의 iat가 초 단위이므로 폐기한 같은 초에 발급된 token도 폐기된 것으로 본다
+func (store *InmemoryRevocationStore) IsRevoked(payload *UserPayload) (bool, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	now := time.Now()
+	if expiresAt, ok := store.tokens[payload.Jti]; ok && payload.Jti != "" && now.Before(expiresAt) {
+		return true, nil
+	}
+
+	revocation := store.users[payload.Username]
+	if revocation != nil && now.Before(revocation.expiresAt) && !payload.IssuedAt.After(revocation.issuedBefore) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (store *InmemoryRevocationStore) RemoveExpired() int {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	removed := 0
+	now := time.Now()
+	for tokenID, expiresAt := range store.tokens {
+		if !now.Before(expiresAt) {
+			delete(store.tokens, tokenID)
+			removed++
+		}
+	}
+	for username, revocation := range store.users {
+		if !now.Before(revocation.expiresAt) {
+			delete(store.users, username)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+func (store *InmemoryRevocationStore) StartCleanup(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if removed := store.RemoveExpired(); removed > 0 {
+					log.Printf("removed %d expired token revocations", removed)
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
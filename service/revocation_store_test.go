@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/o1egl/paseto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryRevocationStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryRevocationStore()
+	now := time.Now()
+	newPayload := func(tokenID string, username string, issuedAt time.Time) *UserPayload {
+		return &UserPayload{
+			JSONToken: paseto.JSONToken{Jti: tokenID, IssuedAt: issuedAt},
+			Username:  username,
+		}
+	}
+
+	require.NoError(t, store.RevokeToken("token-1", now.Add(time.Hour)))
+	require.NoError(t, store.RevokeToken("token-2", now.Add(-time.Second)))
+
+	revoked, err := store.IsRevoked(newPayload("token-1", "alice", now))
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	revoked, err = store.IsRevoked(newPayload("token-2", "alice", now))
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// 폐기 시각 이전에 발급된 token만 폐기된다
+	require.NoError(t, store.RevokeUser("bob", now, now.Add(time.Hour)))
+
+	revoked, err = store.IsRevoked(newPayload("token-3", "bob", now.Add(-time.Minute)))
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	revoked, err = store.IsRevoked(newPayload("token-4", "bob", now.Add(time.Minute)))
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	require.Equal(t, 1, store.RemoveExpired())
+}
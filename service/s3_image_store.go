@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/s3util"
+)
+
+const (
+	DefaultS3MultipartThreshold = 512 << 10
+	DefaultS3PartSize           = s3util.MinPartSize
+	s3RequestTimeout            = 30 * time.Second
+)
+
+// S3ImageStore는 이미지 blob을 S3 호환 object storage에 저장한다
+// threshold보다 큰 이미지는 multipart upload로 partSize씩 나누어 보낸다
+type S3ImageStore struct {
+	imageIndex
+	client    *s3util.Client
+	threshold int
+	partSize  int
+
+	// KeyPrefix는 같은 bucket을 쓰는 organization의 blob이 섞이지 않도록 object key 앞에 붙는다
+	KeyPrefix string
+}
+
+// NewS3ImageStore는 S3가 거부하지 않도록 partSize를 최소 s3util.MinPartSize로 맞춘다
+func NewS3ImageStore(client *s3util.Client, threshold int, partSize int) *S3ImageStore {
+	if threshold <= 0 {
+		threshold = DefaultS3MultipartThreshold
+	}
+
+	return &S3ImageStore{
+		imageIndex: newImageIndex(),
+		client:     client,
+		threshold:  threshold,
+		partSize:   max(partSize, s3util.MinPartSize),
+	}
+}
+
+func (store *S3ImageStore) Save(image *ImageInfo, imageData bytes.Buffer) (string, error) {
+	return store.save(image, func() (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+		defer cancel()
+
+		key := store.KeyPrefix + blobName(image.Digest, image.Type)
+		contentType := mime.TypeByExtension("." + strings.TrimPrefix(image.Type, "."))
+
+		var err error
+		if imageData.Len() > store.threshold {
+			err = store.putMultipart(ctx, key, contentType, imageData.Bytes())
+		} else {
+			err = store.client.PutObject(ctx, key, imageData.Bytes(), contentType)
+		}
+		if err != nil {
+			return "", fmt.Errorf("can not upload image to s3: %w", err)
+		}
+
+		return key, nil
+	})
+}
+
+func (store *S3ImageStore) Delete(imageID string) error {
+	return store.delete(imageID, store.deleteObject)
+}
+
+// ListBlobs는 KeyPrefix 바로 아래에서 digest 이름을 가진 object만 반환한다
+// 다른 organization의 prefix 아래 object는 이름이 맞지 않아 제외된다
+func (store *S3ImageStore) ListBlobs() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+
+	keys, err := store.client.ListObjects(ctx, store.KeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("can not list images in s3: %w", err)
+	}
+
+	blobs := []string{}
+	for _, key := range keys {
+		if blobNamePattern.MatchString(strings.TrimPrefix(key, store.KeyPrefix)) {
+			blobs = append(blobs, key)
+		}
+	}
+
+	return blobs, nil
+}
+
+// RemoveBlob은 index에서 참조하지 않는 object만 삭제한다
+func (store *S3ImageStore) RemoveBlob(key string) error {
+	return store.removeOrphan(key, store.deleteObject)
+}
+
+func (store *S3ImageStore) deleteObject(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+
+	err := store.client.DeleteObject(ctx, key)
+	if err != nil && !errors.Is(err, s3util.ErrNoSuchKey) {
+		return fmt.Errorf("can not delete image from s3: %w", err)
+	}
+	return nil
+}
+
+func (store *S3ImageStore) putMultipart(ctx context.Context, key string, contentType string, data []byte) error {
+	uploadID, err := store.client.CreateMultipartUpload(ctx, key, contentType)
+	if err != nil {
+		return err
+	}
+
+	parts := []s3util.CompletedPart{}
+	for offset := 0; offset < len(data); offset += store.partSize {
+		end := min(offset+store.partSize, len(data))
+		partNumber := len(parts) + 1
+
+		etag, err := store.client.UploadPart(ctx, key, uploadID, partNumber, data[offset:end])
+		if err != nil {
+			store.abortMultipart(key, uploadID)
+			return err
+		}
+		parts = append(parts, s3util.CompletedPart{PartNumber: partNumber, ETag: etag})
+	}
+
+	err = store.client.CompleteMultipartUpload(ctx, key, uploadID, parts)
+	if err != nil {
+		store.abortMultipart(key, uploadID)
+		return err
+	}
+
+	return nil
+}
+
+func (store *S3ImageStore) abortMultipart(key string, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s3RequestTimeout)
+	defer cancel()
+
+	err := store.client.AbortMultipartUpload(ctx, key, uploadID)
+	if err != nil {
+		log.Printf("can not abort multipart upload %s: %v", uploadID, err)
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/JeongWoo-Seo/pcBook/s3util"
+	"github.com/JeongWoo-Seo/pcBook/s3util/s3test"
+	"github.com/JeongWoo-Seo/pcBook/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3ImageStore(t *testing.T) {
+	t.Parallel()
+
+	server := s3test.NewServer("us-east-1", "access", "secret")
+	t.Cleanup(server.Close)
+
+	client, err := s3util.NewClient(server.URL, "us-east-1", "images", "access", "secret")
+	require.NoError(t, err)
+
+	store := NewS3ImageStore(client, 1024, 0)
+
+	small := bytes.Repeat([]byte("a"), 100)
+	large := bytes.Repeat([]byte("b"), 2500)
+
+	smallID, err := store.Save(&ImageInfo{LaptopID: "laptop-1", Type: ".png", Digest: digestOf(small)}, *bytes.NewBuffer(small))
+	require.NoError(t, err)
+	largeID, err := store.Save(&ImageInfo{LaptopID: "laptop-1", Type: ".jpg", Digest: digestOf(large)}, *bytes.NewBuffer(large))
+	require.NoError(t, err)
+	sharedID, err := store.Save(&ImageInfo{LaptopID: "laptop-2", Type: ".jpg", Digest: digestOf(large)}, *bytes.NewBuffer(large))
+	require.NoError(t, err)
+
+	require.Equal(t, 2, server.ObjectCount())
+	require.Equal(t, 1, server.MultipartCompleted)
+	require.Equal(t, 1, server.PartsCompleted)
+
+	image, err := store.Find(largeID)
+	require.NoError(t, err)
+	data, ok := server.Object("images", image.Path)
+	require.True(t, ok)
+	require.Equal(t, large, data)
+
+	require.NoError(t, store.Delete(largeID))
+	_, ok = server.Object("images", image.Path)
+	require.True(t, ok)
+
+	require.NoError(t, store.Delete(sharedID))
+	require.NoError(t, store.Delete(smallID))
+	require.Equal(t, 0, server.ObjectCount())
+}
+
+// part는 threshold와 상관없이 S3 최소 크기 이상으로 나뉘고 마지막 part만 작을 수 있다
+func TestS3ImageStorePartSize(t *testing.T) {
+	t.Parallel()
+
+	server := s3test.NewServer("us-east-1", "access", "secret")
+	t.Cleanup(server.Close)
+
+	client, err := s3util.NewClient(server.URL, "us-east-1", "images", "access", "secret")
+	require.NoError(t, err)
+
+	store := NewS3ImageStore(client, 1024, 1024)
+
+	data := bytes.Repeat([]byte("c"), 2*s3util.MinPartSize+100)
+	imageID, err := store.Save(&ImageInfo{LaptopID: "laptop-1", Type: ".jpg", Digest: digestOf(data)}, *bytes.NewBuffer(data))
+	require.NoError(t, err)
+	require.Equal(t, 3, server.PartsCompleted)
+
+	image, err := store.Find(imageID)
+	require.NoError(t, err)
+	stored, ok := server.Object("images", image.Path)
+	require.True(t, ok)
+	require.Equal(t, data, stored)
+}
+
+// 같은 이미지도 organization마다 다른 key에 저장되어 한쪽에서 지워도 다른 쪽 blob은 남는다
+func TestS3ImageStoreKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	server := s3test.NewServer("us-east-1", "access", "secret")
+	t.Cleanup(server.Close)
+
+	client, err := s3util.NewClient(server.URL, "us-east-1", "images", "access", "secret")
+	require.NoError(t, err)
+
+	defaultStore := NewS3ImageStore(client, 1024, 0)
+	acmeStore := NewS3ImageStore(client, 1024, 0)
+	acmeStore.KeyPrefix = "orgs/acme/"
+
+	data := bytes.Repeat([]byte("a"), 100)
+	defaultID, err := defaultStore.Save(&ImageInfo{LaptopID: "laptop-1", Type: ".png", Digest: digestOf(data)}, *bytes.NewBuffer(data))
+	require.NoError(t, err)
+	acmeID, err := acmeStore.Save(&ImageInfo{LaptopID: "laptop-1", Type: ".png", Digest: digestOf(data)}, *bytes.NewBuffer(data))
+	require.NoError(t, err)
+	require.Equal(t, 2, server.ObjectCount())
+
+	image, err := acmeStore.Find(acmeID)
+	require.NoError(t, err)
+	require.Equal(t, "orgs/acme/"+blobName(digestOf(data), ".png"), image.Path)
+
+	require.NoError(t, acmeStore.Delete(acmeID))
+	image, err = defaultStore.Find(defaultID)
+	require.NoError(t, err)
+	_, ok := server.Object("images", image.Path)
+	require.True(t, ok)
+}
+
+func TestS3ImageStoreInvalidCredentials(t *testing.T) {
+	t.Parallel()
+
+	server := s3test.NewServer("us-east-1", "access", "secret")
+	t.Cleanup(server.Close)
+
+	client, err := s3util.NewClient(server.URL, "us-east-1", "images", "access", "wrong")
+	require.NoError(t, err)
+
+	store := NewS3ImageStore(client, 0, 0)
+
+	data := []byte("image")
+	_, err = store.Save(&ImageInfo{LaptopID: "laptop-1", Type: ".png", Digest: digestOf(data)}, *bytes.NewBuffer(data))
+	require.Error(t, err)
+	require.Equal(t, 0, server.ObjectCount())
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// GC는 KeyPrefix 아래의 orphan object만 지우고 다른 organization의 object는 건드리지 않는다
+func TestS3ImageStoreGarbageCollector(t *testing.T) {
+	t.Parallel()
+
+	server := s3test.NewServer("us-east-1", "access", "secret")
+	server.MaxKeys = 1
+	t.Cleanup(server.Close)
+
+	client, err := s3util.NewClient(server.URL, "us-east-1", "images", "access", "secret")
+	require.NoError(t, err)
+
+	laptopStore := NewInMemoryLaptopStore()
+	laptop := util.NewLaptop()
+	require.NoError(t, laptopStore.Save(laptop))
+
+	store := NewS3ImageStore(client, 0, 0)
+	data := []byte("kept")
+	imageID, err := store.Save(&ImageInfo{LaptopID: laptop.GetId(), Type: ".png", Digest: digestOf(data)}, *bytes.NewBuffer(data))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	orphan := blobName(digestOf([]byte("orphan")), ".png")
+	other := "orgs/acme/" + orphan
+	require.NoError(t, client.PutObject(ctx, orphan, []byte("orphan"), "image/png"))
+	require.NoError(t, client.PutObject(ctx, other, []byte("orphan"), "image/png"))
+	require.NoError(t, client.PutObject(ctx, "README", []byte("not a blob"), "text/plain"))
+
+	gc := NewImageGarbageCollector(laptopStore, store)
+	report, err := gc.Collect(false)
+	require.NoError(t, err)
+	require.Equal(t, []string{orphan}, report.OrphanFiles)
+	require.Empty(t, report.MissingFiles)
+
+	_, ok := server.Object("images", orphan)
+	require.False(t, ok)
+	_, ok = server.Object("images", other)
+	require.True(t, ok)
+	_, ok = server.Object("images", "README")
+	require.True(t, ok)
+
+	image, err := store.Find(imageID)
+	require.NoError(t, err)
+	_, ok = server.Object("images", image.Path)
+	require.True(t, ok)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCode(t *testing.T) {
+	t.Parallel()
+
+	// RFC 6238 부록 B의 SHA1 test vector에서 하위 6자리를 사용한다
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range vectors {
+		code, err := TOTPCode(secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, expected, code, unix)
+	}
+}
+
+func TestVerifySecondFactor(t *testing.T) {
+	t.Parallel()
+
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+	require.Contains(t, TOTPProvisioningURI(secret, "alice"), "otpauth://totp/pcbook:alice?")
+
+	codes, hashes, err := NewRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+
+	user := &User{Username: "alice", TOTPSecret: secret, RecoveryCodes: hashes}
+	now := time.Now()
+
+	// 한 step 전의 code도 받지만 이미 사용한 code는 다시 받지 않는다
+	code, err := TOTPCode(secret, now.Add(-totpPeriod))
+	require.NoError(t, err)
+	require.True(t, user.VerifySecondFactor(code, now))
+	require.False(t, user.VerifySecondFactor(code, now))
+
+	code, err = TOTPCode(secret, now.Add(-3*totpPeriod))
+	require.NoError(t, err)
+	require.False(t, user.VerifySecondFactor(code, now))
+
+	// recovery code는 대소문자와 '-'를 무시하고 한 번만 쓸 수 있다
+	require.True(t, user.VerifySecondFactor(" "+codes[0]+" ", now))
+	require.False(t, user.VerifySecondFactor(codes[0], now))
+	require.Len(t, user.RecoveryCodes, recoveryCodeCount-1)
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOffsetMismatch = errors.New("chunk offset does not match committed offset")
+	ErrUploadOverflow = errors.New("chunk exceeds declared image size")
+)
+
+type UploadSessionStore interface {
+	Create(orgID string, image *ImageInfo, checksum string, totalSize uint32) (*UploadSession, error)
+	Find(uploadID string) (*UploadSession, error)
+	Append(uploadID string, offset uint32, chunk []byte) (*UploadSession, error)
+	Read(uploadID string) (bytes.Buffer, error)
+	Delete(uploadID string) error
+}
+
+// OrgID는 upload를 시작한 호출자의 organization이며 다른 organization은 이 upload를 볼 수 없다
+type UploadSession struct {
+	ID        string
+	OrgID     string
+	Image     *ImageInfo
+	Checksum  string
+	TotalSize uint32
+	Offset    uint32
+	Path      string
+	ExpiresAt time.Time
+}
+
+// DiskUploadSessionStore는 업로드 중인 이미지를 uploadFolder의 임시 파일에 이어서 기록한다
+type DiskUploadSessionStore struct {
+	mutax        sync.RWMutex
+	uploadFolder string
+	ttl          time.Duration
+	sessions     map[string]*UploadSession
+}
+
+func NewDiskUploadSessionStore(uploadFolder string, ttl time.Duration) *DiskUploadSessionStore {
+	return &DiskUploadSessionStore{
+		uploadFolder: uploadFolder,
+		ttl:          ttl,
+		sessions:     make(map[string]*UploadSession),
+	}
+}
+
+func (store *DiskUploadSessionStore) Create(orgID string, image *ImageInfo, checksum string, totalSize uint32) (*UploadSession, error) {
+	uploadID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload id: %w", err)
+	}
+
+	err = os.MkdirAll(store.uploadFolder, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload folder: %w", err)
+	}
+
+	session := &UploadSession{
+		ID:        uploadID.String(),
+		OrgID:     orgOrDefault(orgID),
+		Image:     image.Clone(),
+		Checksum:  checksum,
+		TotalSize: totalSize,
+		Path:      filepath.Join(store.uploadFolder, uploadID.String()+".part"),
+		ExpiresAt: time.Now().Add(store.ttl),
+	}
+
+	file, err := os.Create(session.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	file.Close()
+
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	store.sessions[session.ID] = session
+	return session.Clone(), nil
+}
+
+func (store *DiskUploadSessionStore) Find(uploadID string) (*UploadSession, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	session := store.sessions[uploadID]
+	if session == nil || session.expired(time.Now()) {
+		return nil, nil
+	}
+
+	return session.Clone(), nil
+}
+
+// Append는 offset이 지금까지 기록된 크기와 같을 때만 chunk를 기록한다
+func (store *DiskUploadSessionStore) Append(uploadID string, offset uint32, chunk []byte) (*UploadSession, error) {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	session := store.sessions[uploadID]
+	if session == nil || session.expired(time.Now()) {
+		return nil, ErrNotFound
+	}
+
+	if offset != session.Offset {
+		return session.Clone(), ErrOffsetMismatch
+	}
+
+	if uint64(offset)+uint64(len(chunk)) > uint64(session.TotalSize) {
+		return nil, fmt.Errorf("%w: %d > %d", ErrUploadOverflow, uint64(offset)+uint64(len(chunk)), session.TotalSize)
+	}
+
+	file, err := os.OpenFile(session.Path, os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("can not open upload file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteAt(chunk, int64(offset))
+	if err != nil {
+		return nil, fmt.Errorf("can not write chunk to file: %w", err)
+	}
+
+	session.Offset += uint32(len(chunk))
+	return session.Clone(), nil
+}
+
+func (store *DiskUploadSessionStore) Read(uploadID string) (bytes.Buffer, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	imageData := bytes.Buffer{}
+
+	session := store.sessions[uploadID]
+	if session == nil {
+		return imageData, ErrNotFound
+	}
+
+	data, err := os.ReadFile(session.Path)
+	if err != nil {
+		return imageData, fmt.Errorf("can not read upload file: %w", err)
+	}
+
+	imageData.Write(data[:session.Offset])
+	return imageData, nil
+}
+
+func (store *DiskUploadSessionStore) Delete(uploadID string) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	session := store.sessions[uploadID]
+	if session == nil {
+		return ErrNotFound
+	}
+	delete(store.sessions, uploadID)
+
+	return removeUploadFile(session.Path)
+}
+
+// RemoveExpired는 만료된 세션과 세션이 없는 오래된 임시 파일을 삭제한다
+func (store *DiskUploadSessionStore) RemoveExpired() (int, error) {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	now := time.Now()
+	removed := 0
+	live := make(map[string]bool)
+
+	for id, session := range store.sessions {
+		if !session.expired(now) {
+			live[session.Path] = true
+			continue
+		}
+
+		delete(store.sessions, id)
+		if err := removeUploadFile(session.Path); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	entries, err := os.ReadDir(store.uploadFolder)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return removed, nil
+		}
+		return removed, fmt.Errorf("can not read upload folder: %w", err)
+	}
+
+	// 서버 재시작으로 세션 정보가 사라진 파일
+	for _, entry := range entries {
+		path := filepath.Join(store.uploadFolder, entry.Name())
+		if entry.IsDir() || live[path] {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < store.ttl {
+			continue
+		}
+
+		if err := removeUploadFile(path); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+func (store *DiskUploadSessionStore) StartCleanup(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				removed, err := store.RemoveExpired()
+				if err != nil {
+					log.Println("upload cleanup error:", err)
+					continue
+				}
+				if removed > 0 {
+					log.Printf("removed %d expired uploads", removed)
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (session *UploadSession) Clone() *UploadSession {
+	other := *session
+	other.Image = session.Image.Clone()
+	return &other
+}
+
+func (session *UploadSession) expired(now time.Time) bool {
+	return now.After(session.ExpiresAt)
+}
+
+func removeUploadFile(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("can not remove upload file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskUploadSessionStoreRemoveExpired(t *testing.T) {
+	t.Parallel()
+
+	store := NewDiskUploadSessionStore(t.TempDir(), time.Hour)
+
+	expired, err := store.Create(DefaultOrgID, &ImageInfo{LaptopID: "laptop-1", Type: ".png"}, "", 10)
+	require.NoError(t, err)
+	live, err := store.Create(DefaultOrgID, &ImageInfo{LaptopID: "laptop-1", Type: ".png"}, "", 10)
+	require.NoError(t, err)
+
+	_, err = store.Append(expired.ID, 0, []byte("12345"))
+	require.NoError(t, err)
+
+	store.sessions[expired.ID].ExpiresAt = time.Now().Add(-time.Minute)
+
+	_, err = store.Append(expired.ID, 5, []byte("67890"))
+	require.ErrorIs(t, err, ErrNotFound)
+
+	removed, err := store.RemoveExpired()
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	require.NoFileExists(t, expired.Path)
+	require.FileExists(t, live.Path)
+
+	session, err := store.Find(expired.ID)
+	require.NoError(t, err)
+	require.Nil(t, session)
+}
@@ -1,15 +1,32 @@
 package service
 
 import (
+	"crypto/subtle"
 	"fmt"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// CreatedAt, UpdatedAt은 UserStore가 저장할 때 설정한다
+// PendingTOTPSecret은 code를 한 번 확인해야 TOTPSecret이 되어 2단계 인증이 켜진다
+// RecoveryCodes는 아직 사용하지 않은 recovery code의 hash이다
+// OrgID는 사용자가 속한 organization이며 비어 있으면 default organization이다
+// Version은 UserStore가 Update할 때마다 올리며 그 사이 다른 요청이 바꾼 사용자는 저장하지 않는다
 type User struct {
-	Username       string
-	HashedPassword string
-	Role           string
+	Username          string
+	HashedPassword    string
+	Role              string
+	OrgID             string
+	Disabled          bool
+	TOTPSecret        string
+	PendingTOTPSecret string
+	TOTPLastStep      int64
+	RecoveryCodes     []string
+	Version           uint64
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
 
 func NewUser(username, password, role string) (*User, error) {
@@ -18,7 +35,17 @@ func NewUser(username, password, role string) (*User, error) {
 		return nil, fmt.Errorf("failed to create hash password: %w", err)
 	}
 
-	return &User{Username: username, HashedPassword: string(hashedPassword), Role: role}, nil
+	return &User{Username: username, HashedPassword: string(hashedPassword), Role: role, OrgID: DefaultOrgID}, nil
+}
+
+func (user *User) SetPassword(password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to create hash password: %w", err)
+	}
+
+	user.HashedPassword = string(hashedPassword)
+	return nil
 }
 
 func (user *User) IsCorrectPassword(password string) bool {
@@ -26,10 +53,39 @@ func (user *User) IsCorrectPassword(password string) bool {
 	return err == nil
 }
 
-func (user *User) Clon() *User {
-	return &User{
-		Username:       user.Username,
-		HashedPassword: user.HashedPassword,
-		Role:           user.Role,
+func (user *User) Org() string {
+	return orgOrDefault(user.OrgID)
+}
+
+func (user *User) TwoFactorEnabled() bool {
+	return user.TOTPSecret != ""
+}
+
+// VerifySecondFactor는 TOTP code나 recovery code를 확인한다
+// 확인에 성공하면 사용한 code를 다시 쓸 수 없도록 user를 바꾸므로 저장해야 한다
+func (user *User) VerifySecondFactor(code string, now time.Time) bool {
+	if !user.TwoFactorEnabled() {
+		return false
 	}
+
+	code = strings.TrimSpace(code)
+	if step, ok := verifyTOTP(user.TOTPSecret, code, now, user.TOTPLastStep); ok {
+		user.TOTPLastStep = step
+		return true
+	}
+
+	hash := hashRecoveryCode(code)
+	for i, other := range user.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(other)) == 1 {
+			user.RecoveryCodes = append(user.RecoveryCodes[:i:i], user.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (user *User) Clon() *User {
+	other := *user
+	other.RecoveryCodes = append([]string(nil), user.RecoveryCodes...)
+	return &other
 }
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 500
+)
+
+// SuperAdminRole은 owner가 아니어도 모든 laptop을 수정할 수 있으며 SetUserRole로 지정할 수 없다
+const SuperAdminRole = "superadmin"
+
+// UserRoles는 SetUserRole로 지정할 수 있는 role이다
+var UserRoles = map[string]bool{
+	"admin": true,
+	"user":  true,
+}
+
+// UserAdminServer의 rpc는 관리자만 호출할 수 있으며
+// 관리자가 자기 자신을 비활성화, 삭제하거나 role을 바꿀 수는 없다
+// super-admin이 아닌 관리자에게는 자기 organization의 사용자와 api key만 보인다
+// TokenDuration은 RevokeUserSessions가 access token 폐기 기록을 유지할 기간이다
+type UserAdminServer struct {
+	pb.UnimplementedUserAdminServiceServer
+	UserStore     UserStore
+	RefreshTokens RefreshTokenStore
+	Revocations   RevocationStore
+	APIKeys       APIKeyStore
+	LoginThrottle *LoginThrottle
+	TokenDuration time.Duration
+
+	// Policy는 CreateApiKey가 api key role이 호출할 수 있는 rpc인지 확인할 때 현재 rbac policy를 얻는다
+	Policy func() *Policy
+}
+
+func NewUserAdminServer(userStore UserStore, refreshTokens RefreshTokenStore, revocations RevocationStore, apiKeys APIKeyStore) *UserAdminServer {
+	return &UserAdminServer{
+		UserStore:     userStore,
+		RefreshTokens: refreshTokens,
+		Revocations:   revocations,
+		APIKeys:       apiKeys,
+		TokenDuration: TokenDuration,
+	}
+}
+
+func (server *UserAdminServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	offset, err := parsePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	users, total, err := server.UserStore.List(scopeOrg(ctx), offset, pageSize(req.GetPageSize(), defaultUserPageSize, maxUserPageSize))
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not list users: %v", err))
+	}
+
+	res := &pb.ListUsersResponse{
+		Users:         make([]*pb.UserInfo, 0, len(users)),
+		NextPageToken: nextPageToken(offset, len(users), total),
+		TotalCount:    uint32(total),
+	}
+	for _, user := range users {
+		res.Users = append(res.Users, toPbUserInfo(user))
+	}
+
+	return res, nil
+}
+
+func (server *UserAdminServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	user, err := server.findUser(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetUserResponse{User: toPbUserInfo(user)}, nil
+}
+
+func (server *UserAdminServer) SetUserRole(ctx context.Context, req *pb.SetUserRoleRequest) (*pb.SetUserRoleResponse, error) {
+	if !UserRoles[req.GetRole()] {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "unknown role: %s", req.GetRole()))
+	}
+
+	user, err := server.updateUser(ctx, req.GetUsername(), "change role of", func(user *User) {
+		user.Role = req.GetRole()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SetUserRoleResponse{User: toPbUserInfo(user)}, nil
+}
+
+func (server *UserAdminServer) DisableUser(ctx context.Context, req *pb.DisableUserRequest) (*pb.DisableUserResponse, error) {
+	user, err := server.updateUser(ctx, req.GetUsername(), "disable", func(user *User) {
+		user.Disabled = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DisableUserResponse{User: toPbUserInfo(user)}, nil
+}
+
+func (server *UserAdminServer) EnableUser(ctx context.Context, req *pb.EnableUserRequest) (*pb.EnableUserResponse, error) {
+	user, err := server.updateUser(ctx, req.GetUsername(), "enable", func(user *User) {
+		user.Disabled = false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.EnableUserResponse{User: toPbUserInfo(user)}, nil
+}
+
+func (server *UserAdminServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	username, err := server.checkTarget(ctx, req.GetUsername(), "delete")
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.UserStore.Delete(username)
+	if err != nil {
+		return nil, logErr(userStoreError(err, "can not delete user"))
+	}
+
+	// 같은 username으로 다시 가입한 계정에 이전 token이 통하지 않도록 세션도 폐기한다
+	err = revokeSessions(server.Revocations, server.RefreshTokens, username, server.TokenDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("deleted user: %s", username)
+	return &pb.DeleteUserResponse{}, nil
+}
+
+// RevokeUserSessions는 지금까지 발급된 사용자의 access token과 refresh token을 모두 폐기한다
+// 사용자는 다시 로그인해야 한다
+func (server *UserAdminServer) RevokeUserSessions(ctx context.Context, req *pb.RevokeUserSessionsRequest) (*pb.RevokeUserSessionsResponse, error) {
+	user, err := server.findUser(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+
+	err = revokeSessions(server.Revocations, server.RefreshTokens, user.Username, server.TokenDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("revoked sessions of user: %s", user.Username)
+	return &pb.RevokeUserSessionsResponse{}, nil
+}
+
+// revokeSessions는 username에게 지금까지 발급된 access token과 refresh token을 모두 폐기한다
+// access token 폐기 기록은 tokenDuration 동안 유지한다
+func revokeSessions(revocations RevocationStore, refreshTokens RefreshTokenStore, username string, tokenDuration time.Duration) error {
+	now := time.Now()
+	err := revocations.RevokeUser(username, now, now.Add(tokenDuration))
+	if err != nil {
+		return logErr(status.Errorf(codes.Internal, "can not revoke access tokens: %v", err))
+	}
+
+	err = refreshTokens.RevokeUser(username)
+	if err != nil {
+		return logErr(status.Errorf(codes.Internal, "can not revoke refresh tokens: %v", err))
+	}
+	return nil
+}
+
+// UnlockUser는 로그인 실패로 잠긴 계정을 바로 풀어 준다
+// 없는 username도 잠길 수 있으므로 사용자가 있는지는 확인하지 않는다
+func (server *UserAdminServer) UnlockUser(ctx context.Context, req *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error) {
+	username, err := NormalizeUsername(req.GetUsername())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+	if server.LoginThrottle == nil {
+		return nil, logErr(status.Errorf(codes.FailedPrecondition, "login throttling is not enabled"))
+	}
+
+	err = server.LoginThrottle.Unlock(username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not unlock user: %v", err))
+	}
+
+	log.Printf("unlocked login of user: %s", username)
+	return &pb.UnlockUserResponse{}, nil
+}
+
+// CreateApiKey는 device agent용 key를 만든다
+// key는 hash만 저장하므로 응답으로 한 번만 보여준다
+func (server *UserAdminServer) CreateApiKey(ctx context.Context, req *pb.CreateApiKeyRequest) (*pb.CreateApiKeyResponse, error) {
+	name := strings.TrimSpace(req.GetName())
+	if name == "" {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "api key name must not be empty"))
+	}
+	if len(req.GetMethods()) == 0 {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "api key must be scoped to at least one rpc"))
+	}
+	for _, method := range req.GetMethods() {
+		if strings.Count(method, "/") != 2 || !strings.HasPrefix(method, "/") || strings.Contains(method, "*") {
+			return nil, logErr(status.Errorf(codes.InvalidArgument, "invalid rpc %q, must be like /package.Service/Method", method))
+		}
+	}
+	if err := server.checkAPIKeyMethods(req.GetMethods()); err != nil {
+		return nil, err
+	}
+	for _, laptopID := range req.GetLaptopIds() {
+		if strings.TrimSpace(laptopID) == "" {
+			return nil, logErr(status.Errorf(codes.InvalidArgument, "laptop id must not be empty"))
+		}
+	}
+
+	principal := PrincipalFromContext(ctx)
+	createdBy := ""
+	if principal != nil {
+		createdBy = principal.Username
+	}
+
+	value, key, err := NewAPIKey(name, req.GetMethods(), req.GetLaptopIds(), createdBy)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "%v", err))
+	}
+	key.OrgID = principal.Org()
+	err = server.APIKeys.Create(key)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not save api key: %v", err))
+	}
+
+	log.Printf("created api key %s (%s) by %s", key.ID, key.Name, createdBy)
+	return &pb.CreateApiKeyResponse{ApiKey: toPbApiKey(key), Key: value}, nil
+}
+
+// checkAPIKeyMethods는 api key role이 현재 policy에서 호출할 수 없는 rpc를 scope에 넣지 못하게 한다
+func (server *UserAdminServer) checkAPIKeyMethods(methods []string) error {
+	if server.Policy == nil {
+		return nil
+	}
+
+	policy := server.Policy()
+	for _, method := range methods {
+		permission, ok := policy.Permission(method)
+		if !ok {
+			return logErr(status.Errorf(codes.InvalidArgument, "rpc %s is not allowed by the policy", method))
+		}
+		if permission != PublicPermission && !policy.HasPermission(APIKeyRole, permission) {
+			return logErr(status.Errorf(codes.InvalidArgument, "api key role %s has no permission %s to call %s", APIKeyRole, permission, method))
+		}
+	}
+	return nil
+}
+
+func (server *UserAdminServer) ListApiKeys(ctx context.Context, req *pb.ListApiKeysRequest) (*pb.ListApiKeysResponse, error) {
+	keys, err := server.APIKeys.List()
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not list api keys: %v", err))
+	}
+
+	principal := PrincipalFromContext(ctx)
+	res := &pb.ListApiKeysResponse{}
+	for _, key := range keys {
+		if !principal.CanAccessOrg(key.OrgID) {
+			continue
+		}
+		res.ApiKeys = append(res.ApiKeys, toPbApiKey(key))
+	}
+	return res, nil
+}
+
+func (server *UserAdminServer) RevokeApiKey(ctx context.Context, req *pb.RevokeApiKeyRequest) (*pb.RevokeApiKeyResponse, error) {
+	key, err := server.APIKeys.Find(req.GetId())
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find api key: %v", err))
+	}
+	if key == nil || !PrincipalFromContext(ctx).CanAccessOrg(key.OrgID) {
+		return nil, logErr(status.Errorf(codes.NotFound, "api key %s is not found", req.GetId()))
+	}
+
+	key, err = server.APIKeys.Revoke(req.GetId())
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, ErrNotFound) {
+			code = codes.NotFound
+		}
+		return nil, logErr(status.Errorf(code, "can not revoke api key: %v", err))
+	}
+
+	log.Printf("revoked api key %s (%s)", key.ID, key.Name)
+	return &pb.RevokeApiKeyResponse{ApiKey: toPbApiKey(key)}, nil
+}
+
+// findUser는 다른 organization의 사용자를 없는 사용자처럼 다룬다
+func (server *UserAdminServer) findUser(ctx context.Context, username string) (*User, error) {
+	username, err := NormalizeUsername(username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+
+	user, err := server.UserStore.Find(username)
+	if err != nil {
+		return nil, logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	if user == nil || !PrincipalFromContext(ctx).CanAccessOrg(user.Org()) {
+		return nil, logErr(status.Errorf(codes.NotFound, "user %s is not found", username))
+	}
+
+	return user, nil
+}
+
+// checkTarget은 username을 정규화하고 요청한 관리자 자신이 아닌지 확인한다
+func (server *UserAdminServer) checkTarget(ctx context.Context, username string, action string) (string, error) {
+	username, err := NormalizeUsername(username)
+	if err != nil {
+		return "", logErr(status.Errorf(codes.InvalidArgument, "%v", err))
+	}
+
+	principal := PrincipalFromContext(ctx)
+	if principal != nil && principal.Username == username {
+		return "", logErr(status.Errorf(codes.FailedPrecondition, "can not %s yourself", action))
+	}
+
+	// super-admin 계정은 super-admin만 바꿀 수 있다
+	user, err := server.UserStore.Find(username)
+	if err != nil {
+		return "", logErr(status.Errorf(codes.Internal, "can not find user: %v", err))
+	}
+	if user != nil && !principal.CanAccessOrg(user.Org()) {
+		return "", logErr(status.Errorf(codes.NotFound, "user %s is not found", username))
+	}
+	if user != nil && user.Role == SuperAdminRole && (principal == nil || principal.Role != SuperAdminRole) {
+		return "", logErr(status.Errorf(codes.PermissionDenied, "can not %s a super-admin", action))
+	}
+	return username, nil
+}
+
+func (server *UserAdminServer) updateUser(ctx context.Context, username string, action string, update func(user *User)) (*User, error) {
+	username, err := server.checkTarget(ctx, username, action)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := server.findUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	update(user)
+	err = server.UserStore.Update(user)
+	if err != nil {
+		return nil, logErr(userStoreError(err, "can not update user"))
+	}
+
+	log.Printf("%s user: %s", action, username)
+	return server.findUser(ctx, username)
+}
+
+// scopeOrg는 호출자가 볼 수 있는 사용자의 organization을 반환하며 super-admin이면 모든 organization을 뜻하는 ""를 반환한다
+func scopeOrg(ctx context.Context) string {
+	principal := PrincipalFromContext(ctx)
+	if principal != nil && principal.Role == SuperAdminRole {
+		return ""
+	}
+	return principal.Org()
+}
+
+func userStoreError(err error, message string) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, ErrStaleRecord):
+		code = codes.Aborted
+	}
+	return status.Errorf(code, "%s: %v", message, err)
+}
+
+func toPbUserInfo(user *User) *pb.UserInfo {
+	return &pb.UserInfo{
+		Username:  user.Username,
+		Role:      user.Role,
+		Disabled:  user.Disabled,
+		OrgId:     user.Org(),
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+	}
+}
+
+func toPbApiKey(key *APIKey) *pb.ApiKey {
+	other := &pb.ApiKey{
+		Id:        key.ID,
+		Name:      key.Name,
+		Methods:   key.Methods,
+		LaptopIds: key.LaptopIDs,
+		CreatedBy: key.CreatedBy,
+		Revoked:   key.Revoked,
+		CreatedAt: timestamppb.New(key.CreatedAt),
+	}
+	if key.Revoked {
+		other.RevokedAt = timestamppb.New(key.RevokedAt)
+	}
+	return other
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/JeongWoo-Seo/pcBook/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUserAdminServer(t *testing.T) {
+	t.Parallel()
+
+	userStore := NewInMemoryUserStore()
+	for _, user := range []struct{ username, role string }{{"admin", "admin"}, {"alice", "user"}, {"bob", "user"}, {"root", SuperAdminRole}} {
+		newUser, err := NewUser(user.username, "secret-password", user.role)
+		require.NoError(t, err)
+		require.NoError(t, userStore.Save(newUser))
+	}
+
+	tokenManager := NewPasetoManager(TokenKey, TokenDuration)
+	refreshTokens := NewInMemoryRefreshTokenStore()
+	revocations := NewInMemoryRevocationStore()
+	apiKeys := NewInMemoryAPIKeyStore()
+	policy, err := ParsePolicy([]byte(`{
+		"roles": {
+			"user": {"permissions": ["account.manage"]},
+			"admin": {"inherits": ["user"], "permissions": ["user.admin"]},
+			"device": {"permissions": ["laptop.report"]}
+		},
+		"rules": {
+			"/pcbook.LaptopService/SendLaptopInfo": "laptop.report",
+			"/pcbook.AuthService/ChangePassword": "account.manage",
+			"/pcbook.AuthService/Logout": "account.manage",
+			"/pcbook.AuthService/*": "public",
+			"/pcbook.UserAdminService/*": "user.admin"
+		}
+	}`))
+	require.NoError(t, err)
+	interceptor := NewAuthInterceptor(tokenManager, userStore, revocations, apiKeys, policy)
+
+	authServer := NewAuthServer(userStore, tokenManager, refreshTokens, revocations)
+	userAdminServer := NewUserAdminServer(userStore, refreshTokens, revocations, apiKeys)
+	userAdminServer.LoginThrottle = authServer.LoginThrottle
+	userAdminServer.Policy = interceptor.Policy
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(interceptor.Unary()))
+	pb.RegisterAuthServiceServer(grpcServer, authServer)
+	pb.RegisterUserAdminServiceServer(grpcServer, userAdminServer)
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	authClient := pb.NewAuthServiceClient(conn)
+	adminClient := pb.NewUserAdminServiceClient(conn)
+
+	login := func(username string) context.Context {
+		res, err := authClient.Login(context.Background(), &pb.LoginRequest{Username: username, Password: "secret-password"})
+		require.NoError(t, err)
+		return metadata.AppendToOutgoingContext(context.Background(), authorizationHeader, "Bearer "+res.GetAccessToken())
+	}
+	adminCtx := login("admin")
+	aliceCtx := login("alice")
+
+	list, err := adminClient.ListUsers(adminCtx, &pb.ListUsersRequest{PageSize: 2})
+	require.NoError(t, err)
+	require.Equal(t, uint32(4), list.GetTotalCount())
+	require.Equal(t, "admin", list.GetUsers()[0].GetUsername())
+	require.NotEmpty(t, list.GetNextPageToken())
+
+	_, err = adminClient.ListUsers(aliceCtx, &pb.ListUsersRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = adminClient.SetUserRole(adminCtx, &pb.SetUserRoleRequest{Username: "bob", Role: "root"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = adminClient.DisableUser(adminCtx, &pb.DisableUserRequest{Username: "admin"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = adminClient.SetUserRole(adminCtx, &pb.SetUserRoleRequest{Username: "root", Role: "user"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// 이미 발급된 token도 role 변경과 비활성화가 바로 적용된다
+	role, err := adminClient.SetUserRole(adminCtx, &pb.SetUserRoleRequest{Username: "alice", Role: "admin"})
+	require.NoError(t, err)
+	require.Equal(t, "admin", role.GetUser().GetRole())
+
+	_, err = adminClient.ListUsers(aliceCtx, &pb.ListUsersRequest{})
+	require.NoError(t, err)
+
+	disabled, err := adminClient.DisableUser(adminCtx, &pb.DisableUserRequest{Username: "alice"})
+	require.NoError(t, err)
+	require.True(t, disabled.GetUser().GetDisabled())
+
+	_, err = adminClient.ListUsers(aliceCtx, &pb.ListUsersRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = authClient.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = adminClient.EnableUser(adminCtx, &pb.EnableUserRequest{Username: "alice"})
+	require.NoError(t, err)
+	alice, err := authClient.Login(context.Background(), &pb.LoginRequest{Username: "alice", Password: "secret-password"})
+	require.NoError(t, err)
+
+	_, err = adminClient.DeleteUser(adminCtx, &pb.DeleteUserRequest{Username: "alice"})
+	require.NoError(t, err)
+
+	_, err = adminClient.ListUsers(aliceCtx, &pb.ListUsersRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = adminClient.DeleteUser(adminCtx, &pb.DeleteUserRequest{Username: "alice"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// 삭제된 username으로 다시 가입해도 삭제 전에 발급된 token은 쓸 수 없다
+	_, err = authClient.Register(context.Background(), &pb.RegisterRequest{Username: "alice", Password: "other-password"})
+	require.NoError(t, err)
+
+	_, err = authClient.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: alice.GetRefreshToken()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	oldAliceCtx := metadata.AppendToOutgoingContext(context.Background(), authorizationHeader, "Bearer "+alice.GetAccessToken())
+	_, err = authClient.Logout(oldAliceCtx, &pb.LogoutRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// logout한 token은 만료 전이라도 더 이상 사용할 수 없다
+	bob, err := authClient.Login(context.Background(), &pb.LoginRequest{Username: "bob", Password: "secret-password"})
+	require.NoError(t, err)
+	bobCtx := metadata.AppendToOutgoingContext(context.Background(), authorizationHeader, "Bearer "+bob.GetAccessToken())
+	otherCtx := login("bob")
+
+	_, err = authClient.Logout(bobCtx, &pb.LogoutRequest{RefreshToken: "unknown"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = authClient.Logout(bobCtx, &pb.LogoutRequest{RefreshToken: bob.GetRefreshToken()})
+	require.NoError(t, err)
+
+	_, err = authClient.Logout(bobCtx, &pb.LogoutRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = authClient.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: bob.GetRefreshToken()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// 관리자가 세션을 폐기하면 다른 로그인에서 받은 token도 모두 거부된다
+	_, err = adminClient.RevokeUserSessions(adminCtx, &pb.RevokeUserSessionsRequest{Username: "bob"})
+	require.NoError(t, err)
+
+	_, err = authClient.Logout(otherCtx, &pb.LogoutRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = adminClient.RevokeUserSessions(adminCtx, &pb.RevokeUserSessionsRequest{Username: "nobody"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// 로그인 실패로 잠긴 계정은 관리자가 바로 풀 수 있다
+	for i := 0; i <= DefaultUserBackoff.FreeAttempts; i++ {
+		_, err = authClient.Login(context.Background(), &pb.LoginRequest{Username: "bob", Password: "wrong-password"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+	_, err = authClient.Login(context.Background(), &pb.LoginRequest{Username: "bob", Password: "secret-password"})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = adminClient.UnlockUser(adminCtx, &pb.UnlockUserRequest{Username: "bob"})
+	require.NoError(t, err)
+	login("bob")
+
+	// api key는 생성할 때만 key를 보여주고 hash만 저장한다
+	_, err = adminClient.CreateApiKey(adminCtx, &pb.CreateApiKeyRequest{Name: "agent"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = adminClient.CreateApiKey(adminCtx, &pb.CreateApiKeyRequest{Name: "agent", Methods: []string{"/pcbook.LaptopService/*"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// api key role이 policy에서 호출할 수 없는 rpc는 scope에 넣을 수 없다
+	_, err = adminClient.CreateApiKey(adminCtx, &pb.CreateApiKeyRequest{Name: "agent", Methods: []string{"/pcbook.UserAdminService/ListUsers"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = adminClient.CreateApiKey(adminCtx, &pb.CreateApiKeyRequest{Name: "agent", Methods: []string{"/pcbook.LaptopService/SearchLaptop"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	created, err := adminClient.CreateApiKey(adminCtx, &pb.CreateApiKeyRequest{
+		Name:      "agent",
+		Methods:   []string{"/pcbook.LaptopService/SendLaptopInfo"},
+		LaptopIds: []string{"serial-1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "admin", created.GetApiKey().GetCreatedBy())
+	require.NotEmpty(t, created.GetKey())
+
+	stored, err := apiKeys.Find(created.GetApiKey().GetId())
+	require.NoError(t, err)
+	require.NotContains(t, stored.Hash, created.GetKey())
+	require.True(t, stored.Verify(created.GetKey()))
+
+	keys, err := adminClient.ListApiKeys(adminCtx, &pb.ListApiKeysRequest{})
+	require.NoError(t, err)
+	require.Len(t, keys.GetApiKeys(), 1)
+
+	revoked, err := adminClient.RevokeApiKey(adminCtx, &pb.RevokeApiKeyRequest{Id: created.GetApiKey().GetId()})
+	require.NoError(t, err)
+	require.True(t, revoked.GetApiKey().GetRevoked())
+
+	_, err = adminClient.RevokeApiKey(adminCtx, &pb.RevokeApiKeyRequest{Id: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
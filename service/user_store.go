@@ -1,10 +1,17 @@
 package service
 
-import "sync"
+import (
+	"sort"
+	"sync"
+	"time"
+)
 
 type UserStore interface {
 	Save(user *User) error
 	Find(username string) (*User, error)
+	Update(user *User) error
+	List(orgID string, offset int, limit int) ([]*User, int, error)
+	Delete(username string) error
 }
 
 type InmemoryUserStore struct {
@@ -26,7 +33,10 @@ func (store *InmemoryUserStore) Save(user *User) error {
 		return ErrAlreadyExists
 	}
 
-	store.users[user.Username] = user.Clon()
+	other := user.Clon()
+	other.CreatedAt = time.Now()
+	other.UpdatedAt = other.CreatedAt
+	store.users[user.Username] = other
 	return nil
 }
 
@@ -41,3 +51,67 @@ func (store *InmemoryUserStore) Find(username string) (*User, error) {
 
 	return user.Clon(), nil
 }
+
+// Update는 user.Version이 저장된 사용자와 같을 때만 저장하고 user.Version을 올린다
+// 읽은 뒤 다른 요청이 사용자를 바꿨으면 ErrStaleRecord를 반환하므로 다시 읽어서 바꿔야 한다
+func (store *InmemoryUserStore) Update(user *User) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	previous := store.users[user.Username]
+	if previous == nil {
+		return ErrNotFound
+	}
+	if previous.Version != user.Version {
+		return ErrStaleRecord
+	}
+
+	user.Version++
+	other := user.Clon()
+	other.CreatedAt = previous.CreatedAt
+	other.UpdatedAt = time.Now()
+	store.users[user.Username] = other
+	return nil
+}
+
+// List는 username 순으로 정렬한 사용자 중 요청한 범위와 전체 수를 반환한다
+// orgID가 비어 있으면 모든 organization의 사용자를, limit이 0이면 끝까지 반환한다
+func (store *InmemoryUserStore) List(orgID string, offset int, limit int) ([]*User, int, error) {
+	store.mutax.RLock()
+	defer store.mutax.RUnlock()
+
+	usernames := make([]string, 0, len(store.users))
+	for username, user := range store.users {
+		if orgID != "" && user.Org() != orgID {
+			continue
+		}
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	total := len(usernames)
+	start := min(offset, total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+
+	users := make([]*User, 0, end-start)
+	for _, username := range usernames[start:end] {
+		users = append(users, store.users[username].Clon())
+	}
+
+	return users, total, nil
+}
+
+func (store *InmemoryUserStore) Delete(username string) error {
+	store.mutax.Lock()
+	defer store.mutax.Unlock()
+
+	if store.users[username] == nil {
+		return ErrNotFound
+	}
+
+	delete(store.users, username)
+	return nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryUserStoreUpdateStale(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryUserStore()
+	user, err := NewUser("alice", "secret-password", "user")
+	require.NoError(t, err)
+	require.NoError(t, store.Save(user))
+
+	// 같은 사용자를 읽은 두 요청 중 나중에 저장하는 요청은 앞의 변경을 덮어쓰지 못한다
+	first, err := store.Find("alice")
+	require.NoError(t, err)
+	second, err := store.Find("alice")
+	require.NoError(t, err)
+
+	first.Disabled = true
+	require.NoError(t, store.Update(first))
+
+	require.NoError(t, second.SetPassword("new-password"))
+	require.ErrorIs(t, store.Update(second), ErrStaleRecord)
+
+	saved, err := store.Find("alice")
+	require.NoError(t, err)
+	require.True(t, saved.Disabled)
+	require.True(t, saved.IsCorrectPassword("secret-password"))
+
+	// 저장한 객체는 version이 올라가므로 이어서 다시 저장할 수 있다
+	first.Role = "admin"
+	require.NoError(t, store.Update(first))
+
+	require.ErrorIs(t, store.Update(&User{Username: "bob"}), ErrNotFound)
+}